@@ -2,7 +2,10 @@
 package database
 
 import (
+	"os"
+
 	"github.com/MangriMen/Diverse-Back/internal/queries"
+	"github.com/MangriMen/Diverse-Back/internal/search"
 )
 
 // Queries is struct for storing various queries.
@@ -11,6 +14,21 @@ type Queries struct {
 	*queries.RelationQueries
 	*queries.PostQueries
 	*queries.CommentQueries
+	*queries.SearchQueries
+	*queries.FeedQueries
+	*queries.TimelineQueries
+	*queries.ViewQueries
+	*queries.PollQueries
+	*queries.MutedWordQueries
+	*queries.NotificationQueries
+	*queries.RealtimeQueries
+	*queries.PushQueries
+	*queries.NotificationPreferenceQueries
+	*queries.ConversationQueries
+	*queries.MessageQueries
+	*queries.MessageAttachmentQueries
+
+	Index search.Index
 }
 
 // OpenDBConnection open db connection and combine all queries.
@@ -21,10 +39,29 @@ func OpenDBConnection() (*Queries, error) {
 		return nil, err
 	}
 
+	index, err := search.Open(search.Backend(os.Getenv("SEARCH_BACKEND")), db)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Queries{
-		UserQueries:     &queries.UserQueries{DB: db},
-		RelationQueries: &queries.RelationQueries{DB: db},
-		PostQueries:     &queries.PostQueries{DB: db},
-		CommentQueries:  &queries.CommentQueries{DB: db},
+		UserQueries:                   &queries.UserQueries{DB: db, Index: index},
+		RelationQueries:               &queries.RelationQueries{DB: db},
+		PostQueries:                   &queries.PostQueries{DB: db, Index: index},
+		CommentQueries:                &queries.CommentQueries{DB: db},
+		SearchQueries:                 &queries.SearchQueries{DB: db},
+		FeedQueries:                   &queries.FeedQueries{DB: db},
+		TimelineQueries:               &queries.TimelineQueries{DB: db},
+		ViewQueries:                   &queries.ViewQueries{DB: db},
+		PollQueries:                   &queries.PollQueries{DB: db},
+		MutedWordQueries:              &queries.MutedWordQueries{DB: db},
+		NotificationQueries:           &queries.NotificationQueries{DB: db},
+		RealtimeQueries:               &queries.RealtimeQueries{DB: db},
+		PushQueries:                   &queries.PushQueries{DB: db},
+		NotificationPreferenceQueries: &queries.NotificationPreferenceQueries{DB: db},
+		ConversationQueries:           &queries.ConversationQueries{DB: db},
+		MessageQueries:                &queries.MessageQueries{DB: db},
+		MessageAttachmentQueries:      &queries.MessageAttachmentQueries{DB: db},
+		Index:                         index,
 	}, nil
 }
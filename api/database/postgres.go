@@ -1,12 +1,14 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strconv"
 	"time"
 
 	"github.com/MangriMen/Diverse-Back/internal/helpers"
+	"github.com/jackc/pgx/v4"
 	_ "github.com/jackc/pgx/v4/stdlib" // compatibility layer for sqlx
 	"github.com/jmoiron/sqlx"
 )
@@ -18,16 +20,7 @@ func PostgreSQLConnection() (*sqlx.DB, error) {
 	maxIdleConn, _ := strconv.Atoi(os.Getenv("DB_MAX_IDLE_CONNECTIONS"))
 	maxLifetimeConn, _ := strconv.Atoi(os.Getenv("DB_MAX_LIFETIME_CONNECTIONS"))
 
-	dataSourceName := fmt.Sprintf("%s://%s:%s@%s:%s/%s?sslmode=disable",
-		os.Getenv("DB_TYPE"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_PORT"),
-		os.Getenv("DB_NAME"),
-	)
-
-	db, err := sqlx.Connect("pgx", dataSourceName)
+	db, err := sqlx.Connect("pgx", dataSourceName())
 	if err != nil {
 		return nil, fmt.Errorf("error, not connected to database, %w", err)
 	}
@@ -43,3 +36,26 @@ func PostgreSQLConnection() (*sqlx.DB, error) {
 
 	return db, nil
 }
+
+// PostgreSQLListenConnection opens the single connection to postgres database
+// with parameters from environment, used to listen for notifications.
+func PostgreSQLListenConnection(ctx context.Context) (*pgx.Conn, error) {
+	conn, err := pgx.Connect(ctx, dataSourceName())
+	if err != nil {
+		return nil, fmt.Errorf("error, not connected to database, %w", err)
+	}
+
+	return conn, nil
+}
+
+// dataSourceName returns the url of postgres database from environment.
+func dataSourceName() string {
+	return fmt.Sprintf("%s://%s:%s@%s:%s/%s?sslmode=disable",
+		os.Getenv("DB_TYPE"),
+		os.Getenv("DB_USER"),
+		os.Getenv("DB_PASSWORD"),
+		os.Getenv("DB_HOST"),
+		os.Getenv("DB_PORT"),
+		os.Getenv("DB_NAME"),
+	)
+}
@@ -2,10 +2,15 @@
 package server
 
 import (
+	"log"
+
 	"github.com/MangriMen/Diverse-Back/configs"
 	"github.com/MangriMen/Diverse-Back/internal/helpers"
+	"github.com/MangriMen/Diverse-Back/internal/jobs"
 	"github.com/MangriMen/Diverse-Back/internal/middleware"
+	"github.com/MangriMen/Diverse-Back/internal/realtime"
 	"github.com/MangriMen/Diverse-Back/internal/routes"
+	"github.com/MangriMen/Diverse-Back/internal/search"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -26,5 +31,23 @@ func InitAPI() *fiber.App {
 // SetupAPI is used to run instance of fiber web application.
 func SetupAPI() {
 	app := InitAPI()
-	helpers.StartServerWithGracefulShutdown(app)
+
+	stopJobs := jobs.Start(
+		jobs.RollupPostViews(),
+		jobs.PurgeDeletedPosts(),
+		jobs.ClosePolls(),
+		jobs.PurgeRealtimeEvents(),
+		jobs.DeliverPushNotifications(),
+		jobs.SendNotificationDigests(),
+		jobs.PurgeMessageAttachments(),
+		jobs.FlushSearchIndex(),
+		jobs.PurgeFeedSnapshots(),
+	)
+	stopRealtime := realtime.Start()
+	helpers.StartServerWithGracefulShutdown(app, stopRealtime)
+	stopJobs()
+
+	if err := search.FlushMemoryIndex(); err != nil {
+		log.Printf("Oops... Search index cannot be saved! Reason: %v", err)
+	}
 }
@@ -2,6 +2,9 @@
 package main
 
 import (
+	// Timezones of users are loaded for quiet hours and digests even if the system has no zoneinfo
+	_ "time/tzdata"
+
 	"github.com/MangriMen/Diverse-Back/api/server"
 	"github.com/MangriMen/Diverse-Back/internal/helpers"
 )
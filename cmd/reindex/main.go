@@ -0,0 +1,29 @@
+// Package main rebuilds the search index from the database.
+// The server can keep running while the memory index is rebuilt, it loads the rebuilt index
+// on the next flush and applies changes made since the rebuild started over it.
+package main
+
+import (
+	"log"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/internal/helpers"
+	"github.com/MangriMen/Diverse-Back/internal/search"
+)
+
+func main() {
+	if !helpers.IsRunningInContainer() {
+		helpers.LoadEnvironment(".env")
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		log.Fatalf("Oops... Database is not available! Reason: %v", err)
+	}
+
+	if err = search.Reindex(db.Index, db); err != nil {
+		log.Fatalf("Oops... Search index cannot be rebuilt! Reason: %v", err)
+	}
+
+	log.Println("Search index is rebuilt")
+}
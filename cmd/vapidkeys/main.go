@@ -0,0 +1,20 @@
+// Package main generates the VAPID key pair for Web Push notifications
+// and prints it in the format of the environment file.
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/MangriMen/Diverse-Back/internal/webpush"
+)
+
+func main() {
+	keys, err := webpush.GenerateKeys()
+	if err != nil {
+		log.Fatalf("Oops... VAPID keys cannot be generated! Reason: %v", err)
+	}
+
+	fmt.Printf("VAPID_PRIVATE_KEY=%s\n", keys.PrivateKey())
+	fmt.Printf("# Public key: %s\n", keys.PublicKey())
+}
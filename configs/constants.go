@@ -12,12 +12,203 @@ const PostEditTimeSinceCreated = 24 * time.Hour
 // PostCommentEditTimeSinceCreated is a threshold value for determining whether a comment can be edited or not.
 const PostCommentEditTimeSinceCreated = 24 * time.Hour
 
+// MessageEditTimeSinceCreated is a threshold value for determining whether a message can be edited or not.
+const MessageEditTimeSinceCreated = 15 * time.Minute
+
 // PostFetchCommentCount specifies the maximum number of comments to first time fetch a post.
 const PostFetchCommentCount = 20
 
+// CommentRepliesPreviewCount specifies the number of first replies embedded into comments of a post.
+const CommentRepliesPreviewCount = 2
+
+// CommentsTopGravity is how fast the score of comments sorted by top decays with the comment age in hours.
+const CommentsTopGravity = 1.5
+
+// PostMaxPinned is the maximum number of posts the user can pin to the profile.
+const PostMaxPinned = 3
+
+// MutedWordsMaxCount is the maximum number of words the user can mute.
+const MutedWordsMaxCount = 200
+
+// ConversationMaxMembers is the maximum number of members of the group conversation, including the owner.
+const ConversationMaxMembers = 50
+
+// NotificationActorsPreviewCount specifies the number of last actors embedded into notifications.
+const NotificationActorsPreviewCount = 3
+
+// DefaultReaction is the reaction set by like endpoints and the reaction of likes made before reactions.
+const DefaultReaction = "like"
+
+// GetReactions returns reactions which can be set to posts and comments.
+func GetReactions() []string {
+	return []string{DefaultReaction, "love", "haha", "wow", "sad", "angry"}
+}
+
+// Constants for post impressions.
+const (
+	// PostViewWindow is the period in which repeated views of the post by the same user are counted once.
+	PostViewWindow = 30 * time.Minute
+	// PostViewsRollupInterval is how often view events are rolled up into hourly counts.
+	PostViewsRollupInterval = 5 * time.Minute
+	// PostInsightsDefaultPeriod is the period of insights if the start is not given.
+	PostInsightsDefaultPeriod = 7 * 24 * time.Hour
+	// PostInsightsMaxPeriod is the maximum period of insights.
+	PostInsightsMaxPeriod = 90 * 24 * time.Hour
+)
+
+// PostTrashRetention is how long deleted posts can be restored before they are purged permanently.
+const PostTrashRetention = 30 * 24 * time.Hour
+
+// PostsPurgeInterval is how often deleted posts are checked for purging.
+const PostsPurgeInterval = time.Hour
+
+// PollsCloseInterval is how often expired polls are closed.
+const PollsCloseInterval = time.Minute
+
+// Constants for the real-time gateway.
+const (
+	// RealtimeEventsRetention is how long published events are kept to resume subscriptions.
+	RealtimeEventsRetention = time.Hour
+	// RealtimeEventsPurgeInterval is how often expired events are deleted.
+	RealtimeEventsPurgeInterval = 5 * time.Minute
+	// RealtimeReplayLimit is the maximum number of missed events sent on resume,
+	// the subscription is reset if more events are missed.
+	RealtimeReplayLimit = 500
+	// RealtimeSendBufferSize is the number of events queued for the connection,
+	// slow connections which overflow the queue are closed.
+	RealtimeSendBufferSize = 64
+	// RealtimeMaxTopics is the maximum number of topics one connection can subscribe to.
+	RealtimeMaxTopics = 100
+	// RealtimePingInterval is how often the server pings the connection.
+	RealtimePingInterval = 30 * time.Second
+	// RealtimePongWait is how long the server waits for any message or pong from the connection.
+	RealtimePongWait = 60 * time.Second
+	// RealtimeWriteWait is how long the server waits for a message to be written.
+	RealtimeWriteWait = 10 * time.Second
+	// RealtimeReconnectInterval is how long the listener waits before reconnecting to the database.
+	RealtimeReconnectInterval = 5 * time.Second
+	// RealtimeStreamRetry is how long clients of the event stream wait before reconnecting.
+	RealtimeStreamRetry = 3 * time.Second
+)
+
+// Constants for Web Push notifications.
+const (
+	// PushSubscriptionsMaxCount is the maximum number of push subscriptions of one user.
+	PushSubscriptionsMaxCount = 20
+	// PushServiceHosts are comma-separated hosts of push services subscriptions can use, with subdomains.
+	PushServiceHosts = "fcm.googleapis.com,push.services.mozilla.com,notify.windows.com,push.apple.com"
+	// PushDeliveryInterval is how often queued push notifications are sent.
+	PushDeliveryInterval = 5 * time.Second
+	// PushDeliveryBatchSize is the maximum number of push notifications sent at once.
+	PushDeliveryBatchSize = 100
+	// PushDeliveryLease is how long the claimed delivery is not sent by other instances.
+	PushDeliveryLease = time.Minute
+	// PushMaxAttempts is the maximum number of attempts to deliver the push notification.
+	PushMaxAttempts = 5
+	// PushRetryDelay is the delay before the first retry, it doubles with each next attempt.
+	PushRetryDelay = 30 * time.Second
+	// PushTTL is how long push services keep notifications while browsers are offline.
+	PushTTL = 24 * time.Hour
+	// PushSendTimeout is how long the request to the push service may take.
+	PushSendTimeout = 10 * time.Second
+	// VAPIDTokenLifetime is how long tokens signed with VAPID keys are valid, at most 24 hours.
+	VAPIDTokenLifetime = 12 * time.Hour
+)
+
+// Constants for notification preferences and email digests.
+const (
+	// DigestInterval is how often due email digests are sent.
+	DigestInterval = 15 * time.Minute
+	// DigestHour is the local hour of the user digests are sent after.
+	DigestHour = 9
+	// DigestTolerance is how much earlier than a day or a week after the last one the next digest can be sent,
+	// so the digest time doesn't drift with the job schedule.
+	DigestTolerance = time.Hour
+	// DigestBatchSize is the maximum number of digests sent at once.
+	DigestBatchSize = 100
+	// DigestNotificationsCount is the maximum number of notifications summarized in one digest.
+	DigestNotificationsCount = 200
+	// DigestListLimit is the maximum number of followers and posts listed in one digest.
+	DigestListLimit = 3
+	// DigestExcerptLength is the maximum number of characters of post descriptions listed in digests.
+	DigestExcerptLength = 100
+	// SMTPDefaultPort is the SMTP submission port used if SMTP_PORT is not set.
+	SMTPDefaultPort = "587"
+	// MailSendTimeout is how long sending one email through the SMTP server may take.
+	MailSendTimeout = 30 * time.Second
+	// MailOutboxFileMode is the permissions of emails written to the file outbox.
+	MailOutboxFileMode = 0o644
+)
+
+// Constants for messages.
+const (
+	// UploadMessagePurpose is the purpose of uploaded files which are attached to messages.
+	UploadMessagePurpose = "message"
+	// MessageMaxAttachments is the maximum number of images attached to one message.
+	MessageMaxAttachments = 10
+	// AttachmentsRetention is how long uploaded attachments are kept until they're sent,
+	// and attachments of deleted messages are kept.
+	AttachmentsRetention = 24 * time.Hour
+	// AttachmentsPurgeInterval is how often unused attachments are deleted.
+	AttachmentsPurgeInterval = time.Hour
+	// TypingIndicatorTimeout is how long clients show that the member is typing after the last typing event.
+	TypingIndicatorTimeout = 5 * time.Second
+)
+
+// Constants for ranking of the "for you" feed.
+const (
+	// FeedCandidateWindow is how old posts can be to get into the feed.
+	FeedCandidateWindow = 7 * 24 * time.Hour
+	// FeedCommentVelocityWindow is the period to count recent comments of the post.
+	FeedCommentVelocityWindow = 24 * time.Hour
+	// FeedSnapshotSize is the maximum number of posts in the one feed session.
+	FeedSnapshotSize = 500
+	// FeedSnapshotLifetime is how long the feed session can be scrolled.
+	FeedSnapshotLifetime = 24 * time.Hour
+	// FeedSnapshotsPurgeInterval is how often expired feed sessions are deleted.
+	FeedSnapshotsPurgeInterval = time.Hour
+	// FeedGravity is how fast the score decays with the post age in hours.
+	FeedGravity = 1.5
+	// FeedLikesWeight is the weight of the likes count logarithm.
+	FeedLikesWeight = 1.0
+	// FeedCommentsWeight is the weight of the recent comments count logarithm.
+	FeedCommentsWeight = 2.0
+	// FeedFollowingAffinity is the affinity bonus to the followed authors.
+	FeedFollowingAffinity = 1.0
+	// FeedAuthorRepeatPenalty is the score multiplier for each next post of the same author.
+	FeedAuthorRepeatPenalty = 0.5
+	// FeedMaxPostsPerAuthor is the maximum number of posts of the same author in the one feed session.
+	FeedMaxPostsPerAuthor = 5
+)
+
+// TimelineFanOutLimit is the maximum number of followers to fan out new posts to,
+// posts of authors with more followers are read from the posts table.
+const TimelineFanOutLimit = 10000
+
+// TimelineBackfillCount is the number of the latest author posts added to the timeline on follow.
+const TimelineBackfillCount = 100
+
 // DataPath specifies the root path for image files, video files, etc.
 const DataPath = "/var/lib/backend-data/"
 
+// SearchIndexPath specifies the file of the in-process search index.
+const SearchIndexPath = DataPath + "search/index.gob"
+
+const (
+	// SearchIndexFlushInterval is how often changes of the in-process search index are saved.
+	SearchIndexFlushInterval = time.Minute
+	// SearchIndexChangesRetention is how long changes of the in-process search index are kept
+	// to be applied over the index rebuilt meanwhile, it should exceed the time of the rebuild.
+	SearchIndexChangesRetention = 24 * time.Hour
+)
+
+// MailOutboxPath specifies the directory of the file outbox if MAIL_OUTBOX_DIR is not set.
+const MailOutboxPath = DataPath + "outbox/"
+
+// MessageAttachmentsPath specifies the directory of images attached to messages,
+// it's not served by the public data routes.
+const MessageAttachmentsPath = DataPath + "messages/"
+
 // BodyLimit is limit for body size in bits.
 const BodyLimit = 1024 * 1024 * 1024 * 8
 
@@ -34,6 +225,33 @@ func GetAllowedMIMEBaseTypes() []string {
 // WebpQuality is webp quality for saved images.
 const WebpQuality = 85
 
+// SearchDefaultLanguage is text search configuration used for posts and comments without explicit language.
+const SearchDefaultLanguage = "english"
+
+// Found words of headlines are marked with these private use runes by the database,
+// they're replaced with <mark> tags after the headline is escaped.
+const (
+	SearchHeadlineStartSel = "\uE002"
+	SearchHeadlineStopSel  = "\uE003"
+)
+
+// SearchHeadlineOptions is options for highlighting found fragments of text.
+const SearchHeadlineOptions = `StartSel="` + SearchHeadlineStartSel + `", StopSel="` + SearchHeadlineStopSel +
+	`", MaxWords=35, MinWords=15, MaxFragments=2`
+
+// GetSearchLanguages returns text search configurations allowed for posts and comments.
+func GetSearchLanguages() []string {
+	return []string{"simple", SearchDefaultLanguage, "russian", "german", "french", "spanish"}
+}
+
+// Constants for links of entities rendered to HTML.
+const (
+	// MentionLinkFormat is the link to the profile of the mentioned user by id.
+	MentionLinkFormat = "/users/%s"
+	// HashtagLinkFormat is the link to the search of posts by the hashtag without #.
+	HashtagLinkFormat = "/search/posts?q=%%23%s"
+)
+
 // Constants for postgres errors.
 const (
 	DBDuplicateError = "23505"
@@ -54,8 +272,66 @@ const (
 	PostsNotFoundError = "posts not found"
 	PostsInvalidFilter = "invalid filter option"
 
-	CommentNotFoundError  = "comment with this ID not found"
-	CommentsNotFoundError = "comments not found"
+	PostPinLimitErrorFormat = "can't pin more than %d posts"
+	PostAlreadyPinnedError  = "post is already pinned"
+	PostNotPinnedError      = "post is not pinned"
+	PostPinsMismatchError   = "posts must be exactly the pinned posts"
+	PostNotArchivedError    = "post is not archived"
+	PostNotInTrashError     = "post is not in the trash"
+	PostHiddenError         = "post is hidden by sensitive content preference"
+	PostMutedError          = "post is hidden by a muted word"
+
+	PollClosedError          = "poll is closed"
+	PollAlreadyVotedError    = "already voted in this poll"
+	PollNotFoundError        = "post has no poll"
+	PollInvalidOptionError   = "option is not in this poll"
+	PollSingleChoiceError    = "only one option can be chosen in this poll"
+	PollDuplicateOptionError = "option is chosen more than once"
+
+	ReactionInvalidError  = "unsupported reaction"
+	InsightsInvalidPeriod = "invalid insights period"
+
+	CommentNotFoundError    = "comment with this ID not found"
+	CommentsNotFoundError   = "comments not found"
+	CommentsRestrictedError = "comments on this post are restricted by the author"
+	CommentPinReplyError    = "only top-level comments can be pinned"
+	CommentNotPinnedError   = "comment is not pinned"
+
+	MutedWordNotFoundError    = "muted word with this ID not found"
+	MutedWordExistsError      = "word is already muted"
+	MutedWordLimitErrorFormat = "can't mute more than %d words"
+
+	NotificationNotFoundError = "notification with this ID not found"
+	InvalidTimezoneError      = "unknown timezone"
+	InvalidQuietHoursError    = "invalid quiet hours"
+
+	ConversationNotFoundError = "conversation with this ID not found"
+	ConversationSelfError     = "can't start a conversation with yourself"
+	MessageNotFoundError      = "message with this ID not found"
+	MessagingBlockedError     = "messaging is blocked between users"
+
+	ConversationNotGroupError           = "conversation is not a group"
+	ConversationMemberNotFoundError     = "member of the conversation with this ID not found"
+	ConversationOwnerError              = "the owner of the group can't be removed or change the role"
+	ConversationMembersLimitErrorFormat = "group can't have more than %d members"
+	SystemMessageEditError              = "system messages can't be edited"
+	MessageEmptyError                   = "message must have content or attachments"
+	AttachmentNotFoundError             = "attachment with this ID not found"
+
+	WebSocketUpgradeRequiredError  = "websocket upgrade required"
+	RealtimeTopicForbiddenError    = "topic is not available"
+	RealtimeTopicsLimitErrorFormat = "no more than %d topics can be subscribed to"
+	InvalidLastEventIDError        = "invalid last event id"
+
+	PushNotConfiguredError            = "push notifications are not configured"
+	PushSubscriptionNotFoundError     = "push subscription with this ID not found"
+	InvalidPushSubscriptionError      = "invalid push subscription keys"
+	PushServiceNotAllowedError        = "push service of the endpoint is not allowed"
+	PushSubscriptionsLimitErrorFormat = "no more than %d push subscriptions can be registered"
+
+	SearchInvalidLanguage = "unsupported search language"
+	SearchIndexError      = "search index is not available"
+	InvalidCursorError    = "invalid cursor"
 
 	ForbiddenError = "not enough permission"
 
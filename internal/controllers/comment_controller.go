@@ -8,9 +8,13 @@ import (
 	"github.com/MangriMen/Diverse-Back/api/database"
 	"github.com/MangriMen/Diverse-Back/configs"
 	"github.com/MangriMen/Diverse-Back/internal/helpers"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/cursorhelpers"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/notificationhelpers"
 	"github.com/MangriMen/Diverse-Back/internal/helpers/posthelpers"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/texthelpers"
 	"github.com/MangriMen/Diverse-Back/internal/models"
 	"github.com/MangriMen/Diverse-Back/internal/parameters"
+	"github.com/MangriMen/Diverse-Back/internal/realtime"
 	"github.com/MangriMen/Diverse-Back/internal/responses"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -83,8 +87,16 @@ func GetComments(c *fiber.Ctx) error {
 		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
 	}
 
-	if commentsFetchRequestQuery.LastSeenCommentCreatedAt.IsZero() {
-		commentsFetchRequestQuery.LastSeenCommentCreatedAt = time.Now()
+	sort := helpers.GetNotEmpty(commentsFetchRequestQuery.Sort, parameters.NewestComments)
+
+	cursor, err := cursorhelpers.Decode[parameters.CommentsCursor](commentsFetchRequestQuery.Cursor)
+	if err != nil || (cursor != nil && cursor.Sort != sort) {
+		return helpers.Response(c, fiber.StatusBadRequest, configs.InvalidCursorError)
+	}
+
+	rankedAt := time.Now()
+	if cursor != nil {
+		rankedAt = cursor.RankedAt
 	}
 
 	db, err := database.OpenDBConnection()
@@ -92,18 +104,53 @@ func GetComments(c *fiber.Ctx) error {
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
 	}
 
-	dbPosts, err := db.GetComments(postIDParams.Post, commentsFetchRequestQuery)
+	dbComments, err := db.GetComments(
+		postIDParams.Post,
+		userID,
+		sort,
+		cursor,
+		commentsFetchRequestQuery.Count,
+		rankedAt,
+	)
 	if err != nil {
 		return helpers.Response(c, fiber.StatusNotFound, configs.CommentsNotFoundError)
 	}
 
-	commentsToSend := lo.Map(dbPosts, func(item models.DBComment, index int) models.Comment {
-		return posthelpers.PrepareCommentToPost(item, userID, db)
+	commentsToSend := lo.Map(dbComments, func(item models.DBRankedComment, index int) models.Comment {
+		return posthelpers.PrepareCommentWithRepliesToPost(item.DBComment, userID, db)
 	})
 
+	if cursor == nil {
+		commentsToSend = append(posthelpers.PreparePinnedCommentToPost(postIDParams.Post, userID, db), commentsToSend...)
+	}
+
+	mutedWordsFilter, err := posthelpers.NewMutedWordsFilter(userID, models.CommentsScope, db)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	commentsToSend = mutedWordsFilter.FilterComments(commentsToSend)
+
+	nextCursor := ""
+	if len(dbComments) == commentsFetchRequestQuery.Count {
+		last := dbComments[len(dbComments)-1]
+
+		nextCursor, err = cursorhelpers.Encode(parameters.CommentsCursor{
+			Sort:      sort,
+			RankedAt:  rankedAt,
+			Score:     last.Score,
+			CreatedAt: last.CreatedAt,
+			CommentID: last.ID,
+		})
+		if err != nil {
+			return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+		}
+	}
+
 	return c.JSON(responses.GetCommentsResponseBody{
-		Count: len(commentsToSend),
-		Data:  commentsToSend,
+		Count:      len(commentsToSend),
+		Data:       commentsToSend,
+		NextCursor: nextCursor,
 	})
 }
 
@@ -119,11 +166,6 @@ func GetComments(c *fiber.Ctx) error {
 
 // AddComment is used to add the comment to the post by ID.
 func AddComment(c *fiber.Ctx) error {
-	userID, err := helpers.GetUserIDFromToken(c)
-	if err != nil {
-		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
-	}
-
 	commentAddRequestParams, err := helpers.GetParamsAndValidate[parameters.CommentAddRequestParams](
 		c,
 	)
@@ -131,9 +173,27 @@ func AddComment(c *fiber.Ctx) error {
 		return helpers.Response(c, fiber.StatusBadRequest, helpers.ValidatorErrors(err))
 	}
 
-	commentAddRequestBody, err := helpers.GetBodyAndValidate[parameters.CommentAddRequestBody](
-		c,
-	)
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return addComment(c, commentAddRequestParams.Post, nil, db)
+}
+
+// swagger:route POST /posts/{post}/comments/{comment}/replies Post replyToComment
+// Reply to the comment of the given post
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   201: CreateUpdateCommentResponse
+//   default: ErrorResponse
+
+// ReplyToComment is used to add the reply to the comment by post ID and comment ID.
+func ReplyToComment(c *fiber.Ctx) error {
+	postCommentIDParams, err := helpers.GetParamsAndValidate[parameters.PostCommentIDParams](c)
 	if err != nil {
 		return helpers.Response(c, fiber.StatusBadRequest, helpers.ValidatorErrors(err))
 	}
@@ -143,25 +203,67 @@ func AddComment(c *fiber.Ctx) error {
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
 	}
 
+	parentComment, err := db.GetComment(postCommentIDParams.Comment)
+	if err != nil || parentComment.PostID != postCommentIDParams.Post || parentComment.Tombstoned {
+		return helpers.Response(c, fiber.StatusNotFound, configs.CommentNotFoundError)
+	}
+
+	return addComment(c, postCommentIDParams.Post, &parentComment.ID, db)
+}
+
+// addComment adds the comment from the request body to the post, as a reply
+// if the parent comment is given.
+func addComment(c *fiber.Ctx, postID uuid.UUID, parentCommentID *uuid.UUID, db *database.Queries) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	commentAddRequestBody, err := helpers.GetBodyAndValidate[parameters.CommentAddRequestBody](
+		c,
+	)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, helpers.ValidatorErrors(err))
+	}
+
 	if _, err = db.GetUser(userID); err != nil {
 		return helpers.Response(c, fiber.StatusNotFound, configs.UserNotFoundError)
 	}
 
-	if _, err = db.GetPost(commentAddRequestParams.Post); err != nil {
+	foundPost, err := db.GetPost(postID)
+	if err != nil {
 		return helpers.Response(c, fiber.StatusNotFound, configs.PostNotFoundError)
 	}
 
+	canComment, err := posthelpers.CanComment(foundPost, userID, db)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if !canComment {
+		return helpers.Response(c, fiber.StatusForbidden, configs.CommentsRestrictedError)
+	}
+
+	language, err := posthelpers.GetLanguageOrDefault(commentAddRequestBody.Language)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
 	newComment := &models.DBComment{
 		BaseComment: models.BaseComment{
-			ID:        uuid.New(),
-			Content:   commentAddRequestBody.Content,
-			CreatedAt: time.Now(),
-			Likes:     0,
+			ID:              uuid.New(),
+			Content:         texthelpers.Normalize(commentAddRequestBody.Content),
+			CreatedAt:       time.Now(),
+			Likes:           0,
+			Language:        language,
+			ParentCommentID: parentCommentID,
+			Markdown:        commentAddRequestBody.Markdown,
 		},
-		PostID: commentAddRequestParams.Post,
+		PostID: postID,
 		UserID: userID,
 	}
 	newComment.UpdatedAt = newComment.CreatedAt
+	newComment.Entities = posthelpers.ParseEntities(newComment.Content, db)
 
 	validate := helpers.NewValidator()
 	if err = validate.Struct(newComment); err != nil {
@@ -172,9 +274,112 @@ func AddComment(c *fiber.Ctx) error {
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
 	}
 
+	realtime.PublishComment(realtime.CommentAddedEvent, *newComment, db)
+	notifyCommentAdded(newComment, foundPost, db)
+
 	return c.SendStatus(fiber.StatusCreated)
 }
 
+// notifyCommentAdded notifies the author of the parent comment about the reply or the author
+// of the post about the top-level comment, as well as users mentioned in the comment.
+func notifyCommentAdded(comment *models.DBComment, post models.DBPost, db *database.Queries) {
+	if comment.ParentCommentID != nil {
+		parentComment, err := db.GetComment(*comment.ParentCommentID)
+		if err == nil {
+			notificationhelpers.Notify(models.DBNotification{
+				UserID:    parentComment.UserID,
+				Type:      models.ReplyNotification,
+				PostID:    &post.ID,
+				CommentID: &parentComment.ID,
+			}, comment.UserID, db)
+		}
+	} else {
+		notificationhelpers.Notify(models.DBNotification{
+			UserID: post.UserID,
+			Type:   models.CommentNotification,
+			PostID: &post.ID,
+		}, comment.UserID, db)
+	}
+
+	notificationhelpers.NotifyMentions(comment.Content, post.ID, &comment.ID, comment.UserID, db)
+}
+
+// swagger:route GET /posts/{post}/comments/{comment}/replies Post getCommentReplies
+// Returns a page of replies to the comment, oldest first
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetRepliesResponse
+//   default: ErrorResponse
+
+// GetCommentReplies is used to fetch direct replies to the comment by post ID and comment ID.
+func GetCommentReplies(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	postCommentIDParams, err := helpers.GetParamsAndValidate[parameters.PostCommentIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	repliesFetchRequestQuery, err := helpers.GetQueryAndValidate[parameters.RepliesFetchRequestQuery](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	cursor, err := cursorhelpers.Decode[parameters.RepliesCursor](repliesFetchRequestQuery.Cursor)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, configs.InvalidCursorError)
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	foundComment, err := db.GetComment(postCommentIDParams.Comment)
+	if err != nil || foundComment.PostID != postCommentIDParams.Post {
+		return helpers.Response(c, fiber.StatusNotFound, configs.CommentNotFoundError)
+	}
+
+	dbReplies, err := db.GetCommentReplies(
+		foundComment.ID,
+		userID,
+		cursor,
+		repliesFetchRequestQuery.Count,
+	)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	repliesToSend := lo.Map(dbReplies, func(item models.DBComment, index int) models.Comment {
+		return posthelpers.PrepareCommentToPost(item, userID, db)
+	})
+
+	nextCursor := ""
+	if len(dbReplies) == repliesFetchRequestQuery.Count {
+		last := dbReplies[len(dbReplies)-1]
+
+		nextCursor, err = cursorhelpers.Encode(parameters.RepliesCursor{
+			CreatedAt: last.CreatedAt,
+			CommentID: last.ID,
+		})
+		if err != nil {
+			return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return c.JSON(responses.GetRepliesResponseBody{
+		Count:      len(repliesToSend),
+		Data:       repliesToSend,
+		NextCursor: nextCursor,
+	})
+}
+
 // swagger:route PATCH /posts/{post}/comments/{comment} Post updateComment
 // Update comment content by comment ID with given post ID
 //
@@ -210,7 +415,7 @@ func UpdateComment(c *fiber.Ctx) error {
 	}
 
 	foundComment, err := db.GetComment(postCommentIDParams.Comment)
-	if err != nil {
+	if err != nil || foundComment.Tombstoned {
 		return helpers.Response(c, fiber.StatusNotFound, configs.CommentNotFoundError)
 	}
 
@@ -232,9 +437,11 @@ func UpdateComment(c *fiber.Ctx) error {
 	}
 
 	foundComment.Content = helpers.GetNotEmpty(
-		commentUpdateRequestBody.Content,
+		texthelpers.Normalize(commentUpdateRequestBody.Content),
 		foundComment.Content,
 	)
+	foundComment.Markdown = lo.FromPtrOr(commentUpdateRequestBody.Markdown, foundComment.Markdown)
+	foundComment.Entities = posthelpers.ParseEntities(foundComment.Content, db)
 	foundComment.UpdatedAt = time.Now()
 
 	validate := helpers.NewValidator()
@@ -246,6 +453,8 @@ func UpdateComment(c *fiber.Ctx) error {
 		return helpers.Response(c, fiber.StatusInternalServerError, err)
 	}
 
+	realtime.PublishComment(realtime.CommentUpdatedEvent, foundComment, db)
+
 	commentToSend := posthelpers.PrepareCommentToPost(foundComment, userID, db)
 
 	return c.JSON(responses.GetCommentResponseBody{
@@ -263,8 +472,37 @@ func UpdateComment(c *fiber.Ctx) error {
 //   201: GetCommentResponse
 //   default: ErrorResponse
 
-// LikeComment is used to like the comment by ID.
+// LikeComment is used to set the default reaction to the comment by ID.
 func LikeComment(c *fiber.Ctx) error {
+	return reactToComment(c, configs.DefaultReaction)
+}
+
+// swagger:route PUT /posts/{post}/comments/{comment}/reaction Post reactToComment
+// Set reaction to the comment by ID, replacing the previous one
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   201: GetCommentResponse
+//   default: ErrorResponse
+
+// ReactToComment is used to set the given reaction to the comment by ID.
+func ReactToComment(c *fiber.Ctx) error {
+	reactionRequestBody, err := helpers.GetBodyAndValidate[parameters.ReactionRequestBody](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if err = posthelpers.ValidateReaction(reactionRequestBody.Reaction); err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return reactToComment(c, reactionRequestBody.Reaction)
+}
+
+// reactToComment is used to set the reaction of the requester to the comment by ID.
+func reactToComment(c *fiber.Ctx, reaction string) error {
 	userID, err := helpers.GetUserIDFromToken(c)
 	if err != nil {
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
@@ -284,6 +522,8 @@ func LikeComment(c *fiber.Ctx) error {
 		ID:        uuid.New(),
 		CommentID: postCommentIDParams.Comment,
 		UserID:    userID,
+		Reaction:  reaction,
+		CreatedAt: time.Now(),
 	}
 
 	if err = db.LikeComment(like); err != nil {
@@ -300,6 +540,14 @@ func LikeComment(c *fiber.Ctx) error {
 		return helpers.Response(c, fiber.StatusNotFound, configs.CommentNotFoundError)
 	}
 
+	realtime.PublishCommentLikes(foundComment, db)
+	notificationhelpers.Notify(models.DBNotification{
+		UserID:    foundComment.UserID,
+		Type:      models.CommentLikeNotification,
+		PostID:    &foundComment.PostID,
+		CommentID: &foundComment.ID,
+	}, userID, db)
+
 	commentToSend := posthelpers.PrepareCommentToPost(foundComment, userID, db)
 
 	return c.JSON(responses.GetCommentResponseBody{
@@ -307,6 +555,61 @@ func LikeComment(c *fiber.Ctx) error {
 	})
 }
 
+// swagger:route GET /posts/{post}/comments/{comment}/likes Post getCommentLikes
+// Returns a list of users who reacted to the comment, followed users first
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetLikesResponse
+//   default: ErrorResponse
+
+// GetCommentLikes is used to fetch users who reacted to the comment by ID.
+func GetCommentLikes(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	postCommentIDParams, err := helpers.GetParamsAndValidate[parameters.PostCommentIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	likesFetchRequestQuery, err := helpers.GetQueryAndValidate[parameters.LikesFetchRequestQuery](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	cursor, err := cursorhelpers.Decode[parameters.LikesCursor](likesFetchRequestQuery.Cursor)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, configs.InvalidCursorError)
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	foundComment, err := db.GetComment(postCommentIDParams.Comment)
+	if err != nil || foundComment.PostID != postCommentIDParams.Post {
+		return helpers.Response(c, fiber.StatusNotFound, configs.CommentNotFoundError)
+	}
+
+	dbLikers, err := db.GetCommentLikers(
+		foundComment.ID,
+		userID,
+		cursor,
+		likesFetchRequestQuery.Count,
+	)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return sendLikers(c, dbLikers, likesFetchRequestQuery.Count)
+}
+
 // swagger:route DELETE /posts/{post}/comments/{comment}/like Post unlikeComment
 // Unset like to the comment by ID
 //
@@ -317,7 +620,17 @@ func LikeComment(c *fiber.Ctx) error {
 //   201: GetCommentResponse
 //   default: ErrorResponse
 
-// UnlikeComment is used to unlike the comment by ID.
+// swagger:route DELETE /posts/{post}/comments/{comment}/reaction Post unreactToComment
+// Unset reaction to the comment by ID
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   201: GetCommentResponse
+//   default: ErrorResponse
+
+// UnlikeComment is used to remove the reaction to the comment by ID.
 func UnlikeComment(c *fiber.Ctx) error {
 	userID, err := helpers.GetUserIDFromToken(c)
 	if err != nil {
@@ -348,6 +661,8 @@ func UnlikeComment(c *fiber.Ctx) error {
 		return helpers.Response(c, fiber.StatusNotFound, configs.CommentNotFoundError)
 	}
 
+	realtime.PublishCommentLikes(foundComment, db)
+
 	commentToSend := posthelpers.PrepareCommentToPost(foundComment, userID, db)
 
 	return c.JSON(responses.GetCommentResponseBody{
@@ -371,6 +686,7 @@ func UnlikeComment(c *fiber.Ctx) error {
 //   default: ErrorResponse
 
 // DeleteComment is used to delete the comment on the post by post ID and comment ID.
+// Comments can be deleted by their authors and by the post author.
 func DeleteComment(c *fiber.Ctx) error {
 	userID, err := helpers.GetUserIDFromToken(c)
 	if err != nil {
@@ -393,12 +709,206 @@ func DeleteComment(c *fiber.Ctx) error {
 	}
 
 	if userID != foundComment.UserID {
-		return helpers.Response(c, fiber.StatusForbidden, configs.ForbiddenError)
+		foundPost, postErr := db.GetPost(foundComment.PostID)
+		if postErr != nil || userID != foundPost.UserID {
+			return helpers.Response(c, fiber.StatusForbidden, configs.ForbiddenError)
+		}
 	}
 
 	if err = db.DeleteComment(foundComment.ID); err != nil {
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
 	}
 
+	realtime.PublishComment(realtime.CommentDeletedEvent, foundComment, db)
+
 	return c.SendStatus(fiber.StatusNoContent)
 }
+
+// swagger:route POST /posts/{post}/comments/{comment}/hide Post hideComment
+// Hide the comment under own post from everyone except the comment author
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetCommentResponse
+//   default: ErrorResponse
+
+// HideComment is used to hide the comment under the post of the requester.
+func HideComment(c *fiber.Ctx) error {
+	return setCommentHidden(c, true)
+}
+
+// swagger:route DELETE /posts/{post}/comments/{comment}/hide Post unhideComment
+// Show the hidden comment under own post again
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetCommentResponse
+//   default: ErrorResponse
+
+// UnhideComment is used to show the hidden comment under the post of the requester again.
+func UnhideComment(c *fiber.Ctx) error {
+	return setCommentHidden(c, false)
+}
+
+// setCommentHidden is used to hide or show the comment by the post author.
+func setCommentHidden(c *fiber.Ctx, hidden bool) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	postCommentIDParams, err := helpers.GetParamsAndValidate[parameters.PostCommentIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	foundPost, err := db.GetPost(postCommentIDParams.Post)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusNotFound, configs.PostNotFoundError)
+	}
+
+	if userID != foundPost.UserID {
+		return helpers.Response(c, fiber.StatusForbidden, configs.ForbiddenError)
+	}
+
+	foundComment, err := db.GetComment(postCommentIDParams.Comment)
+	if err != nil || foundComment.PostID != foundPost.ID {
+		return helpers.Response(c, fiber.StatusNotFound, configs.CommentNotFoundError)
+	}
+
+	if err = db.SetCommentHidden(foundComment.ID, hidden); err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	foundComment.Hidden = hidden
+	realtime.PublishComment(realtime.CommentUpdatedEvent, foundComment, db)
+
+	commentToSend := posthelpers.PrepareCommentToPost(foundComment, userID, db)
+
+	return c.JSON(responses.GetCommentResponseBody{
+		Data: commentToSend,
+	})
+}
+
+// swagger:route POST /posts/{post}/comments/{comment}/pin Post pinComment
+// Pin the comment to the top of own post comments, replacing the pinned one
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetCommentResponse
+//   default: ErrorResponse
+
+// PinComment is used to pin the top-level comment under the post of the requester.
+func PinComment(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	postCommentIDParams, err := helpers.GetParamsAndValidate[parameters.PostCommentIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	foundPost, err := db.GetPost(postCommentIDParams.Post)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusNotFound, configs.PostNotFoundError)
+	}
+
+	if userID != foundPost.UserID {
+		return helpers.Response(c, fiber.StatusForbidden, configs.ForbiddenError)
+	}
+
+	foundComment, err := db.GetComment(postCommentIDParams.Comment)
+	if err != nil || foundComment.PostID != foundPost.ID || foundComment.Tombstoned {
+		return helpers.Response(c, fiber.StatusNotFound, configs.CommentNotFoundError)
+	}
+
+	if foundComment.ParentCommentID != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, configs.CommentPinReplyError)
+	}
+
+	if err = db.PinComment(foundPost.ID, foundComment.ID); err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	commentToSend := posthelpers.PrepareCommentToPost(foundComment, userID, db)
+	commentToSend.Pinned = true
+
+	return c.JSON(responses.GetCommentResponseBody{
+		Data: commentToSend,
+	})
+}
+
+// swagger:route DELETE /posts/{post}/comments/{comment}/pin Post unpinComment
+// Unpin the comment from the top of own post comments
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetCommentResponse
+//   default: ErrorResponse
+
+// UnpinComment is used to unpin the comment under the post of the requester.
+func UnpinComment(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	postCommentIDParams, err := helpers.GetParamsAndValidate[parameters.PostCommentIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	foundPost, err := db.GetPost(postCommentIDParams.Post)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusNotFound, configs.PostNotFoundError)
+	}
+
+	if userID != foundPost.UserID {
+		return helpers.Response(c, fiber.StatusForbidden, configs.ForbiddenError)
+	}
+
+	foundComment, err := db.GetComment(postCommentIDParams.Comment)
+	if err != nil || foundComment.PostID != foundPost.ID {
+		return helpers.Response(c, fiber.StatusNotFound, configs.CommentNotFoundError)
+	}
+
+	unpinned, err := db.UnpinComment(foundPost.ID, foundComment.ID)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if !unpinned {
+		return helpers.Response(c, fiber.StatusConflict, configs.CommentNotPinnedError)
+	}
+
+	commentToSend := posthelpers.PrepareCommentToPost(foundComment, userID, db)
+
+	return c.JSON(responses.GetCommentResponseBody{
+		Data: commentToSend,
+	})
+}
@@ -0,0 +1,635 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/helpers"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/cursorhelpers"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/messagehelpers"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/texthelpers"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/MangriMen/Diverse-Back/internal/parameters"
+	"github.com/MangriMen/Diverse-Back/internal/realtime"
+	"github.com/MangriMen/Diverse-Back/internal/responses"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+)
+
+// swagger:route GET /conversations Conversation getConversations
+// Returns a page of conversations of the requester or message requests, latest messages first
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetConversationsResponse
+//   default: ErrorResponse
+
+// GetConversations is used to fetch conversations or message requests of the requester.
+func GetConversations(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversationsFetchRequestQuery, err := helpers.GetQueryAndValidate[parameters.ConversationsFetchRequestQuery](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	cursor, err := cursorhelpers.Decode[parameters.ConversationsCursor](conversationsFetchRequestQuery.Cursor)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, configs.InvalidCursorError)
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	status := models.AcceptedConversation
+	if conversationsFetchRequestQuery.Requests {
+		status = models.RequestedConversation
+	}
+
+	dbConversations, err := db.GetMemberConversations(userID, status, cursor, conversationsFetchRequestQuery.Count)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversationsToSend := lo.Map(
+		dbConversations,
+		func(item models.DBMemberConversation, index int) models.Conversation {
+			return messagehelpers.PrepareConversationToSend(item, userID, db)
+		},
+	)
+
+	nextCursor := ""
+	if len(dbConversations) == conversationsFetchRequestQuery.Count {
+		last := dbConversations[len(dbConversations)-1]
+
+		nextCursor, err = cursorhelpers.Encode(parameters.ConversationsCursor{
+			UpdatedAt:      last.UpdatedAt,
+			ConversationID: last.ID,
+		})
+		if err != nil {
+			return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return c.JSON(responses.GetConversationsResponseBody{
+		Count:      len(conversationsToSend),
+		Data:       conversationsToSend,
+		NextCursor: nextCursor,
+	})
+}
+
+// swagger:route GET /conversations/count Conversation getConversationsCount
+// Returns counts of conversations with unread messages and message requests of the requester
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetConversationsCountResponse
+//   default: ErrorResponse
+
+// GetConversationsCount is used to fetch the number of unread conversations and message requests of the requester.
+func GetConversationsCount(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	unreadCount, requestsCount, err := db.GetUnreadConversationsCount(userID)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(responses.GetConversationsCountResponseBody{
+		UnreadCount:   unreadCount,
+		RequestsCount: requestsCount,
+	})
+}
+
+// swagger:route POST /conversations Conversation createConversation
+// Start the direct conversation with the user or return the existing one.
+// The user gets the new conversation as a message request unless they follow the requester
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetConversationResponse
+//   201: GetConversationResponse
+//   default: ErrorResponse
+
+// CreateConversation is used to start the direct conversation of the requester with the user.
+func CreateConversation(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversationCreateRequestBody, err := helpers.GetBodyAndValidate[parameters.ConversationCreateRequestBody](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	otherUserID := conversationCreateRequestBody.User
+	if otherUserID == userID {
+		return helpers.Response(c, fiber.StatusBadRequest, configs.ConversationSelfError)
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if _, err = db.GetUser(otherUserID); err != nil {
+		return helpers.Response(c, fiber.StatusNotFound, configs.UserNotFoundError)
+	}
+
+	blockedUserIDs, err := db.GetBlockedUserIDs(userID)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if slices.Contains(blockedUserIDs, otherUserID) {
+		return helpers.Response(c, fiber.StatusForbidden, configs.MessagingBlockedError)
+	}
+
+	now := time.Now()
+	newConversation := models.DBConversation{
+		ID:        uuid.New(),
+		Type:      models.DirectConversation,
+		DirectKey: lo.ToPtr(models.DirectConversationKey(userID, otherUserID)),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	created, err := db.CreateDirectConversation(&newConversation, userID, otherUserID)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversation, err := db.GetMemberConversation(newConversation.ID, userID)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	// Starting the conversation the requester got as a request or declined accepts it
+	if conversation.Status != models.AcceptedConversation {
+		err = db.UpdateConversationStatus(conversation.ID, userID, models.AcceptedConversation)
+		if err != nil {
+			return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+		}
+
+		conversation.Status = models.AcceptedConversation
+	}
+
+	status := fiber.StatusOK
+	if created {
+		status = fiber.StatusCreated
+	}
+
+	return c.Status(status).JSON(responses.GetConversationResponseBody{
+		Data: messagehelpers.PrepareConversationToSend(conversation, userID, db),
+	})
+}
+
+// swagger:route POST /conversations/groups Conversation createGroup
+// Create the group conversation owned by the requester.
+// Users who don't follow the requester get the group as a message request
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   201: GetConversationResponse
+//   default: ErrorResponse
+
+// CreateGroup is used to create the group conversation of the requester with the users.
+func CreateGroup(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	groupCreateRequestBody, err := helpers.GetBodyAndValidate[parameters.GroupCreateRequestBody](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	memberIDs := lo.Without(groupCreateRequestBody.Members, userID)
+	if len(memberIDs) == 0 {
+		return helpers.Response(c, fiber.StatusBadRequest, configs.ConversationSelfError)
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if status, validateErr := validateNewMembers(userID, memberIDs, db); validateErr != nil {
+		return helpers.Response(c, status, validateErr.Error())
+	}
+
+	now := time.Now()
+	newConversation := models.DBConversation{
+		ID:        uuid.New(),
+		Type:      models.GroupConversation,
+		Name:      lo.ToPtr(texthelpers.Normalize(groupCreateRequestBody.Name)),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	validate := helpers.NewValidator()
+	if err = validate.Struct(newConversation); err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, helpers.ValidatorErrors(err))
+	}
+
+	createdMessage := messagehelpers.NewSystemMessage(
+		newConversation.ID,
+		userID,
+		models.GroupCreatedMessageEvent,
+		nil,
+		*newConversation.Name,
+		now,
+	)
+
+	err = db.CreateGroupConversation(&newConversation, userID, memberIDs, &createdMessage)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	realtime.PublishMessage(realtime.MessageAddedEvent, createdMessage, db)
+
+	conversation, err := db.GetMemberConversation(newConversation.ID, userID)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(responses.GetConversationResponseBody{
+		Data: messagehelpers.PrepareConversationToSend(conversation, userID, db),
+	})
+}
+
+// swagger:route GET /conversations/{conversation} Conversation getConversation
+// Returns the conversation of the requester by ID
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetConversationResponse
+//   default: ErrorResponse
+
+// GetConversation is used to fetch the conversation of the requester.
+func GetConversation(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversationIDParams, err := helpers.GetParamsAndValidate[parameters.ConversationIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversation, found, err := messagehelpers.GetConversationOfMember(conversationIDParams.Conversation, userID, db)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if !found {
+		return helpers.Response(c, fiber.StatusNotFound, configs.ConversationNotFoundError)
+	}
+
+	return c.JSON(responses.GetConversationResponseBody{
+		Data: messagehelpers.PrepareConversationToSend(conversation, userID, db),
+	})
+}
+
+// swagger:route PATCH /conversations/{conversation} Conversation updateConversation
+// Rename the group, only the owner and admins can rename it
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetConversationResponse
+//   default: ErrorResponse
+
+// UpdateConversation is used to rename the group conversation of the requester.
+func UpdateConversation(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversationIDParams, err := helpers.GetParamsAndValidate[parameters.ConversationIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	conversationUpdateRequestBody, err := helpers.GetBodyAndValidate[parameters.ConversationUpdateRequestBody](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversation, status, err := getGroupOfMember(conversationIDParams.Conversation, userID, db)
+	if err != nil {
+		return helpers.Response(c, status, err.Error())
+	}
+
+	if !conversation.Role.CanManageMembers() {
+		return helpers.Response(c, fiber.StatusForbidden, configs.ForbiddenError)
+	}
+
+	conversation.Name = lo.ToPtr(texthelpers.Normalize(conversationUpdateRequestBody.Name))
+
+	validate := helpers.NewValidator()
+	if err = validate.Struct(conversation.DBConversation); err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, helpers.ValidatorErrors(err))
+	}
+
+	renamedMessage := messagehelpers.NewSystemMessage(
+		conversation.ID,
+		userID,
+		models.GroupRenamedMessageEvent,
+		nil,
+		*conversation.Name,
+		time.Now(),
+	)
+
+	if err = db.UpdateConversationName(&conversation.DBConversation, &renamedMessage); err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	realtime.PublishMessage(realtime.MessageAddedEvent, renamedMessage, db)
+
+	conversation.UpdatedAt = renamedMessage.CreatedAt
+
+	return c.JSON(responses.GetConversationResponseBody{
+		Data: messagehelpers.PrepareConversationToSend(conversation, userID, db),
+	})
+}
+
+// swagger:route POST /conversations/{conversation}/read Conversation readConversation
+// Mark all messages of the conversation as read, other members get the read receipt
+// unless the conversation is a message request of the requester
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   204: ReadConversationResponse
+//   default: ErrorResponse
+
+// ReadConversation is used to mark messages of the conversation as read by the requester.
+func ReadConversation(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversationIDParams, err := helpers.GetParamsAndValidate[parameters.ConversationIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversation, found, err := messagehelpers.GetConversationOfMember(conversationIDParams.Conversation, userID, db)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if !found {
+		return helpers.Response(c, fiber.StatusNotFound, configs.ConversationNotFoundError)
+	}
+
+	if err = db.ReadConversation(conversation.ID, userID, conversation.UpdatedAt); err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversation.UnreadCount = 0
+	realtime.PublishConversationRead(conversation, userID, db)
+
+	// Message requests are read without letting the sender know until they're accepted
+	if conversation.Status == models.AcceptedConversation {
+		realtime.PublishReadReceipt(conversation.ID, userID, conversation.UpdatedAt, db)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// swagger:route POST /conversations/{conversation}/typing Conversation typingConversation
+// Let other members know the requester is typing. The typing state is not kept,
+// clients send it again while the requester keeps typing
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   204: TypingConversationResponse
+//   default: ErrorResponse
+
+// TypingConversation is used to show other members of the conversation that the requester is typing.
+func TypingConversation(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversationIDParams, err := helpers.GetParamsAndValidate[parameters.ConversationIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversation, found, err := messagehelpers.GetConversationOfMember(conversationIDParams.Conversation, userID, db)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if !found {
+		return helpers.Response(c, fiber.StatusNotFound, configs.ConversationNotFoundError)
+	}
+
+	if conversation.Type == models.DirectConversation {
+		blocked, blockedErr := db.IsConversationBlocked(conversation.ID, userID)
+		if blockedErr != nil {
+			return helpers.Response(c, fiber.StatusInternalServerError, blockedErr.Error())
+		}
+
+		if blocked {
+			return helpers.Response(c, fiber.StatusForbidden, configs.MessagingBlockedError)
+		}
+	}
+
+	realtime.PublishTyping(conversation.ID, userID, db)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// swagger:route POST /conversations/{conversation}/accept Conversation acceptConversation
+// Accept the message request, or restore the declined conversation
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetConversationResponse
+//   default: ErrorResponse
+
+// AcceptConversation is used to move the message request to conversations of the requester.
+func AcceptConversation(c *fiber.Ctx) error {
+	return setConversationStatus(c, models.AcceptedConversation)
+}
+
+// swagger:route POST /conversations/{conversation}/decline Conversation declineConversation
+// Decline the message request or hide the conversation, its new messages are not shown to the requester
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetConversationResponse
+//   default: ErrorResponse
+
+// DeclineConversation is used to hide the message request or the conversation from the requester.
+func DeclineConversation(c *fiber.Ctx) error {
+	return setConversationStatus(c, models.DeclinedConversation)
+}
+
+// setConversationStatus is used to accept or decline the conversation by the requester.
+func setConversationStatus(c *fiber.Ctx, status models.ConversationStatus) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversationIDParams, err := helpers.GetParamsAndValidate[parameters.ConversationIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversation, found, err := messagehelpers.GetConversationOfMember(conversationIDParams.Conversation, userID, db)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if !found {
+		return helpers.Response(c, fiber.StatusNotFound, configs.ConversationNotFoundError)
+	}
+
+	if err = db.UpdateConversationStatus(conversation.ID, userID, status); err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversation.Status = status
+
+	return c.JSON(responses.GetConversationResponseBody{
+		Data: messagehelpers.PrepareConversationToSend(conversation, userID, db),
+	})
+}
+
+// swagger:route POST /conversations/{conversation}/mute Conversation muteConversation
+// Mute the conversation, its messages are not counted as unread
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetConversationResponse
+//   default: ErrorResponse
+
+// MuteConversation is used to mute the conversation for the requester.
+func MuteConversation(c *fiber.Ctx) error {
+	return setConversationMuted(c, true)
+}
+
+// swagger:route DELETE /conversations/{conversation}/mute Conversation unmuteConversation
+// Unmute the conversation
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetConversationResponse
+//   default: ErrorResponse
+
+// UnmuteConversation is used to unmute the conversation for the requester.
+func UnmuteConversation(c *fiber.Ctx) error {
+	return setConversationMuted(c, false)
+}
+
+// setConversationMuted is used to mute or unmute the conversation for the requester.
+func setConversationMuted(c *fiber.Ctx, muted bool) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversationIDParams, err := helpers.GetParamsAndValidate[parameters.ConversationIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversation, found, err := messagehelpers.GetConversationOfMember(conversationIDParams.Conversation, userID, db)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if !found {
+		return helpers.Response(c, fiber.StatusNotFound, configs.ConversationNotFoundError)
+	}
+
+	if err = db.UpdateConversationMuted(conversation.ID, userID, muted); err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversation.Muted = muted
+
+	return c.JSON(responses.GetConversationResponseBody{
+		Data: messagehelpers.PrepareConversationToSend(conversation, userID, db),
+	})
+}
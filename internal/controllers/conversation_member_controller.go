@@ -0,0 +1,405 @@
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/helpers"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/messagehelpers"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/MangriMen/Diverse-Back/internal/parameters"
+	"github.com/MangriMen/Diverse-Back/internal/realtime"
+	"github.com/MangriMen/Diverse-Back/internal/responses"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+)
+
+// swagger:route GET /conversations/{conversation}/members Conversation getConversationMembers
+// Returns members of the conversation with their roles
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetConversationMembersResponse
+//   default: ErrorResponse
+
+// GetConversationMembers is used to fetch members of the conversation of the requester.
+func GetConversationMembers(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversationIDParams, err := helpers.GetParamsAndValidate[parameters.ConversationIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversation, found, err := messagehelpers.GetConversationOfMember(conversationIDParams.Conversation, userID, db)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if !found {
+		return helpers.Response(c, fiber.StatusNotFound, configs.ConversationNotFoundError)
+	}
+
+	return sendConversationMembers(c, conversation.ID, db)
+}
+
+// swagger:route POST /conversations/{conversation}/members Conversation addConversationMembers
+// Add users to the group, only the owner and admins can add members.
+// Users who don't follow the requester get the group as a message request
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetConversationMembersResponse
+//   default: ErrorResponse
+
+// AddConversationMembers is used to add users to the group conversation of the requester.
+func AddConversationMembers(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversationIDParams, err := helpers.GetParamsAndValidate[parameters.ConversationIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	membersAddRequestBody, err := helpers.GetBodyAndValidate[parameters.ConversationMembersAddRequestBody](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversation, status, err := getGroupOfMember(conversationIDParams.Conversation, userID, db)
+	if err != nil {
+		return helpers.Response(c, status, err.Error())
+	}
+
+	if !conversation.Role.CanManageMembers() {
+		return helpers.Response(c, fiber.StatusForbidden, configs.ForbiddenError)
+	}
+
+	members, err := db.GetConversationMembers(conversation.ID)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	memberIDs := lo.Map(members, func(item models.DBConversationMember, index int) uuid.UUID {
+		return item.UserID
+	})
+
+	newMemberIDs := lo.Without(membersAddRequestBody.Users, memberIDs...)
+	if len(newMemberIDs) == 0 {
+		return sendConversationMembers(c, conversation.ID, db)
+	}
+
+	if len(memberIDs)+len(newMemberIDs) > configs.ConversationMaxMembers {
+		return helpers.Response(c, fiber.StatusBadRequest, fmt.Sprintf(
+			configs.ConversationMembersLimitErrorFormat,
+			configs.ConversationMaxMembers,
+		))
+	}
+
+	if status, err = validateNewMembers(userID, newMemberIDs, db); err != nil {
+		return helpers.Response(c, status, err.Error())
+	}
+
+	now := time.Now()
+	newMessages := lo.Map(newMemberIDs, func(item uuid.UUID, index int) models.DBMessage {
+		return messagehelpers.NewSystemMessage(
+			conversation.ID,
+			userID,
+			models.MemberAddedMessageEvent,
+			lo.ToPtr(item),
+			"",
+			now,
+		)
+	})
+
+	added, err := db.AddConversationMembers(conversation.ID, userID, newMemberIDs, newMessages)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if !added {
+		return helpers.Response(c, fiber.StatusBadRequest, fmt.Sprintf(
+			configs.ConversationMembersLimitErrorFormat,
+			configs.ConversationMaxMembers,
+		))
+	}
+
+	for _, message := range newMessages {
+		realtime.PublishMessage(realtime.MessageAddedEvent, message, db)
+	}
+
+	return sendConversationMembers(c, conversation.ID, db)
+}
+
+// swagger:route DELETE /conversations/{conversation}/members/{user} Conversation removeConversationMember
+// Remove the member from the group. The owner can remove anyone, admins can remove only members
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   204: LeaveConversationResponse
+//   default: ErrorResponse
+
+// RemoveConversationMember is used to remove the member from the group conversation of the requester.
+func RemoveConversationMember(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversationMemberIDParams, err := helpers.GetParamsAndValidate[parameters.ConversationMemberIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversation, status, err := getGroupOfMember(conversationMemberIDParams.Conversation, userID, db)
+	if err != nil {
+		return helpers.Response(c, status, err.Error())
+	}
+
+	if conversationMemberIDParams.User == userID {
+		return leaveGroup(c, conversation, userID, db)
+	}
+
+	if !conversation.Role.CanManageMembers() {
+		return helpers.Response(c, fiber.StatusForbidden, configs.ForbiddenError)
+	}
+
+	member, err := db.GetConversationMember(conversation.ID, conversationMemberIDParams.User)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusNotFound, configs.ConversationMemberNotFoundError)
+	}
+
+	if member.Role == models.OwnerRole {
+		return helpers.Response(c, fiber.StatusForbidden, configs.ConversationOwnerError)
+	}
+
+	if conversation.Role != models.OwnerRole && member.Role != models.MemberRole {
+		return helpers.Response(c, fiber.StatusForbidden, configs.ForbiddenError)
+	}
+
+	removedMessage := messagehelpers.NewSystemMessage(
+		conversation.ID,
+		userID,
+		models.MemberRemovedMessageEvent,
+		&member.UserID,
+		"",
+		time.Now(),
+	)
+
+	if err = db.RemoveConversationMember(conversation.ID, member.UserID, &removedMessage); err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	realtime.PublishMessage(realtime.MessageAddedEvent, removedMessage, db)
+	realtime.PublishConversationLeft(conversation.ID, member.UserID, db)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// swagger:route POST /conversations/{conversation}/leave Conversation leaveConversation
+// Leave the group. The ownership passes to the earliest admin or member if the owner leaves,
+// the group is deleted when the last member leaves
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   204: LeaveConversationResponse
+//   default: ErrorResponse
+
+// LeaveConversation is used to remove the requester from the group conversation.
+func LeaveConversation(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversationIDParams, err := helpers.GetParamsAndValidate[parameters.ConversationIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversation, status, err := getGroupOfMember(conversationIDParams.Conversation, userID, db)
+	if err != nil {
+		return helpers.Response(c, status, err.Error())
+	}
+
+	return leaveGroup(c, conversation, userID, db)
+}
+
+// swagger:route PUT /conversations/{conversation}/members/{user}/role Conversation updateConversationMemberRole
+// Make the member an admin of the group or a regular member, only the owner can change roles
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetConversationMembersResponse
+//   default: ErrorResponse
+
+// UpdateConversationMemberRole is used to change the role of the member of the group conversation of the requester.
+func UpdateConversationMemberRole(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversationMemberIDParams, err := helpers.GetParamsAndValidate[parameters.ConversationMemberIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	memberRoleRequestBody, err := helpers.GetBodyAndValidate[parameters.ConversationMemberRoleRequestBody](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversation, status, err := getGroupOfMember(conversationMemberIDParams.Conversation, userID, db)
+	if err != nil {
+		return helpers.Response(c, status, err.Error())
+	}
+
+	if conversation.Role != models.OwnerRole {
+		return helpers.Response(c, fiber.StatusForbidden, configs.ForbiddenError)
+	}
+
+	member, err := db.GetConversationMember(conversation.ID, conversationMemberIDParams.User)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusNotFound, configs.ConversationMemberNotFoundError)
+	}
+
+	if member.Role == models.OwnerRole {
+		return helpers.Response(c, fiber.StatusForbidden, configs.ConversationOwnerError)
+	}
+
+	err = db.UpdateMemberRole(conversation.ID, member.UserID, memberRoleRequestBody.Role)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return sendConversationMembers(c, conversation.ID, db)
+}
+
+// leaveGroup removes the user from the group conversation and records the leaving with the system message.
+func leaveGroup(c *fiber.Ctx, conversation models.DBMemberConversation, userID uuid.UUID, db *database.Queries) error {
+	leftMessage := messagehelpers.NewSystemMessage(
+		conversation.ID,
+		userID,
+		models.MemberLeftMessageEvent,
+		&userID,
+		"",
+		time.Now(),
+	)
+
+	if err := db.RemoveConversationMember(conversation.ID, userID, &leftMessage); err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	realtime.PublishMessage(realtime.MessageAddedEvent, leftMessage, db)
+	realtime.PublishConversationLeft(conversation.ID, userID, db)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// sendConversationMembers sends members of the conversation.
+func sendConversationMembers(c *fiber.Ctx, conversationID uuid.UUID, db *database.Queries) error {
+	members, err := db.GetConversationMembers(conversationID)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	membersToSend := messagehelpers.PrepareConversationMembersToSend(members, db)
+
+	return c.JSON(responses.GetConversationMembersResponseBody{
+		Count: len(membersToSend),
+		Data:  membersToSend,
+	})
+}
+
+// getGroupOfMember returns the group conversation as seen by the user,
+// with the status to respond with if the user is not its member or the conversation is not a group.
+func getGroupOfMember(
+	conversationID uuid.UUID,
+	userID uuid.UUID,
+	db *database.Queries,
+) (models.DBMemberConversation, int, error) {
+	conversation, found, err := messagehelpers.GetConversationOfMember(conversationID, userID, db)
+	if err != nil {
+		return conversation, fiber.StatusInternalServerError, err
+	}
+
+	if !found {
+		return conversation, fiber.StatusNotFound, fmt.Errorf(configs.ConversationNotFoundError)
+	}
+
+	if conversation.Type != models.GroupConversation {
+		return conversation, fiber.StatusBadRequest, fmt.Errorf(configs.ConversationNotGroupError)
+	}
+
+	return conversation, fiber.StatusOK, nil
+}
+
+// validateNewMembers checks that users to add to the conversation exist
+// and messaging between them and the user is not blocked,
+// with the status to respond with if they can't be added.
+func validateNewMembers(userID uuid.UUID, newMemberIDs []uuid.UUID, db *database.Queries) (int, error) {
+	for _, newMemberID := range newMemberIDs {
+		if _, err := db.GetUser(newMemberID); err != nil {
+			return fiber.StatusNotFound, fmt.Errorf(configs.UserNotFoundError)
+		}
+	}
+
+	blockedUserIDs, err := db.GetBlockedUserIDs(userID)
+	if err != nil {
+		return fiber.StatusInternalServerError, err
+	}
+
+	if slices.ContainsFunc(newMemberIDs, func(item uuid.UUID) bool {
+		return slices.Contains(blockedUserIDs, item)
+	}) {
+		return fiber.StatusForbidden, fmt.Errorf(configs.MessagingBlockedError)
+	}
+
+	return fiber.StatusOK, nil
+}
@@ -3,7 +3,6 @@ package controllers
 import (
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/MangriMen/Diverse-Back/configs"
 	"github.com/MangriMen/Diverse-Back/internal/helpers"
@@ -52,37 +51,16 @@ func GetData(c *fiber.Ctx) error {
 			return fileErr
 		}
 
-		imageMetadata, metadataErr := bimg.Metadata(file)
-		if metadataErr != nil {
-			return metadataErr
-		}
-
-		var options bimg.Options
-
-		switch {
-		case getDataRequestQuery.Width != nil &&
-			*getDataRequestQuery.Width < imageMetadata.Size.Width:
-			options = bimg.Options{Width: *getDataRequestQuery.Width}
-		case getDataRequestQuery.Height != nil &&
-			*getDataRequestQuery.Height < imageMetadata.Size.Height:
-			options = bimg.Options{Height: *getDataRequestQuery.Height}
-		}
-
-		image, imageErr := bimg.NewImage(file).Process(options)
+		image, contentType, imageErr := datahelpers.ResizeImage(
+			file,
+			getDataRequestQuery.Width,
+			getDataRequestQuery.Height,
+		)
 		if imageErr != nil {
 			return imageErr
 		}
 
-		c.Set(
-			"Content-Type",
-			strings.Join(
-				[]string{
-					getDataRequestParams.Type,
-					bimg.ImageTypeName(bimg.DetermineImageType(image)),
-				},
-				"/",
-			),
-		)
+		c.Set("Content-Type", contentType)
 
 		return c.Send(image)
 	}
@@ -94,7 +72,8 @@ func GetData(c *fiber.Ctx) error {
 //
 // Uploads data to storage
 //
-// Uploads one of the MIME data type images, converts it to webp and returns a relative path
+// Uploads one of the MIME data type images, converts it to webp and returns a relative path.
+// Images uploaded for messages are private, their id is returned to attach them to the message
 //
 // Responses:
 //   200: UploadDataResponse
@@ -102,6 +81,11 @@ func GetData(c *fiber.Ctx) error {
 
 // UploadData is used to upload data files.
 func UploadData(c *fiber.Ctx) error {
+	uploadDataRequestQuery, err := helpers.GetQueryAndValidate[parameters.UploadDataRequestQuery](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
 	receivedFile, err := c.FormFile("file")
 	if err != nil {
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
@@ -115,6 +99,10 @@ func UploadData(c *fiber.Ctx) error {
 		return helpers.Response(c, fiber.StatusUnsupportedMediaType, err.Error())
 	}
 
+	if uploadDataRequestQuery.Purpose == configs.UploadMessagePurpose {
+		return uploadMessageAttachment(c, receivedFile)
+	}
+
 	filename := datahelpers.GenerateUniqueFilename()
 	pathToFolder := filepath.Join(configs.DataPath, baseType)
 
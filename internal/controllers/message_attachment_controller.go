@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/helpers"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/datahelpers"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/messagehelpers"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/MangriMen/Diverse-Back/internal/parameters"
+	"github.com/MangriMen/Diverse-Back/internal/responses"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/h2non/bimg"
+)
+
+// swagger:route GET /conversations/attachments/{attachment} Conversation getMessageAttachment
+// Returns the image attached to the message, only to members of its conversation and the uploader
+//
+// Produces:
+//   - image/webp
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetDataResponse
+//   default: ErrorResponse
+
+// GetMessageAttachment is used to get the image attached to the message of the conversation of the requester.
+func GetMessageAttachment(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	attachmentIDParams, err := helpers.GetParamsAndValidate[parameters.MessageAttachmentIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	getDataRequestQuery, err := helpers.GetQueryAndValidate[parameters.GetDataRequestQuery](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	attachment, err := db.GetMessageAttachment(attachmentIDParams.Attachment)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusNotFound, configs.AttachmentNotFoundError)
+	}
+
+	visible, err := isAttachmentVisible(attachment, userID, db)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if !visible {
+		return helpers.Response(c, fiber.StatusNotFound, configs.AttachmentNotFoundError)
+	}
+
+	file, err := bimg.Read(filepath.Join(configs.MessageAttachmentsPath, attachment.Filename))
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	image, contentType, err := datahelpers.ResizeImage(file, getDataRequestQuery.Width, getDataRequestQuery.Height)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Set("Content-Type", contentType)
+	c.Set("Cache-Control", "private")
+
+	return c.Send(image)
+}
+
+// uploadMessageAttachment saves the image uploaded by the requester for messages
+// outside of the public data directory.
+func uploadMessageAttachment(c *fiber.Ctx, receivedFile *multipart.FileHeader) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if err = os.MkdirAll(configs.MessageAttachmentsPath, os.ModePerm); err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	newAttachment := models.DBMessageAttachment{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Filename:  datahelpers.GenerateUniqueFilename(),
+		CreatedAt: time.Now(),
+	}
+
+	pathToFile := filepath.Join(configs.MessageAttachmentsPath, newAttachment.Filename)
+
+	if err = datahelpers.ProcessFile(receivedFile, pathToFile); err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if err = db.CreateMessageAttachment(&newAttachment); err != nil {
+		if removeErr := os.Remove(pathToFile); removeErr != nil {
+			log.Printf("Message attachment is not removed. Reason: %v", removeErr)
+		}
+
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(
+		responses.UploadDataResponseBody{
+			Path: models.MessageAttachmentPath(newAttachment.ID),
+			ID:   &newAttachment.ID,
+		},
+	)
+}
+
+// isAttachmentVisible reports whether the user uploaded the attachment
+// or can see the message it's attached to.
+func isAttachmentVisible(attachment models.DBMessageAttachment, userID uuid.UUID, db *database.Queries) (bool, error) {
+	if attachment.UserID == userID {
+		return true, nil
+	}
+
+	if attachment.MessageID == nil {
+		return false, nil
+	}
+
+	message, err := db.GetMessage(*attachment.MessageID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	conversation, found, err := messagehelpers.GetConversationOfMember(message.ConversationID, userID, db)
+	if err != nil || !found {
+		return false, err
+	}
+
+	return !message.CreatedAt.Before(conversation.JoinedAt), nil
+}
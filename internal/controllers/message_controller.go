@@ -0,0 +1,355 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/helpers"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/cursorhelpers"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/messagehelpers"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/texthelpers"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/MangriMen/Diverse-Back/internal/parameters"
+	"github.com/MangriMen/Diverse-Back/internal/realtime"
+	"github.com/MangriMen/Diverse-Back/internal/responses"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// swagger:route GET /conversations/{conversation}/messages Conversation getMessages
+// Returns a page of messages of the conversation, newest first
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetMessagesResponse
+//   default: ErrorResponse
+
+// GetMessages is used to fetch messages of the conversation of the requester.
+func GetMessages(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversationIDParams, err := helpers.GetParamsAndValidate[parameters.ConversationIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	messagesFetchRequestQuery, err := helpers.GetQueryAndValidate[parameters.MessagesFetchRequestQuery](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	cursor, err := cursorhelpers.Decode[parameters.MessagesCursor](messagesFetchRequestQuery.Cursor)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, configs.InvalidCursorError)
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversation, found, err := messagehelpers.GetConversationOfMember(conversationIDParams.Conversation, userID, db)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if !found {
+		return helpers.Response(c, fiber.StatusNotFound, configs.ConversationNotFoundError)
+	}
+
+	dbMessages, err := db.GetMessages(conversation.ID, conversation.JoinedAt, cursor, messagesFetchRequestQuery.Count)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	messagesToSend := lo.Map(dbMessages, func(item models.DBMessage, index int) models.Message {
+		return messagehelpers.PrepareMessageToSend(item, db)
+	})
+
+	nextCursor := ""
+	if len(dbMessages) == messagesFetchRequestQuery.Count {
+		last := dbMessages[len(dbMessages)-1]
+
+		nextCursor, err = cursorhelpers.Encode(parameters.MessagesCursor{
+			CreatedAt: last.CreatedAt,
+			MessageID: last.ID,
+		})
+		if err != nil {
+			return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return c.JSON(responses.GetMessagesResponseBody{
+		Count:      len(messagesToSend),
+		Data:       messagesToSend,
+		NextCursor: nextCursor,
+	})
+}
+
+// swagger:route POST /conversations/{conversation}/messages Conversation sendMessage
+// Send the message to the conversation, replying to the message request accepts it
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   201: GetMessageResponse
+//   default: ErrorResponse
+
+// SendMessage is used to send the message of the requester to the conversation.
+func SendMessage(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversationIDParams, err := helpers.GetParamsAndValidate[parameters.ConversationIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	messageSendRequestBody, err := helpers.GetBodyAndValidate[parameters.MessageSendRequestBody](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversation, found, err := messagehelpers.GetConversationOfMember(conversationIDParams.Conversation, userID, db)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if !found {
+		return helpers.Response(c, fiber.StatusNotFound, configs.ConversationNotFoundError)
+	}
+
+	// Members of groups who blocked each other can still take part in the group
+	if conversation.Type == models.DirectConversation {
+		blocked, blockedErr := db.IsConversationBlocked(conversation.ID, userID)
+		if blockedErr != nil {
+			return helpers.Response(c, fiber.StatusInternalServerError, blockedErr.Error())
+		}
+
+		if blocked {
+			return helpers.Response(c, fiber.StatusForbidden, configs.MessagingBlockedError)
+		}
+	}
+
+	newMessage := models.DBMessage{
+		BaseMessage: models.BaseMessage{
+			ID:             uuid.New(),
+			ConversationID: conversation.ID,
+			SenderID:       &userID,
+			Type:           models.TextMessage,
+			Content:        texthelpers.Normalize(messageSendRequestBody.Content),
+			CreatedAt:      time.Now(),
+		},
+	}
+	newMessage.UpdatedAt = newMessage.CreatedAt
+
+	if newMessage.Content == "" && len(messageSendRequestBody.Attachments) == 0 {
+		return helpers.Response(c, fiber.StatusBadRequest, configs.MessageEmptyError)
+	}
+
+	validate := helpers.NewValidator()
+	if err = validate.Struct(newMessage); err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, helpers.ValidatorErrors(err))
+	}
+
+	err = db.CreateMessage(&newMessage, messageSendRequestBody.Attachments)
+	if errors.Is(err, sql.ErrNoRows) {
+		return helpers.Response(c, fiber.StatusBadRequest, configs.AttachmentNotFoundError)
+	}
+
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	realtime.PublishMessage(realtime.MessageAddedEvent, newMessage, db)
+
+	return c.Status(fiber.StatusCreated).JSON(responses.GetMessageResponseBody{
+		Data: messagehelpers.PrepareMessageToSend(newMessage, db),
+	})
+}
+
+// swagger:route PATCH /conversations/{conversation}/messages/{message} Conversation updateMessage
+// Edit the message sent by the requester
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetMessageResponse
+//   default: ErrorResponse
+
+// UpdateMessage is used to edit the content of the message sent by the requester.
+func UpdateMessage(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversationMessageIDParams, err := helpers.GetParamsAndValidate[parameters.ConversationMessageIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	messageUpdateRequestBody, err := helpers.GetBodyAndValidate[parameters.MessageUpdateRequestBody](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	_, foundMessage, found, err := getMessageOfMember(conversationMessageIDParams, userID, db)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if !found {
+		return helpers.Response(c, fiber.StatusNotFound, configs.MessageNotFoundError)
+	}
+
+	if foundMessage.Type == models.SystemMessage {
+		return helpers.Response(c, fiber.StatusForbidden, configs.SystemMessageEditError)
+	}
+
+	if foundMessage.SenderID == nil || *foundMessage.SenderID != userID {
+		return helpers.Response(c, fiber.StatusForbidden, configs.ForbiddenError)
+	}
+
+	if foundMessage.CreatedAt.Add(configs.MessageEditTimeSinceCreated).Before(time.Now()) {
+		return helpers.Response(c, fiber.StatusForbidden, fmt.Sprintf(
+			configs.CantEditAfterErrorFormat,
+			"message",
+			configs.MessageEditTimeSinceCreated.String(),
+		))
+	}
+
+	foundMessage.Content = texthelpers.Normalize(messageUpdateRequestBody.Content)
+	foundMessage.UpdatedAt = time.Now()
+
+	if foundMessage.Content == "" {
+		attachments, attachmentsErr := db.GetMessageAttachments(foundMessage.ID)
+		if attachmentsErr != nil {
+			return helpers.Response(c, fiber.StatusInternalServerError, attachmentsErr.Error())
+		}
+
+		if len(attachments) == 0 {
+			return helpers.Response(c, fiber.StatusBadRequest, configs.MessageEmptyError)
+		}
+	}
+
+	validate := helpers.NewValidator()
+	if err = validate.Struct(foundMessage); err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, helpers.ValidatorErrors(err))
+	}
+
+	if err = db.UpdateMessage(&foundMessage); err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	realtime.PublishMessage(realtime.MessageUpdatedEvent, foundMessage, db)
+
+	return c.JSON(responses.GetMessageResponseBody{
+		Data: messagehelpers.PrepareMessageToSend(foundMessage, db),
+	})
+}
+
+// swagger:route DELETE /conversations/{conversation}/messages/{message} Conversation deleteMessage
+// Delete the message sent by the requester for all members of the conversation,
+// owners and admins of groups can delete messages of other members
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   204: DeleteMessageResponse
+//   default: ErrorResponse
+
+// DeleteMessage is used to delete the message of the conversation of the requester.
+func DeleteMessage(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversationMessageIDParams, err := helpers.GetParamsAndValidate[parameters.ConversationMessageIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	conversation, foundMessage, found, err := getMessageOfMember(conversationMessageIDParams, userID, db)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if !found {
+		return helpers.Response(c, fiber.StatusNotFound, configs.MessageNotFoundError)
+	}
+
+	isSender := foundMessage.SenderID != nil && *foundMessage.SenderID == userID
+	isGroupManager := conversation.Type == models.GroupConversation && conversation.Role.CanManageMembers()
+
+	if !isSender && !isGroupManager {
+		return helpers.Response(c, fiber.StatusForbidden, configs.ForbiddenError)
+	}
+
+	if err = db.DeleteMessage(foundMessage.ID); err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	realtime.PublishMessage(realtime.MessageDeletedEvent, foundMessage, db)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// getMessageOfMember returns the message of the conversation as seen by the user,
+// false if the user is not a member of the conversation or the message was sent before the user joined it.
+func getMessageOfMember(
+	params *parameters.ConversationMessageIDParams,
+	userID uuid.UUID,
+	db *database.Queries,
+) (models.DBMemberConversation, models.DBMessage, bool, error) {
+	conversation, found, err := messagehelpers.GetConversationOfMember(params.Conversation, userID, db)
+	if err != nil || !found {
+		return conversation, models.DBMessage{}, false, err
+	}
+
+	message, err := db.GetMessage(params.Message)
+	if errors.Is(err, sql.ErrNoRows) {
+		return conversation, message, false, nil
+	}
+
+	if err != nil {
+		return conversation, message, false, err
+	}
+
+	if message.ConversationID != conversation.ID || message.CreatedAt.Before(conversation.JoinedAt) {
+		return conversation, message, false, nil
+	}
+
+	return conversation, message, true, nil
+}
@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/helpers"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/texthelpers"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/MangriMen/Diverse-Back/internal/parameters"
+	"github.com/MangriMen/Diverse-Back/internal/responses"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/samber/lo"
+)
+
+// swagger:route GET /users/{user}/muted-words User getMutedWords
+// Returns active muted words of the user
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetMutedWordsResponse
+//   default: ErrorResponse
+
+// GetMutedWords is used to fetch not expired muted words of the requester.
+func GetMutedWords(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	userIDParams, err := helpers.GetParamsAndValidate[parameters.UserIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if userID != userIDParams.User {
+		return helpers.Response(c, fiber.StatusForbidden, configs.ForbiddenError)
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	dbMutedWords, err := db.GetMutedWords(userID, time.Now())
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	mutedWordsToSend := lo.Map(dbMutedWords, func(item models.DBMutedWord, index int) models.MutedWord {
+		return item.MutedWord
+	})
+
+	return c.JSON(responses.GetMutedWordsResponseBody{
+		Count: len(mutedWordsToSend),
+		Data:  mutedWordsToSend,
+	})
+}
+
+// swagger:route POST /users/{user}/muted-words User muteWord
+// Mute the word, phrase or hashtag in posts and comments shown to the user
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   201: GetMutedWordResponse
+//   default: ErrorResponse
+
+// MuteWord is used to add the muted word of the requester.
+func MuteWord(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	userIDParams, err := helpers.GetParamsAndValidate[parameters.UserIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	mutedWordCreateRequestBody, err := helpers.GetBodyAndValidate[parameters.MutedWordCreateRequestBody](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if userID != userIDParams.User {
+		return helpers.Response(c, fiber.StatusForbidden, configs.ForbiddenError)
+	}
+
+	newMutedWord := &models.DBMutedWord{
+		MutedWord: models.MutedWord{
+			ID:        uuid.New(),
+			Phrase:    texthelpers.Normalize(mutedWordCreateRequestBody.Phrase),
+			WholeWord: lo.FromPtrOr(mutedWordCreateRequestBody.WholeWord, true),
+			Scopes: lo.Uniq(lo.Ternary(
+				len(mutedWordCreateRequestBody.Scopes) == 0,
+				models.MutedWordScopes{models.FeedScope, models.CommentsScope, models.NotificationsScope},
+				mutedWordCreateRequestBody.Scopes,
+			)),
+			Action:    helpers.GetNotEmpty(mutedWordCreateRequestBody.Action, models.HideMuted),
+			CreatedAt: time.Now(),
+		},
+		UserID: userID,
+	}
+
+	if mutedWordCreateRequestBody.ExpiresIn > 0 {
+		newMutedWord.ExpiresAt = helpers.Ptr(
+			newMutedWord.CreatedAt.Add(time.Duration(mutedWordCreateRequestBody.ExpiresIn) * time.Second),
+		)
+	}
+
+	validate := helpers.NewValidator()
+	if err = validate.Struct(newMutedWord); err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, helpers.ValidatorErrors(err))
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	created, err := db.CreateMutedWord(newMutedWord)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == configs.DBDuplicateError {
+			return helpers.Response(c, fiber.StatusConflict, configs.MutedWordExistsError)
+		}
+
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if !created {
+		return helpers.Response(c, fiber.StatusConflict, fmt.Sprintf(
+			configs.MutedWordLimitErrorFormat,
+			configs.MutedWordsMaxCount,
+		))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(responses.GetMutedWordResponseBody{
+		Data: newMutedWord.MutedWord,
+	})
+}
+
+// swagger:route DELETE /users/{user}/muted-words/{mutedWord} User unmuteWord
+// Unmute the word
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   204: DeleteMutedWordResponse
+//   default: ErrorResponse
+
+// UnmuteWord is used to delete the muted word of the requester.
+func UnmuteWord(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	mutedWordIDParams, err := helpers.GetParamsAndValidate[parameters.MutedWordIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if userID != mutedWordIDParams.User {
+		return helpers.Response(c, fiber.StatusForbidden, configs.ForbiddenError)
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	deleted, err := db.DeleteMutedWord(userID, mutedWordIDParams.MutedWord)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if !deleted {
+		return helpers.Response(c, fiber.StatusNotFound, configs.MutedWordNotFoundError)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
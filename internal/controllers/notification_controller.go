@@ -0,0 +1,206 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/helpers"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/cursorhelpers"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/notificationhelpers"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/posthelpers"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/MangriMen/Diverse-Back/internal/parameters"
+	"github.com/MangriMen/Diverse-Back/internal/realtime"
+	"github.com/MangriMen/Diverse-Back/internal/responses"
+	"github.com/gofiber/fiber/v2"
+	"github.com/samber/lo"
+)
+
+// swagger:route GET /notifications Notification getNotifications
+// Returns a page of notifications of the requester, latest events first
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetNotificationsResponse
+//   default: ErrorResponse
+
+// GetNotifications is used to fetch notifications of the requester.
+func GetNotifications(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	notificationsFetchRequestQuery, err := helpers.GetQueryAndValidate[parameters.NotificationsFetchRequestQuery](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	cursor, err := cursorhelpers.Decode[parameters.NotificationsCursor](notificationsFetchRequestQuery.Cursor)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, configs.InvalidCursorError)
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	dbNotifications, err := db.GetNotifications(
+		userID,
+		notificationsFetchRequestQuery.UnreadOnly,
+		cursor,
+		notificationsFetchRequestQuery.Count,
+	)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	mutedWordsFilter, err := posthelpers.NewMutedWordsFilter(userID, models.NotificationsScope, db)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	notificationsToSend := lo.FilterMap(
+		dbNotifications,
+		func(item models.DBNotification, index int) (models.Notification, bool) {
+			notification := notificationhelpers.PrepareNotificationToSend(item, db)
+			return notification, mutedWordsFilter.FilterNotification(
+				&notification,
+				notificationhelpers.GetNotificationTexts(item, db)...,
+			)
+		},
+	)
+
+	unreadCount, err := db.GetUnreadNotificationsCount(userID)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	nextCursor := ""
+	if len(dbNotifications) == notificationsFetchRequestQuery.Count {
+		last := dbNotifications[len(dbNotifications)-1]
+
+		nextCursor, err = cursorhelpers.Encode(parameters.NotificationsCursor{
+			UpdatedAt:      last.UpdatedAt,
+			NotificationID: last.ID,
+		})
+		if err != nil {
+			return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return c.JSON(responses.GetNotificationsResponseBody{
+		Count:       len(notificationsToSend),
+		Data:        notificationsToSend,
+		UnreadCount: unreadCount,
+		NextCursor:  nextCursor,
+	})
+}
+
+// swagger:route GET /notifications/count Notification getNotificationsCount
+// Returns a count of unread notifications of the requester
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetNotificationsCountResponse
+//   default: ErrorResponse
+
+// GetNotificationsCount is used to fetch the number of unread notifications of the requester.
+func GetNotificationsCount(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	unreadCount, err := db.GetUnreadNotificationsCount(userID)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(responses.GetNotificationsCountResponseBody{
+		Count: unreadCount,
+	})
+}
+
+// swagger:route POST /notifications/{notification}/read Notification readNotification
+// Mark the notification as read
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   204: ReadNotificationResponse
+//   default: ErrorResponse
+
+// ReadNotification is used to mark the notification of the requester as read.
+func ReadNotification(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	notificationIDParams, err := helpers.GetParamsAndValidate[parameters.NotificationIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	read, err := db.ReadNotification(userID, notificationIDParams.Notification, time.Now())
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if !read {
+		return helpers.Response(c, fiber.StatusNotFound, configs.NotificationNotFoundError)
+	}
+
+	realtime.PublishNotificationsRead(userID, db)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// swagger:route POST /notifications/read Notification readAllNotifications
+// Mark all notifications as read
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   204: ReadNotificationResponse
+//   default: ErrorResponse
+
+// ReadAllNotifications is used to mark all notifications of the requester as read.
+func ReadAllNotifications(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if err = db.ReadAllNotifications(userID, time.Now()); err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	realtime.PublishNotificationsRead(userID, db)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/helpers"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/MangriMen/Diverse-Back/internal/parameters"
+	"github.com/MangriMen/Diverse-Back/internal/responses"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// swagger:route GET /notifications/preferences Notification getNotificationPreferences
+// Returns whether notifications of each type are delivered through each channel
+// and settings of their delivery
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetNotificationPreferencesResponse
+//   default: ErrorResponse
+
+// GetNotificationPreferences is used to fetch notification preferences and settings of the requester.
+func GetNotificationPreferences(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return sendNotificationPreferences(c, userID, db)
+}
+
+// swagger:route PUT /notifications/preferences Notification updateNotificationPreferences
+// Opt out of notifications of the given types and channels or back in,
+// change the timezone, quiet hours or frequency of email digests
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetNotificationPreferencesResponse
+//   default: ErrorResponse
+
+// UpdateNotificationPreferences is used to change notification preferences and settings of the requester.
+func UpdateNotificationPreferences(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	preferencesUpdateRequestBody, err :=
+		helpers.GetBodyAndValidate[parameters.NotificationPreferencesUpdateRequestBody](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if preferencesUpdateRequestBody.Timezone != nil {
+		valid, validErr := isValidTimezone(*preferencesUpdateRequestBody.Timezone, db)
+		if validErr != nil {
+			return helpers.Response(c, fiber.StatusInternalServerError, validErr.Error())
+		}
+
+		if !valid {
+			return helpers.Response(c, fiber.StatusBadRequest, configs.InvalidTimezoneError)
+		}
+	}
+
+	if len(preferencesUpdateRequestBody.Preferences) > 0 {
+		err = db.UpdateNotificationOptOuts(userID, preferencesUpdateRequestBody.Preferences)
+		if err != nil {
+			return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+		}
+	}
+
+	settings, err := db.GetNotificationSettings(userID)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if preferencesUpdateRequestBody.Timezone != nil {
+		settings.Timezone = *preferencesUpdateRequestBody.Timezone
+	}
+
+	if preferencesUpdateRequestBody.Digest != nil {
+		settings.Digest = *preferencesUpdateRequestBody.Digest
+	}
+
+	if quietHours := preferencesUpdateRequestBody.QuietHours; quietHours != nil {
+		settings.QuietHoursStart, settings.QuietHoursEnd = nil, nil
+
+		if quietHours.Enabled {
+			start, startErr := models.ParseClockMinutes(quietHours.Start)
+			end, endErr := models.ParseClockMinutes(quietHours.End)
+			if startErr != nil || endErr != nil {
+				return helpers.Response(c, fiber.StatusBadRequest, configs.InvalidQuietHoursError)
+			}
+
+			settings.QuietHoursStart, settings.QuietHoursEnd = &start, &end
+		}
+	}
+
+	if err = db.UpdateNotificationSettings(&settings); err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return sendNotificationPreferences(c, userID, db)
+}
+
+// sendNotificationPreferences sends preferences of the user for each type and channel of notifications
+// and settings of their delivery.
+func sendNotificationPreferences(c *fiber.Ctx, userID uuid.UUID, db *database.Queries) error {
+	optOuts, err := db.GetNotificationOptOuts(userID)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	settings, err := db.GetNotificationSettings(userID)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	preferences := lo.FlatMap(
+		models.NotificationTypes(),
+		func(notificationType models.NotificationType, index int) []models.NotificationPreference {
+			return lo.Map(
+				models.NotificationChannels(),
+				func(channel models.NotificationChannel, index int) models.NotificationPreference {
+					return models.NotificationPreference{
+						Type:    notificationType,
+						Channel: channel,
+						Enabled: !lo.ContainsBy(optOuts, func(item models.NotificationPreference) bool {
+							return item.Type == notificationType && item.Channel == channel
+						}),
+					}
+				},
+			)
+		},
+	)
+
+	return c.JSON(responses.GetNotificationPreferencesResponseBody{
+		Preferences: preferences,
+		Settings:    settings.ToNotificationSettings(),
+	})
+}
+
+// isValidTimezone reports whether the timezone is an IANA name known both to Go and to the database,
+// which computes local hours of digests. "Local" is rejected, since it's the timezone of the server.
+func isValidTimezone(timezone string, db *database.Queries) (bool, error) {
+	if timezone == "" || timezone == "Local" {
+		return false, nil
+	}
+
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return false, nil
+	}
+
+	return db.IsKnownTimezone(timezone)
+}
@@ -4,14 +4,19 @@ package controllers
 import (
 	"errors"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/MangriMen/Diverse-Back/api/database"
 	"github.com/MangriMen/Diverse-Back/configs"
 	"github.com/MangriMen/Diverse-Back/internal/helpers"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/cursorhelpers"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/notificationhelpers"
 	"github.com/MangriMen/Diverse-Back/internal/helpers/posthelpers"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/texthelpers"
 	"github.com/MangriMen/Diverse-Back/internal/models"
 	"github.com/MangriMen/Diverse-Back/internal/parameters"
+	"github.com/MangriMen/Diverse-Back/internal/realtime"
 	"github.com/MangriMen/Diverse-Back/internal/responses"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -48,6 +53,22 @@ func GetPostsCount(c *fiber.Ctx) error {
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
 	}
 
+	sensitiveFilter, err := posthelpers.NewSensitiveContentFilter(userID, db)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if postsFetchCountRequestQuery.Type == parameters.Subscriptions {
+		postsCount, countErr := db.GetTimelinePostsCount(userID, sensitiveFilter.Preference)
+		if countErr != nil {
+			return helpers.Response(c, fiber.StatusInternalServerError, countErr.Error())
+		}
+
+		return c.JSON(responses.GetPostsCountResponseBody{
+			Count: postsCount,
+		})
+	}
+
 	filter, err := posthelpers.GenerateFilter(
 		userID,
 		postsFetchCountRequestQuery.Type,
@@ -58,7 +79,7 @@ func GetPostsCount(c *fiber.Ctx) error {
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
 	}
 
-	postsCount, err := db.GetPostsCount(filter)
+	postsCount, err := db.GetPostsCount(userID, filter+"\n"+sensitiveFilter.Condition())
 	if err != nil {
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
 	}
@@ -99,24 +120,76 @@ func GetPosts(c *fiber.Ctx) error {
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
 	}
 
-	filter, err := posthelpers.GenerateFilter(
-		userID,
-		postsFetchRequestQuery.Type,
-		postsFetchRequestQuery.UserID,
-		db,
-	)
+	sensitiveFilter, err := posthelpers.NewSensitiveContentFilter(userID, db)
 	if err != nil {
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
 	}
 
-	dbPosts, err := db.GetPosts(postsFetchRequestQuery, filter)
+	mutedWordsFilter, err := posthelpers.NewMutedWordsFilter(userID, models.FeedScope, db)
 	if err != nil {
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
 	}
 
-	postsToSend := lo.Map(dbPosts, func(item models.DBPost, index int) models.Post {
-		return posthelpers.PreparePostToSend(item, userID, db)
-	})
+	mutedCommentWordsFilter, err := posthelpers.NewMutedWordsFilter(userID, models.CommentsScope, db)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	filters := viewerFilters{
+		sensitive:         sensitiveFilter,
+		mutedWords:        mutedWordsFilter,
+		mutedCommentWords: mutedCommentWordsFilter,
+	}
+
+	if postsFetchRequestQuery.Type == parameters.All {
+		return getRankedPosts(c, userID, postsFetchRequestQuery, filters, db)
+	}
+
+	var dbPosts, dbPinnedPosts []models.DBPost
+	if postsFetchRequestQuery.Type == parameters.Subscriptions {
+		dbPosts, err = db.GetTimelinePosts(userID, postsFetchRequestQuery, sensitiveFilter.Preference)
+		if err != nil {
+			return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+		}
+	} else {
+		filter, filterErr := posthelpers.GenerateFilter(
+			userID,
+			postsFetchRequestQuery.Type,
+			postsFetchRequestQuery.UserID,
+			db,
+		)
+		if filterErr != nil {
+			return helpers.Response(c, fiber.StatusInternalServerError, filterErr.Error())
+		}
+
+		if postsFetchRequestQuery.Type == parameters.User {
+			filter += "\n" + posthelpers.GenerateNotPinnedFilter(postsFetchRequestQuery.UserID)
+
+			if postsFetchRequestQuery.LastSeenPostID == uuid.Nil {
+				dbPinnedPosts, err = db.GetPinnedPosts(postsFetchRequestQuery.UserID)
+				if err != nil {
+					return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+				}
+			}
+		}
+
+		dbPosts, err = db.GetPosts(userID, postsFetchRequestQuery, filter+"\n"+sensitiveFilter.Condition())
+		if err != nil {
+			return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+		}
+	}
+
+	commentsSort := postsFetchRequestQuery.CommentsSort
+
+	pinnedPostsToSend := preparePostsToSend(dbPinnedPosts, userID, commentsSort, filters, db)
+	for i := range pinnedPostsToSend {
+		pinnedPostsToSend[i].Pinned = true
+	}
+
+	postsToSend := append(
+		pinnedPostsToSend,
+		preparePostsToSend(dbPosts, userID, commentsSort, filters, db)...,
+	)
 
 	return c.JSON(responses.GetPostsResponseBody{
 		Count: len(postsToSend),
@@ -124,6 +197,101 @@ func GetPosts(c *fiber.Ctx) error {
 	})
 }
 
+// viewerFilters combines the preferences of the requester applied to fetched posts.
+type viewerFilters struct {
+	sensitive         posthelpers.SensitiveContentFilter
+	mutedWords        posthelpers.MutedWordsFilter
+	mutedCommentWords posthelpers.MutedWordsFilter
+}
+
+// preparePostsToSend prepares posts with comments in the given order for sending,
+// skipping posts hidden from the requester and marking posts to blur or collapse.
+func preparePostsToSend(
+	dbPosts []models.DBPost,
+	userID uuid.UUID,
+	commentsSort parameters.CommentSort,
+	filters viewerFilters,
+	db *database.Queries,
+) []models.Post {
+	return lo.FilterMap(dbPosts, func(item models.DBPost, index int) (models.Post, bool) {
+		if filters.sensitive.Hides(item) {
+			return models.Post{}, false
+		}
+
+		post := posthelpers.PreparePostWithCommentsToSend(item, userID, commentsSort, db)
+		post.Blurred = filters.sensitive.Blurs(item)
+		return post, filters.mutedWords.FilterPost(&post, item.UserID, filters.mutedCommentWords)
+	})
+}
+
+// getRankedPosts is used to fetch the next page of the ranked feed session
+// or to start a new session if the cursor is empty or its session has expired.
+func getRankedPosts(
+	c *fiber.Ctx,
+	userID uuid.UUID,
+	postsFetchRequestQuery *parameters.PostsFetchRequestQuery,
+	filters viewerFilters,
+	db *database.Queries,
+) error {
+	cursor, err := cursorhelpers.Decode[parameters.FeedCursor](postsFetchRequestQuery.Cursor)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, configs.InvalidCursorError)
+	}
+
+	if cursor != nil {
+		snapshotExists, existsErr := db.FeedSnapshotExists(cursor.SnapshotID, userID)
+		if existsErr != nil {
+			return helpers.Response(c, fiber.StatusInternalServerError, existsErr.Error())
+		}
+
+		if !snapshotExists {
+			cursor = nil
+		}
+	}
+
+	if cursor == nil {
+		cursor = &parameters.FeedCursor{SnapshotID: uuid.New()}
+		if err = db.CreateFeedSnapshot(cursor.SnapshotID, userID, time.Now()); err != nil {
+			return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+		}
+	}
+
+	dbPosts, err := db.GetFeedPosts(
+		cursor.SnapshotID,
+		userID,
+		cursor.Position,
+		postsFetchRequestQuery.Count,
+	)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	postsToSend := preparePostsToSend(
+		lo.Map(dbPosts, func(item models.DBFeedPost, index int) models.DBPost {
+			return item.DBPost
+		}),
+		userID,
+		postsFetchRequestQuery.CommentsSort,
+		filters,
+		db,
+	)
+
+	nextCursor := ""
+	if len(dbPosts) == postsFetchRequestQuery.Count {
+		cursor.Position = dbPosts[len(dbPosts)-1].Position
+		nextCursor, err = cursorhelpers.Encode(cursor)
+		if err != nil {
+			return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return c.JSON(responses.GetPostsResponseBody{
+		Count:      len(postsToSend),
+		Data:       postsToSend,
+		NextCursor: nextCursor,
+	})
+}
+
 // swagger:route GET /posts/{post} Post getPost
 // Returns the post by given ID
 //
@@ -146,6 +314,11 @@ func GetPost(c *fiber.Ctx) error {
 		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
 	}
 
+	postFetchRequestQuery, err := helpers.GetQueryAndValidate[parameters.PostFetchRequestQuery](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
 	db, err := database.OpenDBConnection()
 	if err != nil {
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
@@ -156,7 +329,36 @@ func GetPost(c *fiber.Ctx) error {
 		return helpers.Response(c, fiber.StatusNotFound, configs.PostNotFoundError)
 	}
 
-	postToSend := posthelpers.PreparePostToSend(dbPost, userID, db)
+	sensitiveFilter, err := posthelpers.NewSensitiveContentFilter(userID, db)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if err = posthelpers.CheckVisibility(dbPost, sensitiveFilter, db); err != nil {
+		return helpers.Response(c, fiber.StatusForbidden, err.Error())
+	}
+
+	postToSend := posthelpers.PreparePostWithCommentsToSend(
+		dbPost,
+		userID,
+		postFetchRequestQuery.CommentsSort,
+		db,
+	)
+	postToSend.Blurred = sensitiveFilter.Blurs(dbPost)
+
+	mutedWordsFilter, err := posthelpers.NewMutedWordsFilter(userID, models.FeedScope, db)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	mutedCommentWordsFilter, err := posthelpers.NewMutedWordsFilter(userID, models.CommentsScope, db)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if !mutedWordsFilter.FilterPost(&postToSend, dbPost.UserID, mutedCommentWordsFilter) {
+		return helpers.Response(c, fiber.StatusForbidden, configs.PostMutedError)
+	}
 
 	return c.JSON(responses.GetPostResponseBody{
 		Data: postToSend,
@@ -185,13 +387,26 @@ func CreatePost(c *fiber.Ctx) error {
 		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
 	}
 
+	language, err := posthelpers.GetLanguageOrDefault(postCreateRequestBody.Language)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
 	newPost := &models.DBPost{
 		BasePost: models.BasePost{
-			ID:          uuid.New(),
-			Content:     postCreateRequestBody.Content,
-			Description: postCreateRequestBody.Description,
-			Likes:       0,
-			CreatedAt:   time.Now(),
+			ID:             uuid.New(),
+			Content:        postCreateRequestBody.Content,
+			Description:    texthelpers.Normalize(postCreateRequestBody.Description),
+			Likes:          0,
+			CreatedAt:      time.Now(),
+			Language:       language,
+			ContentWarning: postCreateRequestBody.ContentWarning,
+			Sensitive:      postCreateRequestBody.Sensitive,
+			CommentPolicy: helpers.GetNotEmpty(
+				postCreateRequestBody.CommentPolicy,
+				models.CommentsFromEveryone,
+			),
+			Markdown: postCreateRequestBody.Markdown,
 		},
 		UserID: userID,
 	}
@@ -206,25 +421,75 @@ func CreatePost(c *fiber.Ctx) error {
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
 	}
 
-	if err = db.CreatePost(newPost); err != nil {
+	newPost.Entities = posthelpers.ParseEntities(newPost.Description, db)
+
+	var newPoll *models.DBPoll
+	var newPollOptions []models.DBPollOption
+	if postCreateRequestBody.Poll != nil {
+		newPoll, newPollOptions = newPostPoll(newPost, postCreateRequestBody.Poll)
+	}
+
+	if err = db.CreatePost(newPost, newPoll, newPollOptions); err != nil {
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
 	}
 
+	// The post is already created, the request doesn't fail so the client doesn't create it again
+	if err = db.FanOutPost(newPost); err != nil {
+		log.Printf("Post is not fanned out to timelines. Reason: %v", err)
+	}
+
+	realtime.PublishFeedPost(*newPost, db)
+
+	notificationhelpers.NotifyMentions(newPost.Description, newPost.ID, nil, userID, db)
+
 	return c.SendStatus(fiber.StatusCreated)
 }
 
-// swagger:route PATCH /posts/{post} Post updatePost
-// Update post by ID with given fields
+// newPostPoll creates the poll and its options from the request body of the new post.
+func newPostPoll(
+	post *models.DBPost,
+	pollCreateRequestBody *parameters.PollCreateRequestBody,
+) (*models.DBPoll, []models.DBPollOption) {
+	newPoll := &models.DBPoll{
+		ID:          uuid.New(),
+		PostID:      post.ID,
+		Multiple:    pollCreateRequestBody.Multiple,
+		Anonymous:   pollCreateRequestBody.Anonymous,
+		ShowResults: pollCreateRequestBody.ShowResults,
+		CreatedAt:   post.CreatedAt,
+	}
+
+	if pollCreateRequestBody.ExpiresIn > 0 {
+		newPoll.ExpiresAt = helpers.Ptr(
+			post.CreatedAt.Add(time.Duration(pollCreateRequestBody.ExpiresIn) * time.Second),
+		)
+	}
+
+	newPollOptions := lo.Map(pollCreateRequestBody.Options, func(item string, index int) models.DBPollOption {
+		return models.DBPollOption{
+			ID:       uuid.New(),
+			PollID:   newPoll.ID,
+			Position: index + 1,
+			Text:     item,
+		}
+	})
+
+	return newPoll, newPollOptions
+}
+
+// swagger:route POST /posts/{post}/poll/votes Post votePoll
+// Vote in the poll attached to the post
 //
 // Security:
 //   bearerAuth:
 //
 // Responses:
-//   201: GetPostResponse
+//   200: GetPostResponse
 //   default: ErrorResponse
 
-// UpdatePost is used to update the post by ID.
-func UpdatePost(c *fiber.Ctx) error {
+// VotePoll is used to vote for one or, in multiple choice polls, several options of the poll.
+// Every user can vote once.
+func VotePoll(c *fiber.Ctx) error {
 	userID, err := helpers.GetUserIDFromToken(c)
 	if err != nil {
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
@@ -235,7 +500,7 @@ func UpdatePost(c *fiber.Ctx) error {
 		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
 	}
 
-	postUpdateRequestBody, err := helpers.GetBodyAndValidate[parameters.PostUpdateRequestBody](c)
+	pollVoteRequestBody, err := helpers.GetBodyAndValidate[parameters.PollVoteRequestBody](c)
 	if err != nil {
 		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
 	}
@@ -250,33 +515,54 @@ func UpdatePost(c *fiber.Ctx) error {
 		return helpers.Response(c, fiber.StatusNotFound, configs.PostNotFoundError)
 	}
 
-	if userID != foundPost.UserID {
-		return helpers.Response(c, fiber.StatusForbidden, configs.ForbiddenError)
+	sensitiveFilter, err := posthelpers.NewSensitiveContentFilter(userID, db)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
 	}
 
-	if foundPost.CreatedAt.Add(configs.PostEditTimeSinceCreated).UTC().
-		Before(time.Now().UTC()) {
-		return helpers.Response(c, fiber.StatusForbidden, fmt.Sprintf(
-			configs.CantEditAfterErrorFormat,
-			"post",
-			configs.PostEditTimeSinceCreated.String(),
-		))
+	if err = posthelpers.CheckVisibility(foundPost, sensitiveFilter, db); err != nil {
+		return helpers.Response(c, fiber.StatusForbidden, err.Error())
 	}
 
-	foundPost.Description = helpers.GetNotEmpty(
-		postUpdateRequestBody.Description,
-		foundPost.Description,
-	)
+	poll, err := db.GetPostPoll(foundPost.ID)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusNotFound, configs.PollNotFoundError)
+	}
 
-	validate := helpers.NewValidator()
-	if err = validate.Struct(foundPost); err != nil {
-		return helpers.Response(c, fiber.StatusBadRequest, helpers.ValidatorErrors(err))
+	votedAt := time.Now()
+	if poll.IsClosed(votedAt) {
+		return helpers.Response(c, fiber.StatusConflict, configs.PollClosedError)
 	}
 
-	if err = db.UpdatePost(&foundPost); err != nil {
+	if !poll.Multiple && len(pollVoteRequestBody.Options) > 1 {
+		return helpers.Response(c, fiber.StatusBadRequest, configs.PollSingleChoiceError)
+	}
+
+	if len(lo.Uniq(pollVoteRequestBody.Options)) != len(pollVoteRequestBody.Options) {
+		return helpers.Response(c, fiber.StatusBadRequest, configs.PollDuplicateOptionError)
+	}
+
+	options, err := db.GetPollOptions(poll.ID)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	optionIDs := lo.Map(options, func(item models.DBPollOption, index int) uuid.UUID {
+		return item.ID
+	})
+	if !lo.Every(optionIDs, pollVoteRequestBody.Options) {
+		return helpers.Response(c, fiber.StatusBadRequest, configs.PollInvalidOptionError)
+	}
+
+	voted, err := db.VotePoll(&poll, userID, pollVoteRequestBody.Options, votedAt)
+	if err != nil {
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
 	}
 
+	if !voted {
+		return helpers.Response(c, fiber.StatusConflict, configs.PollAlreadyVotedError)
+	}
+
 	postToSend := posthelpers.PreparePostToSend(foundPost, userID, db)
 
 	return c.JSON(responses.GetPostResponseBody{
@@ -284,18 +570,18 @@ func UpdatePost(c *fiber.Ctx) error {
 	})
 }
 
-// swagger:route POST /posts/{post}/like Post likePost
-// Set like to the post by ID
+// swagger:route POST /posts/{post}/pin Post pinPost
+// Pin own post to the top of the profile
 //
 // Security:
 //   bearerAuth:
 //
 // Responses:
-//   201: GetPostResponse
+//   200: GetPostResponse
 //   default: ErrorResponse
 
-// LikePost is used to like the post by ID.
-func LikePost(c *fiber.Ctx) error {
+// PinPost is used to pin the post by ID after already pinned posts.
+func PinPost(c *fiber.Ctx) error {
 	userID, err := helpers.GetUserIDFromToken(c)
 	if err != nil {
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
@@ -311,45 +597,52 @@ func LikePost(c *fiber.Ctx) error {
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
 	}
 
-	like := &models.DBPostLike{
-		ID:     uuid.New(),
-		PostID: postIDParams.Post,
-		UserID: userID,
+	foundPost, err := db.GetPost(postIDParams.Post)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusNotFound, configs.PostNotFoundError)
 	}
 
-	if err = db.LikePost(like); err != nil {
+	if userID != foundPost.UserID {
+		return helpers.Response(c, fiber.StatusForbidden, configs.ForbiddenError)
+	}
+
+	pinned, err := db.PinPost(userID, foundPost.ID)
+	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == configs.DBDuplicateError {
-			return helpers.Response(c, fiber.StatusConflict, err.Error())
+			return helpers.Response(c, fiber.StatusConflict, configs.PostAlreadyPinnedError)
 		}
 
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
 	}
 
-	foundPost, err := db.GetPost(like.PostID)
-	if err != nil {
-		return helpers.Response(c, fiber.StatusNotFound, configs.PostNotFoundError)
+	if !pinned {
+		return helpers.Response(c, fiber.StatusConflict, fmt.Sprintf(
+			configs.PostPinLimitErrorFormat,
+			configs.PostMaxPinned,
+		))
 	}
 
 	postToSend := posthelpers.PreparePostToSend(foundPost, userID, db)
+	postToSend.Pinned = true
 
 	return c.JSON(responses.GetPostResponseBody{
 		Data: postToSend,
 	})
 }
 
-// swagger:route DELETE /posts/{post}/like Post unlikePost
-// Unset like to the post by ID
+// swagger:route DELETE /posts/{post}/pin Post unpinPost
+// Unpin own post from the top of the profile
 //
 // Security:
 //   bearerAuth:
 //
 // Responses:
-//   201: GetPostResponse
+//   200: GetPostResponse
 //   default: ErrorResponse
 
-// UnlikePost is used to unlike the post by ID.
-func UnlikePost(c *fiber.Ctx) error {
+// UnpinPost is used to unpin the post by ID.
+func UnpinPost(c *fiber.Ctx) error {
 	userID, err := helpers.GetUserIDFromToken(c)
 	if err != nil {
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
@@ -365,18 +658,18 @@ func UnlikePost(c *fiber.Ctx) error {
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
 	}
 
-	like := &models.DBPostLike{
-		PostID: postIDParams.Post,
-		UserID: userID,
+	foundPost, err := db.GetPost(postIDParams.Post)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusNotFound, configs.PostNotFoundError)
 	}
 
-	if err = db.UnlikePost(like); err != nil {
+	unpinned, err := db.UnpinPost(userID, foundPost.ID)
+	if err != nil {
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
 	}
 
-	foundPost, err := db.GetPost(like.PostID)
-	if err != nil {
-		return helpers.Response(c, fiber.StatusNotFound, configs.PostNotFoundError)
+	if !unpinned {
+		return helpers.Response(c, fiber.StatusNotFound, configs.PostNotPinnedError)
 	}
 
 	postToSend := posthelpers.PreparePostToSend(foundPost, userID, db)
@@ -386,50 +679,886 @@ func UnlikePost(c *fiber.Ctx) error {
 	})
 }
 
-// swagger:route DELETE /posts/{post} Post deletePost
-// Delete post by ID
-//
-// Schemes: http, https
-//
-// Produces:
-//   - application/json
+// swagger:route PUT /posts/pins Post updatePinnedPosts
+// Reorder pinned posts of the requester
 //
 // Security:
 //   bearerAuth:
 //
 // Responses:
-//   204: DeletePostResponse
+//   200: GetPostsResponse
 //   default: ErrorResponse
 
-// DeletePost is used to delete the post by ID.
-func DeletePost(c *fiber.Ctx) error {
+// UpdatePinnedPosts is used to set the order of the pinned posts.
+func UpdatePinnedPosts(c *fiber.Ctx) error {
 	userID, err := helpers.GetUserIDFromToken(c)
 	if err != nil {
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
 	}
 
-	postIDParams, err := helpers.GetParamsAndValidate[parameters.PostIDParams](c)
+	pinnedPostsUpdateRequestBody, err := helpers.GetBodyAndValidate[parameters.PinnedPostsUpdateRequestBody](
+		c,
+	)
 	if err != nil {
-		return helpers.Response(c, fiber.StatusBadRequest, err)
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
 	}
 
 	db, err := database.OpenDBConnection()
 	if err != nil {
-		return helpers.Response(c, fiber.StatusInternalServerError, err)
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
 	}
 
-	foundPost, err := db.GetPost(postIDParams.Post)
+	reordered, err := db.ReorderPinnedPosts(userID, pinnedPostsUpdateRequestBody.Posts)
 	if err != nil {
-		return helpers.Response(c, fiber.StatusNotFound, configs.PostNotFoundError)
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
 	}
 
-	if userID != foundPost.UserID {
-		return helpers.Response(c, fiber.StatusForbidden, configs.ForbiddenError)
+	if !reordered {
+		return helpers.Response(c, fiber.StatusBadRequest, configs.PostPinsMismatchError)
 	}
 
-	if err = db.DeletePost(foundPost.ID); err != nil {
+	dbPinnedPosts, err := db.GetPinnedPosts(userID)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	postsToSend := lo.Map(dbPinnedPosts, func(item models.DBPost, index int) models.Post {
+		post := posthelpers.PreparePostToSend(item, userID, db)
+		post.Pinned = true
+		return post
+	})
+
+	return c.JSON(responses.GetPostsResponseBody{
+		Count: len(postsToSend),
+		Data:  postsToSend,
+	})
+}
+
+// swagger:route POST /posts/{post}/view Post viewPost
+// Record the view of the post by the requester
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   204: ViewPostsResponse
+//   default: ErrorResponse
+
+// ViewPost is used to record the view of the post by ID.
+func ViewPost(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	postIDParams, err := helpers.GetParamsAndValidate[parameters.PostIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if err = db.TrackPostViews(userID, []uuid.UUID{postIDParams.Post}, time.Now()); err != nil {
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
 	}
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
+
+// swagger:route POST /posts/views Post viewPosts
+// Record views of the posts seen by the requester during the feed scroll
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   204: ViewPostsResponse
+//   default: ErrorResponse
+
+// ViewPosts is used to record views of the several posts at once.
+func ViewPosts(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	postsViewRequestBody, err := helpers.GetBodyAndValidate[parameters.PostsViewRequestBody](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if err = db.TrackPostViews(userID, postsViewRequestBody.Posts, time.Now()); err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// swagger:route GET /posts/{post}/insights Post getPostInsights
+// Returns views, unique viewers, likes and comments of own post by hours
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetPostInsightsResponse
+//   default: ErrorResponse
+
+// GetPostInsights is used to fetch statistics of the post by ID for its author,
+// including archived posts.
+func GetPostInsights(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	postIDParams, err := helpers.GetParamsAndValidate[parameters.PostIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	postInsightsRequestQuery, err := helpers.GetQueryAndValidate[parameters.PostInsightsRequestQuery](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if postInsightsRequestQuery.To.IsZero() {
+		postInsightsRequestQuery.To = time.Now()
+	}
+
+	if postInsightsRequestQuery.From.IsZero() {
+		postInsightsRequestQuery.From = postInsightsRequestQuery.To.Add(-configs.PostInsightsDefaultPeriod)
+	}
+
+	period := postInsightsRequestQuery.To.Sub(postInsightsRequestQuery.From)
+	if period <= 0 || period > configs.PostInsightsMaxPeriod {
+		return helpers.Response(c, fiber.StatusBadRequest, configs.InsightsInvalidPeriod)
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	foundPost, err := db.GetPost(postIDParams.Post)
+	if err != nil {
+		archivedPost, archivedErr := db.GetArchivedPost(postIDParams.Post)
+		if archivedErr != nil || userID != archivedPost.UserID {
+			return helpers.Response(c, fiber.StatusNotFound, configs.PostNotFoundError)
+		}
+
+		foundPost = archivedPost.DBPost
+	}
+
+	if userID != foundPost.UserID {
+		return helpers.Response(c, fiber.StatusForbidden, configs.ForbiddenError)
+	}
+
+	insights, err := db.GetPostInsights(
+		foundPost.ID,
+		postInsightsRequestQuery.From,
+		postInsightsRequestQuery.To,
+	)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.JSON(responses.GetPostInsightsResponseBody{
+		Data: insights,
+	})
+}
+
+// swagger:route PATCH /posts/{post} Post updatePost
+// Update post by ID with given fields, the description can only be changed for a while after posting
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   201: GetPostResponse
+//   default: ErrorResponse
+
+// UpdatePost is used to update the post by ID.
+func UpdatePost(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	postIDParams, err := helpers.GetParamsAndValidate[parameters.PostIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	postUpdateRequestBody, err := helpers.GetBodyAndValidate[parameters.PostUpdateRequestBody](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	foundPost, err := db.GetPost(postIDParams.Post)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusNotFound, configs.PostNotFoundError)
+	}
+
+	if userID != foundPost.UserID {
+		return helpers.Response(c, fiber.StatusForbidden, configs.ForbiddenError)
+	}
+
+	description := texthelpers.Normalize(postUpdateRequestBody.Description)
+
+	// Only the content is locked after the edit time, the comment policy can be changed any time
+	contentChanged := description != "" || postUpdateRequestBody.Markdown != nil
+	if contentChanged && foundPost.CreatedAt.Add(configs.PostEditTimeSinceCreated).UTC().
+		Before(time.Now().UTC()) {
+		return helpers.Response(c, fiber.StatusForbidden, fmt.Sprintf(
+			configs.CantEditAfterErrorFormat,
+			"post",
+			configs.PostEditTimeSinceCreated.String(),
+		))
+	}
+
+	foundPost.Description = helpers.GetNotEmpty(description, foundPost.Description)
+	foundPost.CommentPolicy = helpers.GetNotEmpty(
+		postUpdateRequestBody.CommentPolicy,
+		foundPost.CommentPolicy,
+	)
+	foundPost.Markdown = lo.FromPtrOr(postUpdateRequestBody.Markdown, foundPost.Markdown)
+	foundPost.Entities = posthelpers.ParseEntities(foundPost.Description, db)
+
+	validate := helpers.NewValidator()
+	if err = validate.Struct(foundPost); err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, helpers.ValidatorErrors(err))
+	}
+
+	if err = db.UpdatePost(&foundPost); err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	postToSend := posthelpers.PreparePostToSend(foundPost, userID, db)
+
+	return c.JSON(responses.GetPostResponseBody{
+		Data: postToSend,
+	})
+}
+
+// swagger:route PUT /posts/{post}/sensitivity Post updatePostSensitivity
+// Set content warning and sensitive flag of own post, moderators can set them on any post
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetPostResponse
+//   default: ErrorResponse
+
+// UpdatePostSensitivity is used to set the content warning and the sensitive flag of the post by ID.
+func UpdatePostSensitivity(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	postIDParams, err := helpers.GetParamsAndValidate[parameters.PostIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	postSensitivityUpdateRequestBody, err := helpers.GetBodyAndValidate[parameters.PostSensitivityUpdateRequestBody](
+		c,
+	)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	foundPost, err := db.GetPost(postIDParams.Post)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusNotFound, configs.PostNotFoundError)
+	}
+
+	if userID != foundPost.UserID {
+		requester, userErr := db.GetUser(userID)
+		if userErr != nil {
+			return helpers.Response(c, fiber.StatusInternalServerError, userErr.Error())
+		}
+
+		if requester.Role != models.Moderator {
+			return helpers.Response(c, fiber.StatusForbidden, configs.ForbiddenError)
+		}
+	}
+
+	foundPost.ContentWarning = postSensitivityUpdateRequestBody.ContentWarning
+	foundPost.Sensitive = postSensitivityUpdateRequestBody.Sensitive
+
+	if err = db.UpdatePostSensitivity(&foundPost); err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	postToSend := posthelpers.PreparePostToSend(foundPost, userID, db)
+
+	return c.JSON(responses.GetPostResponseBody{
+		Data: postToSend,
+	})
+}
+
+// swagger:route POST /posts/{post}/like Post likePost
+// Set like to the post by ID
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   201: GetPostResponse
+//   default: ErrorResponse
+
+// LikePost is used to set the default reaction to the post by ID.
+func LikePost(c *fiber.Ctx) error {
+	return reactToPost(c, configs.DefaultReaction)
+}
+
+// swagger:route PUT /posts/{post}/reaction Post reactToPost
+// Set reaction to the post by ID, replacing the previous one
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   201: GetPostResponse
+//   default: ErrorResponse
+
+// ReactToPost is used to set the given reaction to the post by ID.
+func ReactToPost(c *fiber.Ctx) error {
+	reactionRequestBody, err := helpers.GetBodyAndValidate[parameters.ReactionRequestBody](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if err = posthelpers.ValidateReaction(reactionRequestBody.Reaction); err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	return reactToPost(c, reactionRequestBody.Reaction)
+}
+
+// reactToPost is used to set the reaction of the requester to the post by ID.
+func reactToPost(c *fiber.Ctx, reaction string) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	postIDParams, err := helpers.GetParamsAndValidate[parameters.PostIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	like := &models.DBPostLike{
+		ID:        uuid.New(),
+		PostID:    postIDParams.Post,
+		UserID:    userID,
+		Reaction:  reaction,
+		CreatedAt: time.Now(),
+	}
+
+	if err = db.LikePost(like); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == configs.DBDuplicateError {
+			return helpers.Response(c, fiber.StatusConflict, err.Error())
+		}
+
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	foundPost, err := db.GetPost(like.PostID)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusNotFound, configs.PostNotFoundError)
+	}
+
+	realtime.PublishPostLikes(foundPost, db)
+	notificationhelpers.Notify(models.DBNotification{
+		UserID: foundPost.UserID,
+		Type:   models.PostLikeNotification,
+		PostID: &foundPost.ID,
+	}, userID, db)
+
+	postToSend := posthelpers.PreparePostToSend(foundPost, userID, db)
+
+	return c.JSON(responses.GetPostResponseBody{
+		Data: postToSend,
+	})
+}
+
+// swagger:route GET /posts/{post}/likes Post getPostLikes
+// Returns a list of users who reacted to the post, followed users first
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetLikesResponse
+//   default: ErrorResponse
+
+// GetPostLikes is used to fetch users who reacted to the post by ID.
+func GetPostLikes(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	postIDParams, err := helpers.GetParamsAndValidate[parameters.PostIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	likesFetchRequestQuery, err := helpers.GetQueryAndValidate[parameters.LikesFetchRequestQuery](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	cursor, err := cursorhelpers.Decode[parameters.LikesCursor](likesFetchRequestQuery.Cursor)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, configs.InvalidCursorError)
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if _, err = db.GetPost(postIDParams.Post); err != nil {
+		return helpers.Response(c, fiber.StatusNotFound, configs.PostNotFoundError)
+	}
+
+	dbLikers, err := db.GetPostLikers(postIDParams.Post, userID, cursor, likesFetchRequestQuery.Count)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return sendLikers(c, dbLikers, likesFetchRequestQuery.Count)
+}
+
+// sendLikers sends the page of users who reacted with the cursor to the next page.
+func sendLikers(c *fiber.Ctx, dbLikers []models.DBLiker, count int) error {
+	likersToSend := lo.Map(dbLikers, func(item models.DBLiker, index int) models.Liker {
+		return item.ToLiker()
+	})
+
+	nextCursor := ""
+	if len(dbLikers) == count {
+		last := dbLikers[len(dbLikers)-1]
+
+		var err error
+		nextCursor, err = cursorhelpers.Encode(parameters.LikesCursor{
+			Followed: last.Followed,
+			LikedAt:  last.LikedAt,
+			UserID:   last.ID,
+		})
+		if err != nil {
+			return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return c.JSON(responses.GetLikesResponseBody{
+		Count:      len(likersToSend),
+		Data:       likersToSend,
+		NextCursor: nextCursor,
+	})
+}
+
+// swagger:route DELETE /posts/{post}/like Post unlikePost
+// Unset like to the post by ID
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   201: GetPostResponse
+//   default: ErrorResponse
+
+// swagger:route DELETE /posts/{post}/reaction Post unreactToPost
+// Unset reaction to the post by ID
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   201: GetPostResponse
+//   default: ErrorResponse
+
+// UnlikePost is used to remove the reaction to the post by ID.
+func UnlikePost(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	postIDParams, err := helpers.GetParamsAndValidate[parameters.PostIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	like := &models.DBPostLike{
+		PostID: postIDParams.Post,
+		UserID: userID,
+	}
+
+	if err = db.UnlikePost(like); err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	foundPost, err := db.GetPost(like.PostID)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusNotFound, configs.PostNotFoundError)
+	}
+
+	realtime.PublishPostLikes(foundPost, db)
+
+	postToSend := posthelpers.PreparePostToSend(foundPost, userID, db)
+
+	return c.JSON(responses.GetPostResponseBody{
+		Data: postToSend,
+	})
+}
+
+// swagger:route DELETE /posts/{post} Post deletePost
+// Delete post by ID
+//
+// Schemes: http, https
+//
+// Produces:
+//   - application/json
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   204: DeletePostResponse
+//   default: ErrorResponse
+
+// DeletePost is used to delete the post by ID.
+func DeletePost(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	postIDParams, err := helpers.GetParamsAndValidate[parameters.PostIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err)
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err)
+	}
+
+	foundPost, err := db.GetPost(postIDParams.Post)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusNotFound, configs.PostNotFoundError)
+	}
+
+	if userID != foundPost.UserID {
+		return helpers.Response(c, fiber.StatusForbidden, configs.ForbiddenError)
+	}
+
+	if err = db.DeletePost(foundPost.ID); err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// swagger:route GET /posts/archive Post getArchivedPosts
+// Returns a list of own archived posts
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetPostsResponse
+//   default: ErrorResponse
+
+// GetArchivedPosts is used to fetch archived posts of the requester.
+func GetArchivedPosts(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	hiddenPostsFetchRequestQuery, err := helpers.GetQueryAndValidate[parameters.HiddenPostsFetchRequestQuery](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	cursor, err := cursorhelpers.Decode[parameters.HiddenPostsCursor](hiddenPostsFetchRequestQuery.Cursor)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, configs.InvalidCursorError)
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	dbPosts, err := db.GetArchivedPosts(userID, cursor, hiddenPostsFetchRequestQuery.Count)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	postsToSend := lo.Map(dbPosts, func(item models.DBArchivedPost, index int) models.Post {
+		post := posthelpers.PreparePostToSend(item.DBPost, userID, db)
+		post.ArchivedAt = helpers.Ptr(item.ArchivedAt)
+		return post
+	})
+
+	nextCursor := ""
+	if len(dbPosts) == hiddenPostsFetchRequestQuery.Count {
+		last := dbPosts[len(dbPosts)-1]
+		nextCursor, err = cursorhelpers.Encode(parameters.HiddenPostsCursor{
+			HiddenAt: last.ArchivedAt,
+			ID:       last.ID,
+		})
+		if err != nil {
+			return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return c.JSON(responses.GetPostsResponseBody{
+		Count:      len(postsToSend),
+		Data:       postsToSend,
+		NextCursor: nextCursor,
+	})
+}
+
+// swagger:route GET /posts/trash Post getDeletedPosts
+// Returns a list of own posts deleted during the retention period
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetPostsResponse
+//   default: ErrorResponse
+
+// GetDeletedPosts is used to fetch posts of the requester which can be restored from the trash.
+func GetDeletedPosts(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	hiddenPostsFetchRequestQuery, err := helpers.GetQueryAndValidate[parameters.HiddenPostsFetchRequestQuery](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	cursor, err := cursorhelpers.Decode[parameters.HiddenPostsCursor](hiddenPostsFetchRequestQuery.Cursor)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, configs.InvalidCursorError)
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	dbPosts, err := db.GetDeletedPosts(
+		userID,
+		time.Now().Add(-configs.PostTrashRetention),
+		cursor,
+		hiddenPostsFetchRequestQuery.Count,
+	)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	postsToSend := lo.Map(dbPosts, func(item models.DBDeletedPost, index int) models.Post {
+		post := posthelpers.PreparePostToSend(item.DBPost, userID, db)
+		post.DeletedAt = helpers.Ptr(item.DeletedAt)
+		return post
+	})
+
+	nextCursor := ""
+	if len(dbPosts) == hiddenPostsFetchRequestQuery.Count {
+		last := dbPosts[len(dbPosts)-1]
+		nextCursor, err = cursorhelpers.Encode(parameters.HiddenPostsCursor{
+			HiddenAt: last.DeletedAt,
+			ID:       last.ID,
+		})
+		if err != nil {
+			return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return c.JSON(responses.GetPostsResponseBody{
+		Count:      len(postsToSend),
+		Data:       postsToSend,
+		NextCursor: nextCursor,
+	})
+}
+
+// swagger:route POST /posts/{post}/archive Post archivePost
+// Hide own post from everyone without deleting it
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   204: DeletePostResponse
+//   default: ErrorResponse
+
+// ArchivePost is used to archive the post by ID.
+func ArchivePost(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	postIDParams, err := helpers.GetParamsAndValidate[parameters.PostIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	foundPost, err := db.GetPost(postIDParams.Post)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusNotFound, configs.PostNotFoundError)
+	}
+
+	if userID != foundPost.UserID {
+		return helpers.Response(c, fiber.StatusForbidden, configs.ForbiddenError)
+	}
+
+	archived, err := db.ArchivePost(userID, foundPost.ID)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if !archived {
+		return helpers.Response(c, fiber.StatusNotFound, configs.PostNotFoundError)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// swagger:route DELETE /posts/{post}/archive Post unarchivePost
+// Show own archived post to everyone again
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetPostResponse
+//   default: ErrorResponse
+
+// UnarchivePost is used to restore the archived post by ID.
+func UnarchivePost(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	postIDParams, err := helpers.GetParamsAndValidate[parameters.PostIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	unarchived, err := db.UnarchivePost(userID, postIDParams.Post)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if !unarchived {
+		return helpers.Response(c, fiber.StatusNotFound, configs.PostNotArchivedError)
+	}
+
+	foundPost, err := db.GetPost(postIDParams.Post)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusNotFound, configs.PostNotFoundError)
+	}
+
+	postToSend := posthelpers.PreparePostToSend(foundPost, userID, db)
+
+	return c.JSON(responses.GetPostResponseBody{
+		Data: postToSend,
+	})
+}
+
+// swagger:route POST /posts/{post}/restore Post restorePost
+// Restore own post from the trash
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   204: DeletePostResponse
+//   default: ErrorResponse
+
+// RestorePost is used to restore the deleted post by ID. Archived posts stay archived.
+func RestorePost(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	postIDParams, err := helpers.GetParamsAndValidate[parameters.PostIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	restored, err := db.RestorePost(
+		userID,
+		postIDParams.Post,
+		time.Now().Add(-configs.PostTrashRetention),
+	)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if !restored {
+		return helpers.Response(c, fiber.StatusNotFound, configs.PostNotInTrashError)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
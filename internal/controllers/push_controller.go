@@ -0,0 +1,189 @@
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/helpers"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/MangriMen/Diverse-Back/internal/parameters"
+	"github.com/MangriMen/Diverse-Back/internal/responses"
+	"github.com/MangriMen/Diverse-Back/internal/webpush"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// swagger:route GET /push/vapid-key Push getVAPIDKey
+// Returns the public VAPID key the browser subscribes to push notifications with
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetVAPIDKeyResponse
+//   default: ErrorResponse
+
+// GetVAPIDKey is used to fetch the public VAPID key of the server.
+func GetVAPIDKey(c *fiber.Ctx) error {
+	keys, err := webpush.LoadKeys()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusServiceUnavailable, configs.PushNotConfiguredError)
+	}
+
+	return c.JSON(responses.GetVAPIDKeyResponseBody{
+		PublicKey: keys.PublicKey(),
+	})
+}
+
+// swagger:route GET /push/subscriptions Push getPushSubscriptions
+// Returns push subscriptions of the user
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetPushSubscriptionsResponse
+//   default: ErrorResponse
+
+// GetPushSubscriptions is used to fetch push subscriptions of the requester.
+func GetPushSubscriptions(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	dbSubscriptions, err := db.GetPushSubscriptions(userID)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	subscriptionsToSend := lo.Map(
+		dbSubscriptions,
+		func(item models.DBPushSubscription, index int) models.PushSubscription {
+			return item.PushSubscription
+		},
+	)
+
+	return c.JSON(responses.GetPushSubscriptionsResponseBody{
+		Count: len(subscriptionsToSend),
+		Data:  subscriptionsToSend,
+	})
+}
+
+// swagger:route POST /push/subscriptions Push createPushSubscription
+// Register the push subscription of the browser, keys of the registered endpoint are updated
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   201: GetPushSubscriptionResponse
+//   default: ErrorResponse
+
+// CreatePushSubscription is used to register the push subscription of the requester.
+func CreatePushSubscription(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	pushSubscriptionCreateRequestBody, err := helpers.GetBodyAndValidate[parameters.PushSubscriptionCreateRequestBody](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if !webpush.Enabled() {
+		return helpers.Response(c, fiber.StatusServiceUnavailable, configs.PushNotConfiguredError)
+	}
+
+	if !webpush.AllowedEndpoint(pushSubscriptionCreateRequestBody.Endpoint) {
+		return helpers.Response(c, fiber.StatusBadRequest, configs.PushServiceNotAllowedError)
+	}
+
+	if !webpush.ValidateSubscription(webpush.Subscription{
+		Endpoint: pushSubscriptionCreateRequestBody.Endpoint,
+		P256dh:   pushSubscriptionCreateRequestBody.Keys.P256dh,
+		Auth:     pushSubscriptionCreateRequestBody.Keys.Auth,
+	}) {
+		return helpers.Response(c, fiber.StatusBadRequest, configs.InvalidPushSubscriptionError)
+	}
+
+	newSubscription := &models.DBPushSubscription{
+		PushSubscription: models.PushSubscription{
+			ID:        uuid.New(),
+			Endpoint:  pushSubscriptionCreateRequestBody.Endpoint,
+			CreatedAt: time.Now(),
+		},
+		UserID: userID,
+		P256dh: pushSubscriptionCreateRequestBody.Keys.P256dh,
+		Auth:   pushSubscriptionCreateRequestBody.Keys.Auth,
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	created, err := db.CreatePushSubscription(newSubscription)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if !created {
+		return helpers.Response(c, fiber.StatusConflict, fmt.Sprintf(
+			configs.PushSubscriptionsLimitErrorFormat,
+			configs.PushSubscriptionsMaxCount,
+		))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(responses.GetPushSubscriptionResponseBody{
+		Data: newSubscription.PushSubscription,
+	})
+}
+
+// swagger:route DELETE /push/subscriptions/{subscription} Push deletePushSubscription
+// Unregister the push subscription
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   204: DeletePushSubscriptionResponse
+//   default: ErrorResponse
+
+// DeletePushSubscription is used to unregister the push subscription of the requester.
+func DeletePushSubscription(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	pushSubscriptionIDParams, err := helpers.GetParamsAndValidate[parameters.PushSubscriptionIDParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	deleted, err := db.DeletePushSubscription(userID, pushSubscriptionIDParams.Subscription)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if !deleted {
+		return helpers.Response(c, fiber.StatusNotFound, configs.PushSubscriptionNotFoundError)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
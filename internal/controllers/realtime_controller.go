@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/helpers"
+	"github.com/MangriMen/Diverse-Back/internal/parameters"
+	"github.com/MangriMen/Diverse-Back/internal/realtime"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// realtimeUserIDKey is the key of the requester id passed from the upgrade request to the connection.
+const realtimeUserIDKey = "realtimeUserID"
+
+// lastEventIDHeader is the header with the id of the last received event, sent by EventSource on reconnect.
+const lastEventIDHeader = "Last-Event-ID"
+
+// swagger:route GET /ws Realtime connectRealtime
+// Upgrade to the WebSocket connection which pushes events of subscribed topics.
+//
+// Clients send {"type": "subscribe", "topic": "...", "last_event_id": 0} to subscribe,
+// {"type": "unsubscribe", "topic": "..."} to unsubscribe and {"type": "ping"} to check the connection.
+// Topics are "users/{user}/notifications", "users/{user}/followers", "users/{user}/feed",
+// "users/{user}/messages", "posts/{post}/comments" and "posts/{post}/likes".
+// Events are sent as {"type": "event", "topic": "...", "event": {...}}, the id of the last received
+// event resumes the subscription after reconnecting. Ephemeral events like typing indicators have id 0,
+// they're not kept and not sent again. The "reset" message means missed events
+// are not available and the topic data should be refetched.
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   101: RealtimeResponse
+//   default: ErrorResponse
+
+// UpgradeRealtime is used to accept WebSocket upgrade requests of authenticated users.
+func UpgradeRealtime(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return helpers.Response(c, fiber.StatusUpgradeRequired, configs.WebSocketUpgradeRequiredError)
+	}
+
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Locals(realtimeUserIDKey, userID)
+
+	return c.Next()
+}
+
+// ServeRealtime returns the handler of WebSocket connections accepted by UpgradeRealtime.
+func ServeRealtime() fiber.Handler {
+	return websocket.New(func(conn *websocket.Conn) {
+		userID, ok := conn.Locals(realtimeUserIDKey).(uuid.UUID)
+		if !ok {
+			_ = conn.Close()
+			return
+		}
+
+		db, err := database.OpenDBConnection()
+		if err != nil {
+			_ = conn.Close()
+			return
+		}
+
+		realtime.Serve(conn, userID, db)
+	})
+}
+
+// swagger:route GET /events Realtime streamRealtime
+// Open the Server-Sent Events stream of subscribed topics, for clients which can't use WebSockets.
+//
+// Topics and events are the same as of the WebSocket gateway. Events are sent with their id,
+// type as the event name and data, the stream resumes after the Last-Event-ID sent on reconnect.
+// Ephemeral events are sent without the id.
+// The "reset" event means missed events are not available and the topic data should be refetched.
+//
+// Produces:
+// - text/event-stream
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: RealtimeStreamResponse
+//   default: ErrorResponse
+
+// StreamRealtime is used to stream events of subscribed topics to the user.
+func StreamRealtime(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	query, err := helpers.GetQueryAndValidate[parameters.RealtimeStreamRequestQuery](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	topics := lo.Uniq(query.Topics)
+	if len(topics) == 0 {
+		topics = realtime.UserTopics(userID)
+	}
+
+	if len(topics) > configs.RealtimeMaxTopics {
+		return helpers.Response(
+			c,
+			fiber.StatusBadRequest,
+			fmt.Sprintf(configs.RealtimeTopicsLimitErrorFormat, configs.RealtimeMaxTopics),
+		)
+	}
+
+	lastEventID := query.LastEventID
+	if header := c.Get(lastEventIDHeader); header != "" {
+		lastEventID, err = strconv.ParseInt(header, 10, 64)
+		if err != nil || lastEventID < 0 {
+			return helpers.Response(c, fiber.StatusBadRequest, configs.InvalidLastEventIDError)
+		}
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	for _, topic := range topics {
+		if !realtime.CanSubscribe(userID, topic, db) {
+			return helpers.Response(c, fiber.StatusForbidden, configs.RealtimeTopicForbiddenError)
+		}
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	// Disables buffering of the response by the nginx reverse proxy
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		realtime.ServeStream(w, userID, topics, lastEventID, db)
+	})
+
+	return nil
+}
@@ -0,0 +1,259 @@
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/helpers"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/cursorhelpers"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/posthelpers"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/texthelpers"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/MangriMen/Diverse-Back/internal/parameters"
+	"github.com/MangriMen/Diverse-Back/internal/responses"
+	"github.com/gofiber/fiber/v2"
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+)
+
+// swagger:route GET /search/posts Search searchPosts
+// Returns a list of posts matching the search query ordered by relevance
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: SearchPostsResponse
+//   default: ErrorResponse
+
+// SearchPosts is used to full-text search posts by description.
+func SearchPosts(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	searchRequestQuery, err := helpers.GetQueryAndValidate[parameters.SearchRequestQuery](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	cursor, err := validateSearchQuery(searchRequestQuery)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	dbResults, err := db.SearchPosts(userID, searchRequestQuery, cursor)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	resultsToSend := lo.Map(
+		dbResults,
+		func(item models.DBPostSearchResult, index int) models.PostSearchResult {
+			return models.PostSearchResult{
+				Post:     posthelpers.PreparePostToSend(item.DBPost, userID, db),
+				Rank:     item.Rank,
+				Headline: texthelpers.RenderHeadline(item.Headline),
+			}
+		},
+	)
+
+	nextCursor := ""
+	if len(dbResults) == searchRequestQuery.Count {
+		last := dbResults[len(dbResults)-1]
+		nextCursor, err = cursorhelpers.Encode(parameters.SearchCursor{
+			Rank:      last.Rank,
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+		if err != nil {
+			return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return c.JSON(responses.SearchPostsResponseBody{
+		Count:      len(resultsToSend),
+		Data:       resultsToSend,
+		NextCursor: nextCursor,
+	})
+}
+
+// swagger:route GET /search/comments Search searchComments
+// Returns a list of comments matching the search query ordered by relevance
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: SearchCommentsResponse
+//   default: ErrorResponse
+
+// SearchComments is used to full-text search comments by content.
+func SearchComments(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	searchRequestQuery, err := helpers.GetQueryAndValidate[parameters.SearchRequestQuery](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	cursor, err := validateSearchQuery(searchRequestQuery)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	dbResults, err := db.SearchComments(userID, searchRequestQuery, cursor)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	resultsToSend := lo.Map(
+		dbResults,
+		func(item models.DBCommentSearchResult, index int) models.CommentSearchResult {
+			return models.CommentSearchResult{
+				Comment:  posthelpers.PrepareCommentToPost(item.DBComment, userID, db),
+				PostID:   item.PostID,
+				Rank:     item.Rank,
+				Headline: texthelpers.RenderHeadline(item.Headline),
+			}
+		},
+	)
+
+	nextCursor := ""
+	if len(dbResults) == searchRequestQuery.Count {
+		last := dbResults[len(dbResults)-1]
+		nextCursor, err = cursorhelpers.Encode(parameters.SearchCursor{
+			Rank:      last.Rank,
+			CreatedAt: last.CreatedAt,
+			ID:        last.ID,
+		})
+		if err != nil {
+			return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return c.JSON(responses.SearchCommentsResponseBody{
+		Count:      len(resultsToSend),
+		Data:       resultsToSend,
+		NextCursor: nextCursor,
+	})
+}
+
+// swagger:route GET /search/suggestions Search getSearchSuggestions
+// Returns users, posts and hashtags starting with the given text
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   200: GetSearchSuggestionsResponse
+//   default: ErrorResponse
+
+// GetSearchSuggestions is used to prefix search users, posts and hashtags in the search index.
+func GetSearchSuggestions(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	searchSuggestionsRequestQuery, err := helpers.GetQueryAndValidate[parameters.SearchSuggestionsRequestQuery](
+		c,
+	)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	blockedUserIDs, err := db.GetBlockedUserIDs(userID)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	userIDs, err := db.Index.SearchUsers(
+		searchSuggestionsRequestQuery.Query,
+		searchSuggestionsRequestQuery.Count,
+	)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, configs.SearchIndexError)
+	}
+
+	postIDs, err := db.Index.SearchPosts(
+		searchSuggestionsRequestQuery.Query,
+		searchSuggestionsRequestQuery.Count,
+	)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, configs.SearchIndexError)
+	}
+
+	hashtags, err := db.Index.SearchHashtags(
+		searchSuggestionsRequestQuery.Query,
+		searchSuggestionsRequestQuery.Count,
+	)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, configs.SearchIndexError)
+	}
+
+	dbUsers, err := db.GetUsersByIDs(lo.Without(userIDs, blockedUserIDs...))
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	dbPosts, err := db.GetPostsByIDs(postIDs)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	usersToSend := lo.Map(dbUsers, func(item models.DBUser, index int) models.User {
+		return item.ToUser()
+	})
+
+	postsToSend := lo.FilterMap(dbPosts, func(item models.DBPost, index int) (models.Post, bool) {
+		if lo.Contains(blockedUserIDs, item.UserID) {
+			return models.Post{}, false
+		}
+
+		return posthelpers.PreparePostToSend(item, userID, db), true
+	})
+
+	return c.JSON(responses.GetSearchSuggestionsResponseBody{
+		Users:    usersToSend,
+		Posts:    postsToSend,
+		Hashtags: hashtags,
+	})
+}
+
+// validateSearchQuery checks the search language and decodes the search cursor.
+func validateSearchQuery(
+	searchRequestQuery *parameters.SearchRequestQuery,
+) (*parameters.SearchCursor, error) {
+	if searchRequestQuery.Language != "" &&
+		!slices.Contains(configs.GetSearchLanguages(), searchRequestQuery.Language) {
+		return nil, fmt.Errorf(configs.SearchInvalidLanguage)
+	}
+
+	cursor, err := cursorhelpers.Decode[parameters.SearchCursor](searchRequestQuery.Cursor)
+	if err != nil {
+		return nil, fmt.Errorf(configs.InvalidCursorError)
+	}
+
+	return cursor, nil
+}
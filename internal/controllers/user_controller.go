@@ -152,7 +152,7 @@ func LoginUser(c *fiber.Ctx) error {
 
 	return c.JSON(responses.RegisterLoginUserResponseBody{
 		Token: token,
-		User:  foundDBUser.ToUser(),
+		User:  foundDBUser.ToOwnUser(),
 	})
 }
 
@@ -191,7 +191,9 @@ func CreateUser(c *fiber.Ctx) error {
 			Email:     registerRequestBody.Email,
 			Username:  registerRequestBody.Username,
 			CreatedAt: time.Now(),
+			Role:      models.RegularUser,
 		},
+		SensitiveContent: models.BlurSensitive,
 	}
 	user.UpdatedAt = user.CreatedAt
 
@@ -217,7 +219,7 @@ func CreateUser(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusCreated).JSON(
 		responses.RegisterLoginUserResponseBody{
 			Token: token,
-			User:  user.ToUser(),
+			User:  user.ToOwnUser(),
 		})
 }
 
@@ -259,7 +261,7 @@ func FetchUser(c *fiber.Ctx) error {
 
 	return c.JSON(responses.RegisterLoginUserResponseBody{
 		Token: token,
-		User:  dbUser.ToUser(),
+		User:  dbUser.ToOwnUser(),
 	})
 }
 
@@ -314,6 +316,10 @@ func UpdateUser(c *fiber.Ctx) error {
 	foundUser.About = helpers.GetNotEmpty(userUpdateRequestBody.About, foundUser.About)
 
 	foundUser.AvatarURL = helpers.GetNotEmpty(userUpdateRequestBody.AvatarURL, foundUser.AvatarURL)
+	foundUser.SensitiveContent = helpers.GetNotEmpty(
+		userUpdateRequestBody.SensitiveContent,
+		foundUser.SensitiveContent,
+	)
 
 	if userUpdateRequestBody.Password != "" {
 		foundUser.Password, err = userhelpers.HashPassword(userUpdateRequestBody.Password)
@@ -2,14 +2,17 @@ package controllers
 
 import (
 	"errors"
+	"log"
 	"time"
 
 	"github.com/MangriMen/Diverse-Back/api/database"
 	"github.com/MangriMen/Diverse-Back/configs"
 	"github.com/MangriMen/Diverse-Back/internal/helpers"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/notificationhelpers"
 	"github.com/MangriMen/Diverse-Back/internal/helpers/userhelpers"
 	"github.com/MangriMen/Diverse-Back/internal/models"
 	"github.com/MangriMen/Diverse-Back/internal/parameters"
+	"github.com/MangriMen/Diverse-Back/internal/realtime"
 	"github.com/MangriMen/Diverse-Back/internal/responses"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -219,6 +222,18 @@ func AddRelation(c *fiber.Ctx) error {
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
 	}
 
+	if err = userhelpers.UpdateTimelinesOnRelationAdded(relation, db); err != nil {
+		log.Printf("Timelines are not updated. Reason: %v", err)
+	}
+
+	if relation.Type == models.Following {
+		notificationhelpers.Notify(models.DBNotification{
+			UserID: relation.RelationUserID,
+			Type:   models.FollowNotification,
+		}, relation.UserID, db)
+		realtime.PublishFollower(*relation, db)
+	}
+
 	return c.SendStatus(fiber.StatusCreated)
 }
 
@@ -266,5 +281,92 @@ func DeleteRelation(c *fiber.Ctx) error {
 		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
 	}
 
+	if relationAddDeleteRequestQuery.Type == models.Following {
+		err = db.RemoveFromTimeline(relationGetStatusParams.User, relationGetStatusParams.RelationUser)
+		if err != nil {
+			return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// swagger:route POST /users/{user}/restrictions/{relationUser} User restrictUser
+// Restrict the user from commenting own posts, existing comments of the user become hidden
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   204: CommentRestrictionResponse
+//   default: ErrorResponse
+
+// RestrictUser is used to restrict the user from commenting posts of the requester.
+func RestrictUser(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	params, err := helpers.GetParamsAndValidate[parameters.RelationGetStatusParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if userID != params.User || userID == params.RelationUser {
+		return helpers.Response(c, fiber.StatusForbidden, configs.ForbiddenError)
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if _, err = db.GetUser(params.RelationUser); err != nil {
+		return helpers.Response(c, fiber.StatusNotFound, configs.UserNotFoundError)
+	}
+
+	if err = db.RestrictUser(params.User, params.RelationUser); err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// swagger:route DELETE /users/{user}/restrictions/{relationUser} User unrestrictUser
+// Allow the restricted user to comment own posts again
+//
+// Security:
+//   bearerAuth:
+//
+// Responses:
+//   204: CommentRestrictionResponse
+//   default: ErrorResponse
+
+// UnrestrictUser is used to lift the comment restriction of the user.
+func UnrestrictUser(c *fiber.Ctx) error {
+	userID, err := helpers.GetUserIDFromToken(c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	params, err := helpers.GetParamsAndValidate[parameters.RelationGetStatusParams](c)
+	if err != nil {
+		return helpers.Response(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	if userID != params.User {
+		return helpers.Response(c, fiber.StatusForbidden, configs.ForbiddenError)
+	}
+
+	db, err := database.OpenDBConnection()
+	if err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	if err = db.UnrestrictUser(params.User, params.RelationUser); err != nil {
+		return helpers.Response(c, fiber.StatusInternalServerError, err.Error())
+	}
+
 	return c.SendStatus(fiber.StatusNoContent)
 }
@@ -0,0 +1,37 @@
+// Package cursorhelpers provides functions to work with opaque pagination cursors.
+package cursorhelpers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// Encode serializes the cursor to the opaque url-safe string.
+func Encode[T any](cursor T) (string, error) {
+	rawCursor, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(rawCursor), nil
+}
+
+// Decode deserializes the cursor from the opaque string returned by Encode.
+// Returns nil if the given string is empty, which means the first page.
+func Decode[T any](encodedCursor string) (*T, error) {
+	if encodedCursor == "" {
+		return nil, nil //nolint:nilnil // empty cursor means the first page
+	}
+
+	rawCursor, err := base64.RawURLEncoding.DecodeString(encodedCursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor T
+	if err = json.Unmarshal(rawCursor, &cursor); err != nil {
+		return nil, err
+	}
+
+	return &cursor, nil
+}
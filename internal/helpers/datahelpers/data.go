@@ -90,3 +90,33 @@ func processImage(buffer []byte, quality int, filepath string) error {
 
 	return bimg.Write(filepath, processed)
 }
+
+// ResizeImage shrinks the image to the given width or height keeping its aspect ratio,
+// the image is not enlarged if it's smaller. Returns the image with its content type.
+func ResizeImage(file []byte, width *int, height *int) ([]byte, string, error) {
+	imageMetadata, err := bimg.Metadata(file)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var options bimg.Options
+
+	switch {
+	case width != nil && *width < imageMetadata.Size.Width:
+		options = bimg.Options{Width: *width}
+	case height != nil && *height < imageMetadata.Size.Height:
+		options = bimg.Options{Height: *height}
+	}
+
+	image, err := bimg.NewImage(file).Process(options)
+	if err != nil {
+		return nil, "", err
+	}
+
+	contentType := strings.Join(
+		[]string{configs.MIMEBaseImage, bimg.ImageTypeName(bimg.DetermineImageType(image))},
+		"/",
+	)
+
+	return image, contentType, nil
+}
@@ -0,0 +1,90 @@
+// Package messagehelpers provides functionality to work with conversations and messages.
+package messagehelpers
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// PrepareConversationToSend prepares a conversation object for sending by fetching additional data
+// from the database such as other members and the last message of the conversation.
+func PrepareConversationToSend(
+	conversation models.DBMemberConversation,
+	userID uuid.UUID,
+	db *database.Queries,
+) models.Conversation {
+	preparedConversation := conversation.ToConversation()
+	preparedConversation.Members = []models.User{}
+
+	members, err := db.GetConversationMembers(conversation.ID)
+	if err == nil {
+		preparedConversation.Members = lo.FilterMap(
+			members,
+			func(item models.DBConversationMember, index int) (models.User, bool) {
+				if item.UserID == userID {
+					return models.User{}, false
+				}
+
+				user, userErr := db.GetUser(item.UserID)
+				if userErr != nil {
+					return models.User{}, false
+				}
+
+				return user.ToUser(), true
+			},
+		)
+	}
+
+	lastMessage, err := db.GetLastMessage(conversation.ID, conversation.JoinedAt)
+	if err == nil {
+		preparedLastMessage := PrepareMessageToSend(lastMessage, db)
+		preparedConversation.LastMessage = &preparedLastMessage
+	}
+
+	return preparedConversation
+}
+
+// PrepareConversationMembersToSend prepares members of the conversation for sending
+// by fetching their users from the database.
+func PrepareConversationMembersToSend(
+	members []models.DBConversationMember,
+	db *database.Queries,
+) []models.ConversationMember {
+	return lo.FilterMap(
+		members,
+		func(item models.DBConversationMember, index int) (models.ConversationMember, bool) {
+			user, err := db.GetUser(item.UserID)
+			if err != nil {
+				return models.ConversationMember{}, false
+			}
+
+			member := item.ToConversationMember()
+			member.User = user.ToUser()
+
+			return member, true
+		},
+	)
+}
+
+// GetConversationOfMember returns the conversation as seen by the user, false if the user is not its member.
+func GetConversationOfMember(
+	conversationID uuid.UUID,
+	userID uuid.UUID,
+	db *database.Queries,
+) (models.DBMemberConversation, bool, error) {
+	conversation, err := db.GetMemberConversation(conversationID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return conversation, false, nil
+	}
+
+	if err != nil {
+		return conversation, false, err
+	}
+
+	return conversation, true, nil
+}
@@ -0,0 +1,90 @@
+package messagehelpers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// NewSystemMessage returns the system message recording the change of the conversation made by the actor.
+// The content is the new name for renaming messages, the target is the member added, removed or left.
+func NewSystemMessage(
+	conversationID uuid.UUID,
+	actorID uuid.UUID,
+	event models.MessageEvent,
+	targetUserID *uuid.UUID,
+	content string,
+	now time.Time,
+) models.DBMessage {
+	return models.DBMessage{
+		BaseMessage: models.BaseMessage{
+			ID:             uuid.New(),
+			ConversationID: conversationID,
+			SenderID:       &actorID,
+			Type:           models.SystemMessage,
+			Content:        content,
+			Event:          &event,
+			TargetUserID:   targetUserID,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		},
+	}
+}
+
+// PrepareMessageToSend prepares a message object for sending by fetching its attachments,
+// system messages get the summary with usernames of the actor and the target.
+func PrepareMessageToSend(message models.DBMessage, db *database.Queries) models.Message {
+	preparedMessage := message.ToMessage()
+
+	if message.Type != models.SystemMessage || message.Event == nil {
+		attachments, err := db.GetMessageAttachments(message.ID)
+		if err == nil {
+			preparedMessage.Attachments = lo.Map(
+				attachments,
+				func(item models.DBMessageAttachment, index int) models.MessageAttachment {
+					return item.ToMessageAttachment()
+				},
+			)
+		}
+
+		return preparedMessage
+	}
+
+	actor := username(message.SenderID, db)
+	target := username(message.TargetUserID, db)
+
+	switch *message.Event {
+	case models.GroupCreatedMessageEvent:
+		preparedMessage.Text = fmt.Sprintf("%s created the group %q", actor, message.Content)
+	case models.GroupRenamedMessageEvent:
+		preparedMessage.Text = fmt.Sprintf("%s renamed the group to %q", actor, message.Content)
+	case models.MemberAddedMessageEvent:
+		preparedMessage.Text = fmt.Sprintf("%s added %s", actor, target)
+	case models.MemberRemovedMessageEvent:
+		preparedMessage.Text = fmt.Sprintf("%s removed %s", actor, target)
+	case models.MemberLeftMessageEvent:
+		preparedMessage.Text = fmt.Sprintf("%s left", target)
+	default:
+		preparedMessage.Text = fmt.Sprintf("%s changed the group", actor)
+	}
+
+	return preparedMessage
+}
+
+// username returns the username of the user, "someone" if the user is deleted.
+func username(userID *uuid.UUID, db *database.Queries) string {
+	if userID == nil {
+		return "someone"
+	}
+
+	user, err := db.GetUser(*userID)
+	if err != nil {
+		return "someone"
+	}
+
+	return user.Username
+}
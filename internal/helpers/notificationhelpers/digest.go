@@ -0,0 +1,224 @@
+package notificationhelpers
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"log"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/posthelpers"
+	"github.com/MangriMen/Diverse-Back/internal/mailer"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/samber/lo"
+)
+
+//go:embed templates/digest.html.tmpl templates/digest.txt.tmpl
+var digestTemplates embed.FS //nolint:gochecknoglobals // embedded files can only be package variables
+
+// digestPost is the liked post of the user listed in the digest.
+type digestPost struct {
+	Description    string
+	ReactionsCount int
+}
+
+// digest is the summary of unread notifications of the user rendered into the email.
+type digest struct {
+	Username string
+
+	// Period is the time summarized by the digest, like "today"
+	Period string
+
+	FollowersCount int
+	Followers      []string
+
+	TopPosts []digestPost
+
+	CommentsCount         int
+	RepliesCount          int
+	MentionsCount         int
+	CommentReactionsCount int
+}
+
+// empty returns whether there is nothing to report in the digest.
+func (d *digest) empty() bool {
+	return d.FollowersCount == 0 && len(d.TopPosts) == 0 &&
+		d.CommentsCount == 0 && d.RepliesCount == 0 && d.MentionsCount == 0 && d.CommentReactionsCount == 0
+}
+
+// SendNotificationDigests emails due digests of unread notifications to users who turned them on.
+// Digests are claimed as sent before sending, so each one is sent by one instance
+// and skipped rather than sent twice if sending fails.
+// Users without unread notifications since the last digest don't receive the email.
+func SendNotificationDigests(sender mailer.Mailer, db *database.Queries) error {
+	now := time.Now()
+
+	dueDigests, err := db.ClaimDueDigests(now, configs.DigestHour, configs.DigestTolerance, configs.DigestBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, settings := range dueDigests {
+		if err = sendDigest(sender, settings, now, db); err != nil {
+			log.Printf("Notification digest is not sent. Reason: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// sendDigest summarizes notifications of the user since the previous digest and emails the summary.
+func sendDigest(
+	sender mailer.Mailer,
+	settings models.DBNotificationSettings,
+	now time.Time,
+	db *database.Queries,
+) error {
+	since := now.Add(-settings.Digest.Period())
+	if settings.LastDigestAt != nil {
+		since = *settings.LastDigestAt
+	}
+
+	user, err := db.GetUser(settings.UserID)
+	if err != nil {
+		return err
+	}
+
+	notifications, err := db.GetDigestNotifications(settings.UserID, since, configs.DigestNotificationsCount)
+	if err != nil {
+		return err
+	}
+
+	summary, err := summarizeDigest(user, settings.Digest, notifications, db)
+	if err != nil {
+		return err
+	}
+
+	if summary.empty() {
+		return nil
+	}
+
+	message, err := renderDigest(summary)
+	if err != nil {
+		return err
+	}
+
+	message.To = user.Email
+
+	return sender.Send(message)
+}
+
+// summarizeDigest counts notifications of each type and lists new followers and the most liked posts.
+// Mentions hidden by muted words of the user are not counted.
+func summarizeDigest(
+	user models.DBUser,
+	frequency models.DigestFrequency,
+	notifications []models.DBNotification,
+	db *database.Queries,
+) (digest, error) {
+	summary := digest{Username: user.Username, Period: "today"}
+	if frequency == models.WeeklyDigest {
+		summary.Period = "this week"
+	}
+
+	mutedWordsFilter, err := posthelpers.NewMutedWordsFilter(user.ID, models.NotificationsScope, db)
+	if err != nil {
+		return summary, err
+	}
+
+	for _, notification := range notifications {
+		switch notification.Type {
+		case models.FollowNotification:
+			summary.FollowersCount += notification.ActorsCount
+
+			actors, actorsErr := db.GetNotificationActors(notification.ID, configs.DigestListLimit)
+			if actorsErr != nil {
+				return summary, actorsErr
+			}
+
+			summary.Followers = append(summary.Followers, lo.Map(actors, func(item models.DBUser, index int) string {
+				return item.Username
+			})...)
+		case models.PostLikeNotification:
+			// Notifications go from the most liked, so the first ones are the top posts
+			if len(summary.TopPosts) >= configs.DigestListLimit || notification.PostID == nil {
+				continue
+			}
+
+			post, postErr := db.GetPost(*notification.PostID)
+			if postErr != nil {
+				continue
+			}
+
+			description := excerpt(post.Description, configs.DigestExcerptLength)
+			if description == "" {
+				description = "Post without description"
+			}
+
+			summary.TopPosts = append(summary.TopPosts, digestPost{
+				Description:    description,
+				ReactionsCount: notification.ActorsCount,
+			})
+		case models.CommentNotification:
+			summary.CommentsCount += notification.ActorsCount
+		case models.ReplyNotification:
+			summary.RepliesCount += notification.ActorsCount
+		case models.CommentLikeNotification:
+			summary.CommentReactionsCount += notification.ActorsCount
+		case models.MentionNotification:
+			if _, muted := mutedWordsFilter.Match(GetNotificationTexts(notification, db)...); !muted {
+				summary.MentionsCount++
+			}
+		}
+	}
+
+	summary.Followers = lo.Slice(summary.Followers, 0, configs.DigestListLimit)
+
+	return summary, nil
+}
+
+// renderDigest renders the digest into the email with plain text and HTML bodies.
+func renderDigest(summary digest) (mailer.Message, error) {
+	message := mailer.Message{Subject: "Your Diverse digest: what you missed " + summary.Period}
+
+	textTemplate, err := texttemplate.ParseFS(digestTemplates, "templates/digest.txt.tmpl")
+	if err != nil {
+		return message, err
+	}
+
+	htmlTemplate, err := htmltemplate.ParseFS(digestTemplates, "templates/digest.html.tmpl")
+	if err != nil {
+		return message, err
+	}
+
+	var text, html bytes.Buffer
+
+	if err = textTemplate.Execute(&text, summary); err != nil {
+		return message, err
+	}
+
+	if err = htmlTemplate.Execute(&html, summary); err != nil {
+		return message, err
+	}
+
+	message.Text = text.String()
+	message.HTML = html.String()
+
+	return message, nil
+}
+
+// excerpt returns the first line of the text shortened to the given number of characters.
+func excerpt(text string, length int) string {
+	text, _, _ = strings.Cut(strings.TrimSpace(text), "\n")
+
+	runes := []rune(text)
+	if len(runes) <= length {
+		return text
+	}
+
+	return strings.TrimSpace(string(runes[:length])) + "…"
+}
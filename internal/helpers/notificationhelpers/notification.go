@@ -0,0 +1,201 @@
+// Package notificationhelpers provides functionality to create notifications
+// and convert them from DB to response variant.
+package notificationhelpers
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/texthelpers"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/MangriMen/Diverse-Back/internal/realtime"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
+)
+
+// Notify notifies the user of the notification about the event caused by the actor through channels
+// the user hasn't opted out of: it's pushed to connected clients and queued for push subscriptions,
+// the email digest includes it later. Events caused by the user itself or by users blocked by or blocking
+// the user are skipped. The error is only logged, because the event is already saved and the notification
+// is not essential.
+func Notify(notification models.DBNotification, actorID uuid.UUID, db *database.Queries) {
+	if notification.UserID == actorID {
+		return
+	}
+
+	optOuts, err := db.GetNotificationOptOuts(notification.UserID)
+	if err != nil {
+		log.Printf("Notification is not created. Reason: %v", err)
+		return
+	}
+
+	channels := enabledChannels(notification.Type, optOuts)
+	if len(channels) == 0 {
+		return
+	}
+
+	notification.ID = uuid.New()
+	notification.GroupKey = groupKey(notification)
+	notification.CreatedAt = time.Now()
+	notification.UpdatedAt = notification.CreatedAt
+
+	notificationID, err := db.CreateNotification(&notification, actorID)
+	if err != nil {
+		log.Printf("Notification is not created. Reason: %v", err)
+		return
+	}
+
+	if notificationID == uuid.Nil {
+		return
+	}
+
+	notification.ID = notificationID
+
+	if slices.Contains(channels, models.InAppChannel) {
+		realtime.PublishNotification(notification.UserID, notificationID, db)
+	}
+
+	if slices.Contains(channels, models.PushChannel) {
+		enqueuePush(notification, db)
+	}
+}
+
+// NotifyMentions notifies users mentioned in the text of the post or the comment written by the actor.
+func NotifyMentions(
+	text string,
+	postID uuid.UUID,
+	commentID *uuid.UUID,
+	actorID uuid.UUID,
+	db *database.Queries,
+) {
+	usernames := lo.Map(texthelpers.ExtractMentions(text), func(item string, index int) string {
+		return strings.ToLower(item)
+	})
+	if len(usernames) == 0 {
+		return
+	}
+
+	users, err := db.GetUsersByUsernames(usernames)
+	if err != nil {
+		log.Printf("Notification is not created. Reason: %v", err)
+		return
+	}
+
+	for _, user := range users {
+		Notify(models.DBNotification{
+			UserID:    user.ID,
+			Type:      models.MentionNotification,
+			PostID:    &postID,
+			CommentID: commentID,
+		}, actorID, db)
+	}
+}
+
+// groupKey returns the key of events grouped into one notification while it is unread.
+// Mentions are never grouped.
+func groupKey(notification models.DBNotification) string {
+	switch notification.Type {
+	case models.FollowNotification:
+		return string(notification.Type)
+	case models.PostLikeNotification, models.CommentNotification:
+		return fmt.Sprintf("%s:%s", notification.Type, lo.FromPtr(notification.PostID))
+	case models.CommentLikeNotification, models.ReplyNotification:
+		return fmt.Sprintf("%s:%s", notification.Type, lo.FromPtr(notification.CommentID))
+	case models.MentionNotification:
+		return fmt.Sprintf("%s:%s", notification.Type, notification.ID)
+	default:
+		return notification.ID.String()
+	}
+}
+
+// PrepareNotificationToSend prepares a notification object for sending by fetching additional data
+// from the database such as the last actors of the notification.
+func PrepareNotificationToSend(notification models.DBNotification, db *database.Queries) models.Notification {
+	preparedNotification := notification.ToNotification()
+	preparedNotification.Actors = []models.User{}
+
+	actors, err := db.GetNotificationActors(notification.ID, configs.NotificationActorsPreviewCount)
+	if err == nil {
+		preparedNotification.Actors = lo.Map(actors, func(item models.DBUser, index int) models.User {
+			return item.ToUser()
+		})
+	}
+
+	preparedNotification.Text = notificationText(
+		notification.Type,
+		preparedNotification.Actors,
+		notification.ActorsCount,
+	)
+
+	return preparedNotification
+}
+
+// notificationText returns the summary of the notification, like "alice and 12 others liked your post".
+func notificationText(notificationType models.NotificationType, actors []models.User, actorsCount int) string {
+	actor := "someone"
+	if len(actors) > 0 {
+		actor = actors[0].Username
+	}
+
+	switch {
+	case actorsCount == 2:
+		actor += " and 1 other"
+	case actorsCount > 2:
+		actor += fmt.Sprintf(" and %d others", actorsCount-1)
+	}
+
+	return actor + " " + notificationAction(notificationType)
+}
+
+// notificationAction returns the description of the event of the given type.
+func notificationAction(notificationType models.NotificationType) string {
+	switch notificationType {
+	case models.FollowNotification:
+		return "followed you"
+	case models.PostLikeNotification:
+		return "reacted to your post"
+	case models.CommentLikeNotification:
+		return "reacted to your comment"
+	case models.CommentNotification:
+		return "commented on your post"
+	case models.ReplyNotification:
+		return "replied to your comment"
+	case models.MentionNotification:
+		return "mentioned you"
+	default:
+		return "interacted with you"
+	}
+}
+
+// GetNotificationTexts returns texts muted words are matched against in the notification.
+// Only mentions have their own text: the comment or the post description the user is mentioned in.
+func GetNotificationTexts(notification models.DBNotification, db *database.Queries) []string {
+	if notification.Type != models.MentionNotification {
+		return []string{}
+	}
+
+	if notification.CommentID != nil {
+		comment, err := db.GetComment(*notification.CommentID)
+		if err != nil {
+			return []string{}
+		}
+
+		return []string{comment.Content}
+	}
+
+	if notification.PostID != nil {
+		post, err := db.GetPost(*notification.PostID)
+		if err != nil {
+			return []string{}
+		}
+
+		return []string{post.Description, post.ContentWarning}
+	}
+
+	return []string{}
+}
@@ -0,0 +1,55 @@
+package notificationhelpers
+
+import (
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/samber/lo"
+)
+
+// minutesInDay is the number of minutes between two local midnights, ignoring daylight saving time.
+const minutesInDay = 24 * 60
+
+// enabledChannels returns channels notifications of the type are delivered through to the user.
+func enabledChannels(
+	notificationType models.NotificationType,
+	optOuts []models.NotificationPreference,
+) []models.NotificationChannel {
+	return lo.Filter(models.NotificationChannels(), func(channel models.NotificationChannel, index int) bool {
+		return !lo.ContainsBy(optOuts, func(item models.NotificationPreference) bool {
+			return item.Type == notificationType && item.Channel == channel
+		})
+	})
+}
+
+// QuietHoursEnd returns the time push notifications can be delivered to the user:
+// the end of quiet hours if they are in progress, otherwise the given time.
+// Quiet hours are ignored if the timezone of the user is unknown.
+func QuietHoursEnd(settings models.DBNotificationSettings, now time.Time) time.Time {
+	if settings.QuietHoursStart == nil || settings.QuietHoursEnd == nil {
+		return now
+	}
+
+	location, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		return now
+	}
+
+	local := now.In(location)
+	minutes := local.Hour()*60 + local.Minute()
+	start, end := *settings.QuietHoursStart, *settings.QuietHoursEnd
+
+	quiet := minutes >= start && minutes < end
+	if start > end {
+		// Quiet hours span the midnight, like 22:00-07:00
+		quiet = minutes >= start || minutes < end
+	}
+
+	if !quiet {
+		return now
+	}
+
+	untilEnd := (end - minutes + minutesInDay) % minutesInDay
+
+	return local.Truncate(time.Minute).Add(time.Duration(untilEnd) * time.Minute)
+}
@@ -0,0 +1,149 @@
+package notificationhelpers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/posthelpers"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/MangriMen/Diverse-Back/internal/webpush"
+	"github.com/google/uuid"
+)
+
+// pushPayload is the data of the notification received by the service worker of the browser.
+type pushPayload struct {
+	NotificationID uuid.UUID               `json:"notification_id"`
+	Type           models.NotificationType `json:"type"`
+	Text           string                  `json:"text"`
+	PostID         *uuid.UUID              `json:"post_id,omitempty"`
+	CommentID      *uuid.UUID              `json:"comment_id,omitempty"`
+	ActorsCount    int                     `json:"actors_count"`
+	UpdatedAt      time.Time               `json:"updated_at"`
+}
+
+// enqueuePush queues delivery of the notification to push subscriptions of its user,
+// it's delivered after quiet hours of the user if they are in progress.
+func enqueuePush(notification models.DBNotification, db *database.Queries) {
+	if !webpush.Enabled() {
+		return
+	}
+
+	settings, err := db.GetNotificationSettings(notification.UserID)
+	if err == nil {
+		now := time.Now()
+		err = db.EnqueuePushDeliveries(&notification, now, QuietHoursEnd(settings, now))
+	}
+
+	if err != nil {
+		log.Printf("Push notification is not queued. Reason: %v", err)
+	}
+}
+
+// DeliverPushNotifications sends queued push notifications which are due. Notifications read
+// or hidden by muted words meanwhile are dropped, failed deliveries are retried with growing delays
+// and subscriptions expired at the push service are deleted.
+func DeliverPushNotifications(sender *webpush.Sender, db *database.Queries) error {
+	now := time.Now()
+
+	deliveries, err := db.ClaimPushDeliveries(now, now.Add(configs.PushDeliveryLease), configs.PushDeliveryBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		if err = deliverPush(sender, delivery, db); err != nil {
+			log.Printf("Push notification is not delivered. Reason: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// deliverPush sends the notification of the delivery and updates the delivery by the result.
+func deliverPush(sender *webpush.Sender, delivery models.DBPushDelivery, db *database.Queries) error {
+	subscription, err := db.GetPushSubscription(delivery.SubscriptionID)
+	if err != nil {
+		return db.DeletePushDelivery(delivery.ID)
+	}
+
+	payload, send, err := preparePushPayload(delivery, db)
+	if err != nil || !send {
+		return errors.Join(err, db.DeletePushDelivery(delivery.ID))
+	}
+
+	err = sender.Send(webpush.Subscription{
+		Endpoint: subscription.Endpoint,
+		P256dh:   subscription.P256dh,
+		Auth:     subscription.Auth,
+	}, webpush.Message{
+		Payload: payload,
+		TTL:     configs.PushTTL,
+		// Later deliveries of the same notification replace undelivered ones
+		Topic:   strings.ReplaceAll(delivery.NotificationID.String(), "-", ""),
+		Urgency: webpush.NormalUrgency,
+	})
+	if err == nil {
+		return db.DeletePushDelivery(delivery.ID)
+	}
+
+	var statusErr *webpush.StatusError
+	if errors.As(err, &statusErr) && statusErr.Expired() {
+		return db.DeleteExpiredPushSubscription(subscription.ID)
+	}
+
+	if errors.As(err, &statusErr) && !statusErr.Temporary() || delivery.Attempts >= configs.PushMaxAttempts {
+		return errors.Join(err, db.DeletePushDelivery(delivery.ID))
+	}
+
+	delay := configs.PushRetryDelay << (delivery.Attempts - 1)
+	if statusErr != nil && statusErr.RetryAfter > delay {
+		delay = statusErr.RetryAfter
+	}
+
+	return errors.Join(err, db.RetryPushDelivery(delivery.ID, time.Now().Add(delay)))
+}
+
+// preparePushPayload returns the payload of the delivered notification.
+// Returns false if the notification is read or hidden by a muted word of the user.
+func preparePushPayload(delivery models.DBPushDelivery, db *database.Queries) ([]byte, bool, error) {
+	notification, err := db.GetNotification(delivery.NotificationID)
+	if errors.Is(err, sql.ErrNoRows) || err == nil && notification.ReadAt != nil {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	mutedWordsFilter, err := posthelpers.NewMutedWordsFilter(notification.UserID, models.NotificationsScope, db)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if _, muted := mutedWordsFilter.Match(GetNotificationTexts(notification, db)...); muted {
+		return nil, false, nil
+	}
+
+	preparedNotification := PrepareNotificationToSend(notification, db)
+
+	data, err := json.Marshal(pushPayload{
+		NotificationID: notification.ID,
+		Type:           notification.Type,
+		Text:           preparedNotification.Text,
+		PostID:         notification.PostID,
+		CommentID:      notification.CommentID,
+		ActorsCount:    notification.ActorsCount,
+		UpdatedAt:      notification.UpdatedAt,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return data, true, nil
+}
@@ -0,0 +1,62 @@
+package posthelpers
+
+import (
+	"strings"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/texthelpers"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/userhelpers"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/MangriMen/Diverse-Back/internal/parameters"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// CanComment returns whether the user is allowed to comment the post by the comment policy of the post.
+// The author can always comment own posts, users restricted by the author or blocked by or blocking
+// the author can't comment any of them.
+func CanComment(post models.DBPost, userID uuid.UUID, db *database.Queries) (bool, error) {
+	if userID == post.UserID {
+		return true, nil
+	}
+
+	restricted, err := db.IsUserRestricted(post.UserID, userID)
+	if err != nil || restricted {
+		return false, err
+	}
+
+	rawRelationStatus, err := db.GetRelationStatus(&parameters.RelationGetStatusParams{
+		UserIDParams: parameters.UserIDParams{User: post.UserID},
+		RelationUserIDParams: parameters.RelationUserIDParams{
+			RelationUser: userID,
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	relationStatus := userhelpers.PrepareRelationStatusToSend(post.UserID, rawRelationStatus)
+	if relationStatus[models.Blocked] {
+		return false, nil
+	}
+
+	switch post.CommentPolicy {
+	case models.CommentsFromEveryone:
+		return true, nil
+	case models.CommentsFromFollowing:
+		return relationStatus[models.Following], nil
+	case models.CommentsFromMentioned:
+		user, err := db.GetUser(userID)
+		if err != nil {
+			return false, err
+		}
+
+		return lo.ContainsBy(texthelpers.ExtractMentions(post.Description), func(item string) bool {
+			return strings.EqualFold(item, user.Username)
+		}), nil
+	case models.CommentsOff:
+		return false, nil
+	default:
+		return false, nil
+	}
+}
@@ -0,0 +1,102 @@
+package posthelpers
+
+import (
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/texthelpers"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// MutedWordsFilter applies the words muted by the viewer in one scope to posts and comments.
+// Own posts and comments of the viewer are never filtered.
+type MutedWordsFilter struct {
+	UserID     uuid.UUID
+	MutedWords []models.DBMutedWord
+}
+
+// NewMutedWordsFilter creates the filter with the active muted words of the given user in the scope.
+func NewMutedWordsFilter(
+	userID uuid.UUID,
+	scope models.MutedWordScope,
+	db *database.Queries,
+) (MutedWordsFilter, error) {
+	mutedWords, err := db.GetActiveMutedWords(userID, scope, time.Now())
+	if err != nil {
+		return MutedWordsFilter{}, err
+	}
+
+	return MutedWordsFilter{UserID: userID, MutedWords: mutedWords}, nil
+}
+
+// Match returns the first muted word found in any of the texts.
+func (f MutedWordsFilter) Match(texts ...string) (models.DBMutedWord, bool) {
+	return lo.Find(f.MutedWords, func(item models.DBMutedWord) bool {
+		return lo.SomeBy(texts, func(text string) bool {
+			return texthelpers.ContainsPhrase(text, item.Phrase, item.WholeWord)
+		})
+	})
+}
+
+// FilterPost returns false if the post is hidden from the viewer by a muted word,
+// posts collapsed by a muted word get the phrase in FilteredBy.
+// Embedded comments are filtered by the filter of the comments scope.
+func (f MutedWordsFilter) FilterPost(post *models.Post, authorID uuid.UUID, commentsFilter MutedWordsFilter) bool {
+	post.Comments = commentsFilter.FilterComments(post.Comments)
+
+	if authorID == f.UserID {
+		return true
+	}
+
+	visible, filteredBy := f.filter(post.Description, post.ContentWarning)
+	post.FilteredBy = filteredBy
+
+	return visible
+}
+
+// FilterComments drops comments hidden from the viewer by a muted word and marks collapsed ones,
+// first replies embedded into the comments are filtered the same way.
+func (f MutedWordsFilter) FilterComments(comments []models.Comment) []models.Comment {
+	filtered := make([]models.Comment, 0, len(comments))
+
+	for _, comment := range comments {
+		comment.Replies = f.FilterComments(comment.Replies)
+
+		if comment.User == nil || comment.User.ID != f.UserID {
+			var visible bool
+			if visible, comment.FilteredBy = f.filter(comment.Content); !visible {
+				continue
+			}
+		}
+
+		filtered = append(filtered, comment)
+	}
+
+	return filtered
+}
+
+// FilterNotification returns false if the notification about the texts is hidden from the viewer
+// by a muted word, notifications collapsed by a muted word get the phrase in FilteredBy.
+func (f MutedWordsFilter) FilterNotification(notification *models.Notification, texts ...string) bool {
+	visible, filteredBy := f.filter(texts...)
+	notification.FilteredBy = filteredBy
+
+	return visible
+}
+
+// filter returns whether the item with the texts is visible to the viewer
+// and the phrase the item is collapsed by.
+func (f MutedWordsFilter) filter(texts ...string) (bool, *string) {
+	mutedWord, found := f.Match(texts...)
+	if !found {
+		return true, nil
+	}
+
+	if mutedWord.Action == models.HideMuted {
+		return false, nil
+	}
+
+	return true, &mutedWord.Phrase
+}
@@ -0,0 +1,64 @@
+package posthelpers
+
+import (
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/internal/helpers"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+)
+
+// PreparePollToSend prepares the poll of the post for sending by fetching the options
+// and the votes of the requester. Unless the poll shows results before voting, the results
+// are hidden until the requester votes or the poll is closed, the author always sees them.
+func PreparePollToSend(
+	poll models.DBPoll,
+	authorID uuid.UUID,
+	userID uuid.UUID,
+	db *database.Queries,
+) (models.Poll, error) {
+	options, err := db.GetPollOptions(poll.ID)
+	if err != nil {
+		return models.Poll{}, err
+	}
+
+	voted, err := db.HasVotedInPoll(poll.ID, userID)
+	if err != nil {
+		return models.Poll{}, err
+	}
+
+	myVotes := []uuid.UUID{}
+	if voted && !poll.Anonymous {
+		if myVotes, err = db.GetPollVotes(poll.ID, userID); err != nil {
+			return models.Poll{}, err
+		}
+	}
+
+	closed := poll.IsClosed(time.Now())
+	showResults := poll.ShowResults || voted || closed || userID == authorID
+
+	preparedPoll := models.Poll{
+		ID:        poll.ID,
+		Multiple:  poll.Multiple,
+		Anonymous: poll.Anonymous,
+		ExpiresAt: poll.ExpiresAt,
+		Closed:    closed,
+		Voted:     voted,
+		Options: lo.Map(options, func(item models.DBPollOption, index int) models.PollOption {
+			option := models.PollOption{ID: item.ID, Text: item.Text}
+			if showResults {
+				option.Votes = helpers.Ptr(item.Votes)
+			}
+			return option
+		}),
+		MyVotes: myVotes,
+	}
+
+	if showResults {
+		preparedPoll.VotersCount = helpers.Ptr(poll.VotersCount)
+	}
+
+	return preparedPoll, nil
+}
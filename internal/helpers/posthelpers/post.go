@@ -4,7 +4,6 @@ package posthelpers
 
 import (
 	"fmt"
-	"math"
 	"time"
 
 	"github.com/MangriMen/Diverse-Back/api/database"
@@ -15,16 +14,29 @@ import (
 	"github.com/MangriMen/Diverse-Back/internal/parameters"
 	"github.com/google/uuid"
 	"github.com/samber/lo"
+	"golang.org/x/exp/slices"
 )
 
 // PreparePostToSend prepares a post object for sending by fetching additional data from the database
-// such as the user associated with the post and the comments associated with the post.
+// such as the user associated with the post and the newest comments associated with the post.
 func PreparePostToSend(post models.DBPost, userID uuid.UUID, db *database.Queries) models.Post {
+	return PreparePostWithCommentsToSend(post, userID, parameters.NewestComments, db)
+}
+
+// PreparePostWithCommentsToSend prepares a post object for sending like PreparePostToSend
+// with the first comments in the given order.
+func PreparePostWithCommentsToSend(
+	post models.DBPost,
+	userID uuid.UUID,
+	commentsSort parameters.CommentSort,
+	db *database.Queries,
+) models.Post {
 	preparedPost := post.ToPost()
+	preparedPost.Entities, preparedPost.DescriptionHTML = PreparePostText(post, db)
 
-	isLikedByRequester, err := db.GetPostIsLiked(post.ID, userID)
+	requesterReaction, err := db.GetPostReaction(post.ID, userID)
 	if err == nil {
-		preparedPost.LikedByMe = isLikedByRequester
+		preparedPost.MyReaction = helpers.PtrOrNil(requesterReaction)
 	}
 
 	user, err := db.GetUser(post.UserID)
@@ -32,27 +44,84 @@ func PreparePostToSend(post models.DBPost, userID uuid.UUID, db *database.Querie
 		preparedPost.User = helpers.Ptr(user.ToUser())
 	}
 
+	canComment, err := CanComment(post, userID, db)
+	if err == nil {
+		preparedPost.CanComment = canComment
+	}
+
+	poll, err := db.GetPostPoll(post.ID)
+	if err == nil {
+		if preparedPoll, pollErr := PreparePollToSend(poll, post.UserID, userID, db); pollErr == nil {
+			preparedPost.Poll = &preparedPoll
+		}
+	}
+
 	comments, err := db.GetComments(
 		post.ID,
-		&parameters.CommentsFetchRequestQuery{
-			Count:                    configs.PostFetchCommentCount,
-			LastSeenCommentCreatedAt: time.Now(),
-		},
+		userID,
+		commentsSort,
+		nil,
+		configs.PostFetchCommentCount,
+		time.Now(),
 	)
 	if err == nil {
-		preparedPost.Comments = lo.Map(
-			comments,
+		preparedPost.Comments = append(
+			PreparePinnedCommentToPost(post.ID, userID, db),
+			lo.Map(
+				comments,
+				func(item models.DBRankedComment, index int) models.Comment {
+					return PrepareCommentWithRepliesToPost(item.DBComment, userID, db)
+				},
+			)...,
+		)
+	}
+
+	return preparedPost
+}
+
+// PrepareCommentWithRepliesToPost prepares a comment object for inclusion in a post object
+// with a preview of the first replies to the comment.
+func PrepareCommentWithRepliesToPost(
+	comment models.DBComment,
+	userID uuid.UUID,
+	db *database.Queries,
+) models.Comment {
+	preparedComment := PrepareCommentToPost(comment, userID, db)
+
+	if comment.ReplyCount == 0 {
+		return preparedComment
+	}
+
+	replies, err := db.GetCommentReplies(comment.ID, userID, nil, configs.CommentRepliesPreviewCount)
+	if err == nil {
+		preparedComment.Replies = lo.Map(
+			replies,
 			func(item models.DBComment, index int) models.Comment {
 				return PrepareCommentToPost(item, userID, db)
 			},
 		)
 	}
 
-	return preparedPost
+	return preparedComment
+}
+
+// PreparePinnedCommentToPost prepares the pinned comment of the post to go before other comments.
+// Returns empty slice if the post has no pinned comment visible to the user.
+func PreparePinnedCommentToPost(postID uuid.UUID, userID uuid.UUID, db *database.Queries) []models.Comment {
+	pinnedComment, err := db.GetPinnedComment(postID, userID)
+	if err != nil {
+		return []models.Comment{}
+	}
+
+	preparedComment := PrepareCommentWithRepliesToPost(pinnedComment, userID, db)
+	preparedComment.Pinned = true
+
+	return []models.Comment{preparedComment}
 }
 
 // PrepareCommentToPost prepares a comment object for inclusion in a post object
 // by fetching additional data from the database such as the user associated with the comment.
+// Tombstoned comments keep only their place in the thread, without the content and the user.
 func PrepareCommentToPost(
 	comment models.DBComment,
 	userID uuid.UUID,
@@ -60,9 +129,17 @@ func PrepareCommentToPost(
 ) models.Comment {
 	preparedComment := comment.ToComment()
 
-	isLikedByRequester, err := db.GetCommentIsLiked(comment.ID, userID)
+	if comment.Tombstoned {
+		preparedComment.Content = ""
+		preparedComment.Entities = []models.Entity{}
+		return preparedComment
+	}
+
+	preparedComment.Entities, preparedComment.ContentHTML = PrepareCommentText(comment, db)
+
+	requesterReaction, err := db.GetCommentReaction(comment.ID, userID)
 	if err == nil {
-		preparedComment.LikedByMe = isLikedByRequester
+		preparedComment.MyReaction = helpers.PtrOrNil(requesterReaction)
 	}
 
 	user, err := db.GetUser(comment.UserID)
@@ -74,6 +151,7 @@ func PrepareCommentToPost(
 }
 
 // GenerateFilter generates a filter for SQL query to fetch posts by the specified parameters.
+// Subscriptions are fetched from the user timeline and have no filter.
 func GenerateFilter(
 	userID uuid.UUID,
 	postFetchType parameters.PostFetchType,
@@ -83,31 +161,6 @@ func GenerateFilter(
 	const conditionFormatString = "AND user_id='%s'"
 
 	switch postFetchType {
-	case parameters.Subscriptions:
-		rawRelations, rawRelationsErr := db.GetRelations(
-			userID,
-			&parameters.RelationGetRequestQuery{
-				Type:                      models.Following,
-				Count:                     math.MaxInt64,
-				LastSeenRelationCreatedAt: time.Now(),
-			},
-		)
-		if rawRelationsErr != nil {
-			return "", rawRelationsErr
-		}
-
-		relations := lo.Map(
-			rawRelations,
-			func(item models.DBRelation, index int) models.Relation {
-				return *userhelpers.PrepareRelationToSend(item, db)
-			},
-		)
-
-		condition := ""
-		for _, relation := range relations {
-			condition += fmt.Sprintf(conditionFormatString, relation.RelationUser.ID.String())
-		}
-		return condition, nil
 	case parameters.User:
 		rawRelationStatus, err := db.GetRelationStatus(&parameters.RelationGetStatusParams{
 			UserIDParams: parameters.UserIDParams{User: userID},
@@ -128,7 +181,40 @@ func GenerateFilter(
 		return fmt.Sprintf(conditionFormatString, postsAuthorID), nil
 	case parameters.All:
 		return "", nil
+	case parameters.Subscriptions:
+		return "", fmt.Errorf(configs.PostsInvalidFilter)
 	default:
 		return "", fmt.Errorf(configs.PostsInvalidFilter)
 	}
 }
+
+// GenerateNotPinnedFilter generates a filter for SQL query to skip pinned posts of the author.
+func GenerateNotPinnedFilter(postsAuthorID uuid.UUID) string {
+	return fmt.Sprintf(
+		"AND id NOT IN (SELECT post_id FROM pinned_posts WHERE user_id='%s')",
+		postsAuthorID,
+	)
+}
+
+// ValidateReaction returns error if the reaction is not supported.
+func ValidateReaction(reaction string) error {
+	if !slices.Contains(configs.GetReactions(), reaction) {
+		return fmt.Errorf(configs.ReactionInvalidError)
+	}
+
+	return nil
+}
+
+// GetLanguageOrDefault returns the given text search configuration or default one if it is empty.
+// Returns error if the configuration is not supported.
+func GetLanguageOrDefault(language string) (string, error) {
+	if language == "" {
+		return configs.SearchDefaultLanguage, nil
+	}
+
+	if !slices.Contains(configs.GetSearchLanguages(), language) {
+		return "", fmt.Errorf(configs.SearchInvalidLanguage)
+	}
+
+	return language, nil
+}
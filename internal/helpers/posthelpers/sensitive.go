@@ -0,0 +1,45 @@
+package posthelpers
+
+import (
+	"fmt"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/google/uuid"
+)
+
+// SensitiveContentFilter applies the sensitive content preference of the viewer to posts.
+// Own posts of the viewer are never hidden or blurred.
+type SensitiveContentFilter struct {
+	UserID     uuid.UUID
+	Preference models.SensitiveContentPreference
+}
+
+// NewSensitiveContentFilter creates the filter with the preference of the given user.
+func NewSensitiveContentFilter(userID uuid.UUID, db *database.Queries) (SensitiveContentFilter, error) {
+	user, err := db.GetUser(userID)
+	if err != nil {
+		return SensitiveContentFilter{}, err
+	}
+
+	return SensitiveContentFilter{UserID: userID, Preference: user.SensitiveContent}, nil
+}
+
+// Condition generates a filter for SQL query to skip posts hidden from the viewer.
+func (f SensitiveContentFilter) Condition() string {
+	if f.Preference != models.HideSensitive {
+		return ""
+	}
+
+	return fmt.Sprintf("AND (NOT sensitive OR user_id='%s')", f.UserID)
+}
+
+// Hides reports whether the post is hidden from the viewer.
+func (f SensitiveContentFilter) Hides(post models.DBPost) bool {
+	return f.Preference == models.HideSensitive && post.Sensitive && post.UserID != f.UserID
+}
+
+// Blurs reports whether the post should be blurred for the viewer.
+func (f SensitiveContentFilter) Blurs(post models.DBPost) bool {
+	return f.Preference == models.BlurSensitive && post.Sensitive && post.UserID != f.UserID
+}
@@ -0,0 +1,56 @@
+package posthelpers
+
+import (
+	"log"
+	"strings"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/texthelpers"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/samber/lo"
+)
+
+// ParseEntities finds entities of the post description or the comment content when it is written.
+// Mentions are resolved to ids of existing users, mentions of unknown users stay plain text.
+func ParseEntities(text string, db *database.Queries) models.Entities {
+	entities := texthelpers.ParseEntities(text, func(usernames []string) map[string]string {
+		users, err := db.GetUsersByUsernames(usernames)
+		if err != nil {
+			return map[string]string{}
+		}
+
+		return lo.SliceToMap(users, func(item models.DBUser) (string, string) {
+			return strings.ToLower(item.Username), item.ID.String()
+		})
+	})
+
+	return append(models.Entities{}, entities...)
+}
+
+// PreparePostText renders the description of the post with its stored entities to HTML.
+// Entities of posts written before entities were stored are parsed and saved once.
+func PreparePostText(post models.DBPost, db *database.Queries) ([]models.Entity, string) {
+	entities := post.Entities
+	if entities == nil {
+		entities = ParseEntities(post.Description, db)
+		if err := db.SetPostEntities(post.ID, entities); err != nil {
+			log.Printf("Post entities are not saved. Reason: %v", err)
+		}
+	}
+
+	return entities, texthelpers.RenderHTML(post.Description, entities, post.Markdown)
+}
+
+// PrepareCommentText renders the content of the comment with its stored entities to HTML.
+// Entities of comments written before entities were stored are parsed and saved once.
+func PrepareCommentText(comment models.DBComment, db *database.Queries) ([]models.Entity, string) {
+	entities := comment.Entities
+	if entities == nil {
+		entities = ParseEntities(comment.Content, db)
+		if err := db.SetCommentEntities(comment.ID, entities); err != nil {
+			log.Printf("Comment entities are not saved. Reason: %v", err)
+		}
+	}
+
+	return entities, texthelpers.RenderHTML(comment.Content, entities, comment.Markdown)
+}
@@ -0,0 +1,40 @@
+package posthelpers
+
+import (
+	"fmt"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/userhelpers"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/MangriMen/Diverse-Back/internal/parameters"
+)
+
+// CheckVisibility returns error if the post is not available to the viewer of the filter,
+// because the viewer and the author blocked one another or the post is hidden
+// by the sensitive content preference. Archived posts are never fetched for viewers.
+func CheckVisibility(post models.DBPost, filter SensitiveContentFilter, db *database.Queries) error {
+	if post.UserID != filter.UserID {
+		rawRelationStatus, err := db.GetRelationStatus(&parameters.RelationGetStatusParams{
+			UserIDParams: parameters.UserIDParams{User: filter.UserID},
+			RelationUserIDParams: parameters.RelationUserIDParams{
+				RelationUser: post.UserID,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		relationStatus := userhelpers.PrepareRelationStatusToSend(filter.UserID, rawRelationStatus)
+
+		if relationStatus[models.Blocked] {
+			return fmt.Errorf(configs.UserBlocked)
+		}
+	}
+
+	if filter.Hides(post) {
+		return fmt.Errorf(configs.PostHiddenError)
+	}
+
+	return nil
+}
@@ -10,7 +10,8 @@ import (
 )
 
 // StartServerWithGracefulShutdown is starting server with a graceful shutdown.
-func StartServerWithGracefulShutdown(a *fiber.App) {
+// Functions closing long-lived connections are called before the shutdown, which waits for them.
+func StartServerWithGracefulShutdown(a *fiber.App, beforeShutdown ...func()) {
 	idleConnectionsClosed := make(chan struct{})
 
 	go func() {
@@ -19,6 +20,10 @@ func StartServerWithGracefulShutdown(a *fiber.App) {
 		<-sigint
 
 		// Received an interrupt signal, shutdown
+		for _, f := range beforeShutdown {
+			f()
+		}
+
 		if err := a.Shutdown(); err != nil {
 			// Error from closing listeners, or context timeout:
 			log.Printf("Oops... Server cannot be shutted down! Reason: %v", err)
@@ -20,6 +20,14 @@ func GetNotEmpty[T comparable](a T, b T) T {
 // Ptr returns pointer to an object.
 func Ptr[T any](obj T) *T { return &obj }
 
+// PtrOrNil returns pointer to an object or nil if the object has zero value.
+func PtrOrNil[T comparable](obj T) *T {
+	if obj == *new(T) {
+		return nil
+	}
+	return &obj
+}
+
 // CloseQuietly close io.Closer object quietly without returning error.
 func CloseQuietly[T io.Closer](entity T) {
 	if err := entity.Close(); err != nil {
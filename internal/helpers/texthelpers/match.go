@@ -0,0 +1,52 @@
+package texthelpers
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ContainsPhrase reports whether the text contains the phrase ignoring case. With wholeWord the phrase
+// matches only when it is not a part of a longer word, so "cat" matches "#cat" but not "category".
+func ContainsPhrase(text string, phrase string, wholeWord bool) bool {
+	text = strings.ToLower(text)
+	phrase = strings.ToLower(phrase)
+
+	if phrase == "" {
+		return false
+	}
+
+	if !wholeWord {
+		return strings.Contains(text, phrase)
+	}
+
+	for offset := 0; offset < len(text); {
+		index := strings.Index(text[offset:], phrase)
+		if index < 0 {
+			return false
+		}
+
+		start := offset + index
+		end := start + len(phrase)
+
+		if !isWordByteBefore(text, start) && !isWordByteAfter(text, end) {
+			return true
+		}
+
+		_, size := utf8.DecodeRuneInString(text[start:])
+		offset = start + size
+	}
+
+	return false
+}
+
+// isWordByteAfter returns whether the rune at the position is a letter, a digit or an underscore.
+func isWordByteAfter(text string, position int) bool {
+	if position >= len(text) {
+		return false
+	}
+
+	r, _ := utf8.DecodeRuneInString(text[position:])
+
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
@@ -0,0 +1,217 @@
+package texthelpers
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+)
+
+// Entities are replaced with placeholders from the private use area while the text is formatted,
+// formatted markdown links are replaced with other ones while emphasis is formatted.
+const (
+	placeholderStart     = '\uE000'
+	placeholderEnd       = '\uE001'
+	linkPlaceholderStart = '\uE004'
+	linkPlaceholderEnd   = '\uE005'
+)
+
+var (
+	placeholderRegexp     = regexp.MustCompile(`\x{E000}([0-9]+)\x{E001}`)
+	linkPlaceholderRegexp = regexp.MustCompile(`\x{E004}([0-9]+)\x{E005}`)
+	codeRegexp            = regexp.MustCompile("`([^`\n]+)`")
+	linkRegexp            = regexp.MustCompile(`\[([^\]\n\x{E000}]+)\]\(\x{E000}([0-9]+)\x{E001}\)`)
+	boldRegexp            = regexp.MustCompile(`\*\*([^*\n]+)\*\*`)
+	italicRegexp          = regexp.MustCompile(`\*([^*\n]+)\*`)
+	underscoreRegexp      = regexp.MustCompile(`(^|[^\p{L}\p{N}_])_([^_\n]+)_($|[^\p{L}\p{N}_])`)
+	strikeRegexp          = regexp.MustCompile(`~~([^~\n]+)~~`)
+)
+
+// RenderHTML renders the text with its entities to sanitized HTML. All the text is escaped,
+// entities become links and line breaks become <br>. With markdown the limited subset is formatted:
+// **bold**, *italic* or _italic_, ~~strikethrough~~, `code` and [text](link) with detected links only.
+func RenderHTML(text string, entities []models.Entity, markdown bool) string {
+	byteOffsets := utf16ToByteOffsets(text)
+
+	builder := strings.Builder{}
+	spanTexts := map[int]string{}
+	position := 0
+	for index, entity := range entities {
+		start, startOK := byteOffsets[entity.Offset]
+		end, endOK := byteOffsets[entity.Offset+entity.Length]
+		if !startOK || !endOK || start < position {
+			continue
+		}
+
+		builder.WriteString(stripPlaceholders(text[position:start]))
+		builder.WriteString(fmt.Sprintf("%c%d%c", placeholderStart, index, placeholderEnd))
+		spanTexts[index] = text[start:end]
+		position = end
+	}
+	builder.WriteString(stripPlaceholders(text[position:]))
+
+	rendered := html.EscapeString(builder.String())
+
+	if markdown {
+		rendered = renderMarkdown(rendered, entities)
+	}
+
+	rendered = strings.ReplaceAll(rendered, "\n", "<br>")
+
+	return placeholderRegexp.ReplaceAllStringFunc(rendered, func(placeholder string) string {
+		index, err := strconv.Atoi(placeholderRegexp.FindStringSubmatch(placeholder)[1])
+		if err != nil {
+			return ""
+		}
+
+		return entityLink(entities[index], html.EscapeString(spanTexts[index]))
+	})
+}
+
+// renderMarkdown formats the escaped text, the content of code spans is not formatted.
+func renderMarkdown(text string, entities []models.Entity) string {
+	builder := strings.Builder{}
+	position := 0
+
+	for _, match := range codeRegexp.FindAllStringSubmatchIndex(text, -1) {
+		builder.WriteString(renderInlineMarkdown(text[position:match[0]], entities))
+		builder.WriteString("<code>" + text[match[2]:match[3]] + "</code>")
+		position = match[1]
+	}
+	builder.WriteString(renderInlineMarkdown(text[position:], entities))
+
+	return builder.String()
+}
+
+// renderInlineMarkdown formats links and emphasis of the escaped text. Links are kept
+// as placeholders while emphasis is formatted, so it can't get into their targets.
+func renderInlineMarkdown(text string, entities []models.Entity) string {
+	links := []string{}
+
+	text = linkRegexp.ReplaceAllStringFunc(text, func(link string) string {
+		match := linkRegexp.FindStringSubmatch(link)
+
+		index, err := strconv.Atoi(match[2])
+		if err != nil || index >= len(entities) || entities[index].Type != models.URLEntity {
+			return link
+		}
+
+		links = append(links, fmt.Sprintf(
+			`<a href="%s" rel="nofollow noopener noreferrer" target="_blank">%s</a>`,
+			html.EscapeString(entities[index].Target),
+			renderEmphasis(match[1]),
+		))
+
+		return fmt.Sprintf("%c%d%c", linkPlaceholderStart, len(links)-1, linkPlaceholderEnd)
+	})
+
+	text = renderEmphasis(text)
+
+	return linkPlaceholderRegexp.ReplaceAllStringFunc(text, func(placeholder string) string {
+		index, err := strconv.Atoi(linkPlaceholderRegexp.FindStringSubmatch(placeholder)[1])
+		if err != nil || index >= len(links) {
+			return ""
+		}
+
+		return links[index]
+	})
+}
+
+// renderEmphasis formats bold, italic and strikethrough spans of the escaped text.
+func renderEmphasis(text string) string {
+	text = boldRegexp.ReplaceAllString(text, "<strong>$1</strong>")
+	text = italicRegexp.ReplaceAllString(text, "<em>$1</em>")
+	text = underscoreRegexp.ReplaceAllString(text, "$1<em>$2</em>$3")
+	text = strikeRegexp.ReplaceAllString(text, "<del>$1</del>")
+
+	return text
+}
+
+// entityLink returns the link to the target of the entity with the given escaped text.
+func entityLink(entity models.Entity, escapedText string) string {
+	switch entity.Type {
+	case models.URLEntity:
+		return fmt.Sprintf(
+			`<a href="%s" rel="nofollow noopener noreferrer" target="_blank">%s</a>`,
+			html.EscapeString(entity.Target),
+			escapedText,
+		)
+	case models.MentionEntity:
+		return fmt.Sprintf(
+			`<a href="%s" class="mention">%s</a>`,
+			html.EscapeString(fmt.Sprintf(configs.MentionLinkFormat, url.PathEscape(entity.Target))),
+			escapedText,
+		)
+	case models.HashtagEntity:
+		return fmt.Sprintf(
+			`<a href="%s" class="hashtag">%s</a>`,
+			html.EscapeString(fmt.Sprintf(configs.HashtagLinkFormat, url.QueryEscape(entity.Target))),
+			escapedText,
+		)
+	default:
+		return escapedText
+	}
+}
+
+// utf16ToByteOffsets maps offsets in UTF-16 code units at rune boundaries to byte offsets.
+func utf16ToByteOffsets(text string) map[int]int {
+	offsets := map[int]int{}
+
+	utf16Offset := 0
+	for byteOffset, r := range text {
+		offsets[utf16Offset] = byteOffset
+		if r >= surrogatePairStart {
+			utf16Offset += 2
+		} else {
+			utf16Offset++
+		}
+	}
+	offsets[utf16Offset] = len(text)
+
+	return offsets
+}
+
+// stripPlaceholders replaces placeholder runes of the text to keep them from being rendered as entities.
+func stripPlaceholders(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r == placeholderStart || r == placeholderEnd || r == linkPlaceholderStart || r == linkPlaceholderEnd {
+			return '\uFFFD'
+		}
+		return r
+	}, text)
+}
+
+// RenderHeadline renders the search headline to sanitized HTML. All the text is escaped
+// and found words marked by the database become <mark> tags, which are always balanced.
+func RenderHeadline(headline string) string {
+	builder := strings.Builder{}
+	marked := false
+
+	for _, r := range html.EscapeString(headline) {
+		switch string(r) {
+		case configs.SearchHeadlineStartSel:
+			if !marked {
+				builder.WriteString("<mark>")
+				marked = true
+			}
+		case configs.SearchHeadlineStopSel:
+			if marked {
+				builder.WriteString("</mark>")
+				marked = false
+			}
+		default:
+			builder.WriteRune(r)
+		}
+	}
+
+	if marked {
+		builder.WriteString("</mark>")
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,236 @@
+// Package texthelpers provides the pipeline for user text: normalization,
+// detection of links, mentions and hashtags and rendering to sanitized HTML.
+package texthelpers
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/samber/lo"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	urlRegexp        = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"'\x60]+`)
+	mentionRegexp    = regexp.MustCompile(`@([\p{L}\p{N}_.]{1,32})`)
+	hashtagRegexp    = regexp.MustCompile(`#([\p{L}\p{N}_]+)`)
+	extraLinesRegexp = regexp.MustCompile(`\n{3,}`)
+)
+
+// surrogatePairStart is the first rune encoded in UTF-16 with two code units.
+const surrogatePairStart = 0x10000
+
+// span is the entity with its position in bytes.
+type span struct {
+	start int
+	end   int
+
+	entityType models.EntityType
+	target     string
+}
+
+// Normalize prepares user text for storing. The text is converted to NFC, line breaks are unified,
+// control characters and bidirectional overrides are removed, runs of empty lines are shortened
+// and surrounding whitespace is trimmed.
+func Normalize(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = norm.NFC.String(text)
+
+	text = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\t':
+			return r
+		case r == '\r':
+			return '\n'
+		case unicode.IsControl(r),
+			r == utf8.RuneError,
+			r == '\uFEFF',
+			r >= '\u202A' && r <= '\u202E',
+			r >= '\u2066' && r <= '\u2069':
+			return -1
+		default:
+			return r
+		}
+	}, text)
+
+	return strings.TrimSpace(extraLinesRegexp.ReplaceAllString(text, "\n\n"))
+}
+
+// ExtractMentions returns unique usernames mentioned in the text as @username.
+func ExtractMentions(text string) []string {
+	return lo.Uniq(lo.Map(findMentions(text), func(item span, index int) string {
+		return item.target
+	}))
+}
+
+// ParseEntities finds links, mentions and hashtags in the text. Usernames of mentions are resolved
+// to user ids by the given function, which receives unique usernames and returns ids by lowercase
+// usernames, mentions of unknown users are skipped. Mentions and hashtags inside links are skipped.
+func ParseEntities(text string, resolveMentions func(usernames []string) map[string]string) []models.Entity {
+	spans := findURLs(text)
+
+	overlapsURL := func(item span) bool {
+		return lo.ContainsBy(spans, func(urlSpan span) bool {
+			return item.start < urlSpan.end && urlSpan.start < item.end
+		})
+	}
+
+	mentions := lo.Reject(findMentions(text), func(item span, index int) bool {
+		return overlapsURL(item)
+	})
+
+	if len(mentions) > 0 {
+		userIDs := resolveMentions(lo.Uniq(lo.Map(mentions, func(item span, index int) string {
+			return item.target
+		})))
+
+		for _, mention := range mentions {
+			if userID, ok := userIDs[strings.ToLower(mention.target)]; ok {
+				mention.target = userID
+				spans = append(spans, mention)
+			}
+		}
+	}
+
+	for _, hashtag := range findHashtags(text) {
+		if !overlapsURL(hashtag) {
+			spans = append(spans, hashtag)
+		}
+	}
+
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].start < spans[j].start
+	})
+
+	return lo.Map(spans, func(item span, index int) models.Entity {
+		offset := utf16Len(text[:item.start])
+		return models.Entity{
+			Type:   item.entityType,
+			Offset: offset,
+			Length: utf16Len(text[:item.end]) - offset,
+			Target: item.target,
+		}
+	})
+}
+
+// findURLs returns http and https links of the text. Trailing punctuation is not
+// a part of the link, closing brackets are kept only if they are balanced.
+func findURLs(text string) []span {
+	spans := []span{}
+
+	for _, match := range urlRegexp.FindAllStringIndex(text, -1) {
+		start, end := match[0], match[1]
+		if start > 0 && isWordByteBefore(text, start) {
+			continue
+		}
+
+		for end > start {
+			last := text[end-1]
+			if strings.IndexByte(".,;:!?'\"*_~", last) >= 0 ||
+				(last == ')' && strings.Count(text[start:end], "(") < strings.Count(text[start:end], ")")) ||
+				(last == ']' && strings.Count(text[start:end], "[") < strings.Count(text[start:end], "]")) {
+				end--
+				continue
+			}
+			break
+		}
+
+		target := text[start:end]
+		if !strings.Contains(strings.ToLower(target), "://") {
+			target = "https://" + target
+		}
+
+		parsedURL, err := url.Parse(target)
+		if err != nil || parsedURL.Host == "" {
+			continue
+		}
+
+		spans = append(spans, span{
+			start:      start,
+			end:        end,
+			entityType: models.URLEntity,
+			target:     parsedURL.String(),
+		})
+	}
+
+	return spans
+}
+
+// findMentions returns mentions of the text with usernames as targets.
+// Mentions preceded by letters, like in emails, are skipped.
+func findMentions(text string) []span {
+	spans := []span{}
+
+	for _, match := range mentionRegexp.FindAllStringSubmatchIndex(text, -1) {
+		start, end := match[0], match[1]
+		if isWordByteBefore(text, start) {
+			continue
+		}
+
+		for end > match[2] && text[end-1] == '.' {
+			end--
+		}
+		if end == match[2] {
+			continue
+		}
+
+		spans = append(spans, span{
+			start:      start,
+			end:        end,
+			entityType: models.MentionEntity,
+			target:     text[match[2]:end],
+		})
+	}
+
+	return spans
+}
+
+// findHashtags returns hashtags of the text with lowercase tags as targets.
+func findHashtags(text string) []span {
+	spans := []span{}
+
+	for _, match := range hashtagRegexp.FindAllStringSubmatchIndex(text, -1) {
+		if isWordByteBefore(text, match[0]) {
+			continue
+		}
+
+		spans = append(spans, span{
+			start:      match[0],
+			end:        match[1],
+			entityType: models.HashtagEntity,
+			target:     strings.ToLower(text[match[2]:match[3]]),
+		})
+	}
+
+	return spans
+}
+
+// isWordByteBefore returns whether the rune before the position is a letter, a digit or an underscore.
+func isWordByteBefore(text string, position int) bool {
+	if position == 0 {
+		return false
+	}
+
+	r, _ := utf8.DecodeLastRuneInString(text[:position])
+
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// utf16Len returns the length of the text in UTF-16 code units.
+func utf16Len(text string) int {
+	length := 0
+	for _, r := range text {
+		if r >= surrogatePairStart {
+			length += 2
+		} else {
+			length++
+		}
+	}
+
+	return length
+}
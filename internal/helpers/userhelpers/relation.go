@@ -36,3 +36,21 @@ func PrepareRelationStatusToSend(userID uuid.UUID, relationStatus []models.DBRel
 
 	return preparedStatus
 }
+
+// UpdateTimelinesOnRelationAdded fills the user timeline with posts of the followed user
+// or removes posts of each other from timelines of blocked users.
+func UpdateTimelinesOnRelationAdded(relation *models.DBRelation, db *database.Queries) error {
+	switch relation.Type {
+	case models.Following:
+		return db.BackfillTimeline(relation.UserID, relation.RelationUserID)
+	case models.Blocked:
+		if err := db.RemoveFromTimeline(relation.UserID, relation.RelationUserID); err != nil {
+			return err
+		}
+		return db.RemoveFromTimeline(relation.RelationUserID, relation.UserID)
+	case models.Follower:
+		return nil
+	default:
+		return nil
+	}
+}
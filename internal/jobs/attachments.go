@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+)
+
+// PurgeMessageAttachments is the job which deletes images uploaded for messages but never sent,
+// and images of deleted messages, with their files.
+func PurgeMessageAttachments() Job {
+	return Job{
+		Name:     "purge message attachments",
+		Interval: configs.AttachmentsPurgeInterval,
+		Run: func(db *database.Queries) error {
+			attachments, err := db.DeleteUnusedMessageAttachments(time.Now().Add(-configs.AttachmentsRetention))
+			if err != nil {
+				return err
+			}
+
+			for _, attachment := range attachments {
+				err = os.Remove(filepath.Join(configs.MessageAttachmentsPath, attachment.Filename))
+				if err != nil && !os.IsNotExist(err) {
+					log.Printf("Message attachment is not removed. Reason: %v", err)
+				}
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,33 @@
+package jobs
+
+import (
+	"errors"
+	"log"
+	"os"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/notificationhelpers"
+	"github.com/MangriMen/Diverse-Back/internal/mailer"
+)
+
+// SendNotificationDigests is the job which emails due digests of unread notifications.
+// It does nothing if the mailer is not configured.
+func SendNotificationDigests() Job {
+	sender, err := mailer.Open(mailer.Backend(os.Getenv("MAILER_BACKEND")))
+	if err != nil && !errors.Is(err, mailer.ErrNotConfigured) {
+		log.Printf("Notification digests are disabled. Reason: %v", err)
+	}
+
+	return Job{
+		Name:     "send notification digests",
+		Interval: configs.DigestInterval,
+		Run: func(db *database.Queries) error {
+			if sender == nil {
+				return nil
+			}
+
+			return notificationhelpers.SendNotificationDigests(sender, db)
+		},
+	}
+}
@@ -0,0 +1,17 @@
+package jobs
+
+import (
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+)
+
+// PurgeFeedSnapshots is the job which deletes feed sessions which can't be scrolled anymore.
+func PurgeFeedSnapshots() Job {
+	return Job{
+		Name:     "purge feed snapshots",
+		Interval: configs.FeedSnapshotsPurgeInterval,
+		Run: func(db *database.Queries) error {
+			return db.DeleteExpiredFeedSnapshots()
+		},
+	}
+}
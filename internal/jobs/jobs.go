@@ -0,0 +1,65 @@
+// Package jobs provides background jobs which are run periodically alongside the web server.
+package jobs
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+)
+
+// Job is the background task run with the given interval.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(db *database.Queries) error
+}
+
+// Start runs each job periodically in its own goroutine.
+// Returns function which stops jobs and waits for running ones to finish.
+func Start(jobs ...Job) func() {
+	done := make(chan struct{})
+	wait := sync.WaitGroup{}
+
+	for _, job := range jobs {
+		wait.Add(1)
+		go func(job Job) {
+			defer wait.Done()
+			loop(job, done)
+		}(job)
+	}
+
+	return func() {
+		close(done)
+		wait.Wait()
+	}
+}
+
+// loop runs the job on each tick until done is closed. The database connection is opened
+// on the first tick and reopened on the next tick if it is not available.
+func loop(job Job, done <-chan struct{}) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	var db *database.Queries
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		if db == nil {
+			var err error
+			if db, err = database.OpenDBConnection(); err != nil {
+				log.Printf("Job %s is skipped, database is not available. Reason: %v", job.Name, err)
+				continue
+			}
+		}
+
+		if err := job.Run(db); err != nil {
+			log.Printf("Job %s is failed. Reason: %v", job.Name, err)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package jobs
+
+import (
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+)
+
+// ClosePolls is the job which closes expired polls.
+func ClosePolls() Job {
+	return Job{
+		Name:     "close polls",
+		Interval: configs.PollsCloseInterval,
+		Run: func(db *database.Queries) error {
+			return db.CloseExpiredPolls(time.Now())
+		},
+	}
+}
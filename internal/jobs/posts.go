@@ -0,0 +1,19 @@
+package jobs
+
+import (
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+)
+
+// PurgeDeletedPosts is the job which permanently deletes posts kept in the trash longer than the retention.
+func PurgeDeletedPosts() Job {
+	return Job{
+		Name:     "purge deleted posts",
+		Interval: configs.PostsPurgeInterval,
+		Run: func(db *database.Queries) error {
+			return db.PurgeDeletedPosts(time.Now().Add(-configs.PostTrashRetention))
+		},
+	}
+}
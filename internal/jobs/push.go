@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"errors"
+	"log"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/notificationhelpers"
+	"github.com/MangriMen/Diverse-Back/internal/webpush"
+)
+
+// DeliverPushNotifications is the job which sends queued push notifications.
+// It does nothing if VAPID keys are not configured.
+func DeliverPushNotifications() Job {
+	sender, err := webpush.NewSender()
+	if err != nil && !errors.Is(err, webpush.ErrNotConfigured) {
+		log.Printf("Push notifications are disabled. Reason: %v", err)
+	}
+
+	return Job{
+		Name:     "deliver push notifications",
+		Interval: configs.PushDeliveryInterval,
+		Run: func(db *database.Queries) error {
+			if sender == nil {
+				return nil
+			}
+
+			return notificationhelpers.DeliverPushNotifications(sender, db)
+		},
+	}
+}
@@ -0,0 +1,19 @@
+package jobs
+
+import (
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+)
+
+// PurgeRealtimeEvents is the job which deletes real-time events too old to resume from.
+func PurgeRealtimeEvents() Job {
+	return Job{
+		Name:     "purge realtime events",
+		Interval: configs.RealtimeEventsPurgeInterval,
+		Run: func(db *database.Queries) error {
+			return db.DeleteExpiredRealtimeEvents(time.Now().Add(-configs.RealtimeEventsRetention))
+		},
+	}
+}
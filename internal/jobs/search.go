@@ -0,0 +1,19 @@
+package jobs
+
+import (
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/search"
+)
+
+// FlushSearchIndex is the job which saves changes of the in-process search index,
+// so they aren't lost if the server stops without the graceful shutdown.
+func FlushSearchIndex() Job {
+	return Job{
+		Name:     "flush search index",
+		Interval: configs.SearchIndexFlushInterval,
+		Run: func(_ *database.Queries) error {
+			return search.FlushMemoryIndex()
+		},
+	}
+}
@@ -0,0 +1,17 @@
+package jobs
+
+import (
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+)
+
+// RollupPostViews is the job which rolls up post view events into hourly counts.
+func RollupPostViews() Job {
+	return Job{
+		Name:     "rollup post views",
+		Interval: configs.PostViewsRollupInterval,
+		Run: func(db *database.Queries) error {
+			return db.RollupPostViews()
+		},
+	}
+}
@@ -0,0 +1,52 @@
+package mailer
+
+import (
+	"fmt"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/google/uuid"
+)
+
+// FileOutbox writes emails to .eml files in the directory instead of sending them,
+// to check them locally with any email client.
+type FileOutbox struct {
+	from *mail.Address
+	dir  string
+}
+
+// NewFileOutbox returns the outbox in MAIL_OUTBOX_DIR, the outbox directory in data path if it's not set.
+func NewFileOutbox(from string) (*FileOutbox, error) {
+	address, err := parseFrom(from)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := os.Getenv("MAIL_OUTBOX_DIR")
+	if dir == "" {
+		dir = configs.MailOutboxPath
+	}
+
+	if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	return &FileOutbox{from: address, dir: dir}, nil
+}
+
+// Send writes the message to the new file named after the time it's sent.
+func (o *FileOutbox) Send(message Message) error {
+	now := time.Now()
+
+	data, err := compose(o.from, message, now)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405Z"), uuid.New())
+
+	return os.WriteFile(filepath.Join(o.dir, name), data, configs.MailOutboxFileMode)
+}
@@ -0,0 +1,157 @@
+// Package mailer provides sending emails through SMTP or to the file outbox for local testing.
+package mailer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Backend is type for mailer implementations.
+type Backend string
+
+// Enum for mailer backend.
+const (
+	SMTP Backend = "smtp"
+	File Backend = "file"
+)
+
+// ErrNotConfigured is returned if the mailer backend is not set, emails are not sent then.
+var ErrNotConfigured = errors.New("mailer is not configured")
+
+// Message is the email with alternative plain text and HTML bodies.
+type Message struct {
+	// To is the address of the recipient
+	To string
+
+	Subject string
+
+	// Text is the plain text body
+	Text string
+
+	// HTML is the HTML body, the email is plain text only if it's empty
+	HTML string
+}
+
+// Mailer sends emails.
+type Mailer interface {
+	// Send sends the message to its recipient.
+	Send(message Message) error
+}
+
+// parseFrom parses the address emails are sent from.
+func parseFrom(from string) (*mail.Address, error) {
+	if from == "" {
+		return nil, errors.New("MAIL_FROM is not set")
+	}
+
+	return mail.ParseAddress(from)
+}
+
+// Open returns the mailer of the given backend configured from the environment,
+// ErrNotConfigured is returned if the backend is empty.
+func Open(backend Backend) (Mailer, error) {
+	from := os.Getenv("MAIL_FROM")
+
+	switch backend {
+	case "":
+		return nil, ErrNotConfigured
+	case SMTP:
+		return NewSMTPMailer(from)
+	case File:
+		return NewFileOutbox(from)
+	default:
+		return nil, fmt.Errorf("unknown mailer backend %q", backend)
+	}
+}
+
+// compose renders the message from the sender in the MIME format.
+func compose(from *mail.Address, message Message, now time.Time) ([]byte, error) {
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+
+	headers := []struct{ key, value string }{
+		{"From", from.String()},
+		{"To", to.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", message.Subject)},
+		{"Date", now.Format(time.RFC1123Z)},
+		{"Message-ID", fmt.Sprintf("<%s@%s>", uuid.New(), domain(from.Address))},
+		{"MIME-Version", "1.0"},
+	}
+	for _, header := range headers {
+		fmt.Fprintf(&buffer, "%s: %s\r\n", header.key, header.value)
+	}
+
+	if message.HTML == "" {
+		fmt.Fprintf(&buffer, "Content-Type: text/plain; charset=utf-8\r\n")
+		fmt.Fprintf(&buffer, "Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+		if err = writeQuotedPrintable(&buffer, message.Text); err != nil {
+			return nil, err
+		}
+
+		return buffer.Bytes(), nil
+	}
+
+	writer := multipart.NewWriter(&buffer)
+	fmt.Fprintf(&buffer, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", writer.Boundary())
+
+	parts := []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", message.Text},
+		{"text/html; charset=utf-8", message.HTML},
+	}
+	for _, part := range parts {
+		partWriter, partErr := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if partErr != nil {
+			return nil, partErr
+		}
+
+		if err = writeQuotedPrintable(partWriter, part.body); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// writeQuotedPrintable writes the body in the quoted-printable encoding.
+func writeQuotedPrintable(w io.Writer, body string) error {
+	writer := quotedprintable.NewWriter(w)
+	if _, err := writer.Write([]byte(body)); err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+// domain returns the domain part of the email address.
+func domain(address string) string {
+	at := strings.LastIndexByte(address, '@')
+	if at < 0 {
+		return "localhost"
+	}
+
+	return address[at+1:]
+}
@@ -0,0 +1,112 @@
+package mailer
+
+import (
+	"crypto/tls"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/helpers"
+)
+
+// SMTPMailer sends emails through the SMTP server, using STARTTLS if the server supports it.
+type SMTPMailer struct {
+	from *mail.Address
+	host string
+	addr string
+	auth smtp.Auth
+}
+
+// NewSMTPMailer returns the mailer for the server from SMTP_HOST and SMTP_PORT,
+// authenticated with SMTP_USERNAME and SMTP_PASSWORD if they are set.
+func NewSMTPMailer(from string) (*SMTPMailer, error) {
+	address, err := parseFrom(from)
+	if err != nil {
+		return nil, err
+	}
+
+	host := os.Getenv("SMTP_HOST")
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = configs.SMTPDefaultPort
+	}
+
+	mailer := &SMTPMailer{
+		from: address,
+		host: host,
+		addr: net.JoinHostPort(host, port),
+	}
+
+	if username := os.Getenv("SMTP_USERNAME"); username != "" {
+		mailer.auth = smtp.PlainAuth("", username, os.Getenv("SMTP_PASSWORD"), host)
+	}
+
+	return mailer, nil
+}
+
+// Send sends the message through the SMTP server.
+func (m *SMTPMailer) Send(message Message) error {
+	data, err := compose(m.from, message, time.Now())
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.DialTimeout("tcp", m.addr, configs.MailSendTimeout)
+	if err != nil {
+		return err
+	}
+	defer helpers.CloseQuietly(conn)
+
+	if err = conn.SetDeadline(time.Now().Add(configs.MailSendTimeout)); err != nil {
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		return err
+	}
+	defer helpers.CloseQuietly(client)
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: m.host, MinVersion: tls.VersionTLS12}); err != nil {
+			return err
+		}
+	}
+
+	if m.auth != nil {
+		if err = client.Auth(m.auth); err != nil {
+			return err
+		}
+	}
+
+	if err = client.Mail(m.from.Address); err != nil {
+		return err
+	}
+
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return err
+	}
+
+	if err = client.Rcpt(to.Address); err != nil {
+		return err
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err = writer.Write(data); err != nil {
+		return err
+	}
+
+	if err = writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
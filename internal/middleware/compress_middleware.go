@@ -1,11 +1,32 @@
 package middleware
 
 import (
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/compress"
+	"github.com/valyala/fasthttp"
 )
 
-// Compress sets middleware to compress static routes.
+// Compress sets middleware to compress responses by their content type.
+// Event streams are skipped, because compression buffers events until the stream ends.
 func Compress(a *fiber.App) {
-	a.Use(compress.New(compress.ConfigDefault))
+	compressor := fasthttp.CompressHandlerBrotliLevel(
+		func(_ *fasthttp.RequestCtx) {},
+		fasthttp.CompressBrotliDefaultCompression,
+		fasthttp.CompressDefaultCompression,
+	)
+
+	a.Use(func(c *fiber.Ctx) error {
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		if strings.HasPrefix(string(c.Response().Header.ContentType()), "text/event-stream") {
+			return nil
+		}
+
+		compressor(c.Context())
+
+		return nil
+	})
 }
@@ -20,6 +20,20 @@ func JWTProtected() func(*fiber.Ctx) error {
 	return jwtware.New(config)
 }
 
+// JWTProtectedStream func for specify WebSocket and event stream routes with JWT authentication.
+// Browsers can't set headers of WebSocket and EventSource requests,
+// so the token is also looked up in the "token" query.
+func JWTProtectedStream() func(*fiber.Ctx) error {
+	config := jwtware.Config{
+		SigningKey:   []byte(os.Getenv("JWT_SECRET_KEY")),
+		ContextKey:   "jwt",
+		TokenLookup:  "header:Authorization,query:token",
+		ErrorHandler: jwtError,
+	}
+
+	return jwtware.New(config)
+}
+
 func jwtError(c *fiber.Ctx, err error) error {
 	// Return status 400 and missing or malformed token error.
 	if err.Error() == "Missing or malformed JWT" {
@@ -25,8 +25,30 @@ type BaseComment struct {
 	// required: true
 	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
 
-	// Number of likes
+	// Total number of reactions
 	Likes int `db:"likes" json:"likes"`
+
+	// Number of reactions of each kind
+	Reactions ReactionCounts `db:"reactions" json:"reactions"`
+
+	// Text search configuration of the comment content
+	// required: true
+	Language string `db:"language" json:"language" validate:"required"`
+
+	// The id of the comment this comment replies to, null for top-level comments
+	ParentCommentID *uuid.UUID `db:"parent_comment_id" json:"parent_comment_id"`
+
+	// Number of direct replies
+	ReplyCount int `db:"reply_count" json:"reply_count"`
+
+	// Whether the comment is deleted and kept only to hold its replies
+	Tombstoned bool `db:"tombstoned" json:"tombstoned"`
+
+	// Whether the comment is hidden by the post author
+	Hidden bool `db:"hidden" json:"hidden"`
+
+	// Whether the content is formatted with markdown
+	Markdown bool `db:"markdown" json:"markdown"`
 }
 
 // DBComment represents a comment struct from database.
@@ -40,6 +62,17 @@ type DBComment struct {
 	// Id of the user who wrote the comment
 	// required: true
 	UserID uuid.UUID `db:"user_id" json:"user_id" validate:"required,uuid"`
+
+	// Links, mentions and hashtags found in the content when it was written
+	Entities Entities `db:"entities" json:"entities"`
+}
+
+// DBRankedComment represents a comment with the score of the top order from database.
+type DBRankedComment struct {
+	DBComment
+
+	// Score of the comment, zero for orders other than top
+	Score float64 `db:"score" json:"score"`
 }
 
 // ToComment converts the DBComment to Comment model.
@@ -54,10 +87,26 @@ type Comment struct {
 
 	User *User `json:"user"`
 
-	LikedByMe bool `json:"liked_by_me"`
+	// Reaction of the requester, null if the requester has not reacted
+	MyReaction *string `json:"my_reaction"`
+
+	// Whether the comment is pinned to the top of the post comments
+	Pinned bool `json:"pinned"`
+
+	// Links, mentions and hashtags found in the content
+	Entities []Entity `json:"entities"`
+
+	// Content rendered to safe HTML
+	ContentHTML string `json:"content_html"`
+
+	// Muted word the comment is collapsed by, null if the comment is not filtered
+	FilteredBy *string `json:"filtered_by"`
+
+	// First replies to the comment, only for comments embedded in posts
+	Replies []Comment `json:"replies,omitempty"`
 }
 
-// DBCommentLike represents a comment like struct from database.
+// DBCommentLike represents a comment reaction struct from database.
 type DBCommentLike struct {
 	// The id for this like
 	// required: true
@@ -70,4 +119,12 @@ type DBCommentLike struct {
 	// Id of the user who wrote the comment
 	// required: true
 	UserID uuid.UUID `db:"user_id" json:"user_id" validate:"required,uuid"`
+
+	// Kind of the reaction
+	// required: true
+	Reaction string `db:"reaction" json:"reaction" validate:"required"`
+
+	// The time the reaction was set
+	// required: true
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
 }
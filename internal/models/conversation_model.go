@@ -0,0 +1,227 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ConversationType is type for kinds of conversations.
+type ConversationType string
+
+// Enum for conversation type.
+const (
+	DirectConversation ConversationType = "direct"
+	GroupConversation  ConversationType = "group"
+)
+
+// ConversationStatus is type for whether the member takes part in the conversation.
+type ConversationStatus string
+
+// Enum for conversation status.
+const (
+	AcceptedConversation  ConversationStatus = "accepted"
+	RequestedConversation ConversationStatus = "request"
+	DeclinedConversation  ConversationStatus = "declined"
+)
+
+// ConversationRole is type for permissions of members of group conversations.
+type ConversationRole string
+
+// Enum for conversation role.
+const (
+	OwnerRole  ConversationRole = "owner"
+	AdminRole  ConversationRole = "admin"
+	MemberRole ConversationRole = "member"
+)
+
+// CanManageMembers reports whether members with the role can rename the group and add or remove members.
+func (r ConversationRole) CanManageMembers() bool {
+	return r == OwnerRole || r == AdminRole
+}
+
+// DBConversation represents a conversation struct from database.
+type DBConversation struct {
+	// The id for this conversation
+	// required: true
+	ID uuid.UUID `db:"id" json:"id" validate:"required,uuid"`
+
+	// Kind of the conversation
+	// required: true
+	Type ConversationType `db:"type" json:"type" validate:"required,oneof=direct group"`
+
+	// Ids of both users of the direct conversation in ascending order, null for other kinds
+	DirectKey *string `db:"direct_key" json:"direct_key"`
+
+	// Name of the group conversation, null for direct conversations
+	Name *string `db:"name" json:"name" validate:"omitempty,min=1,max=64"`
+
+	// The time the conversation was started
+	// required: true
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+
+	// The time of the last message, the time the conversation was started if there are no messages
+	// required: true
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// DBMemberConversation represents a conversation as seen by one of its members from database.
+type DBMemberConversation struct {
+	DBConversation
+
+	// Whether the member takes part in the conversation
+	// required: true
+	Status ConversationStatus `db:"status" json:"status"`
+
+	// The time of the last message read by the member
+	LastReadAt *time.Time `db:"last_read_at" json:"last_read_at"`
+
+	// Permissions of the member in the group conversation
+	// required: true
+	Role ConversationRole `db:"role" json:"role"`
+
+	// Whether the member muted the conversation
+	// required: true
+	Muted bool `db:"muted" json:"muted"`
+
+	// The time the member joined the conversation, earlier messages are not visible to the member
+	// required: true
+	JoinedAt time.Time `db:"joined_at" json:"joined_at"`
+
+	// Number of messages of other members the member hasn't read
+	UnreadCount int `db:"unread_count" json:"unread_count"`
+}
+
+// DBConversationMember represents a member of the conversation from database.
+type DBConversationMember struct {
+	// The id of the conversation
+	// required: true
+	ConversationID uuid.UUID `db:"conversation_id" json:"conversation_id" validate:"required,uuid"`
+
+	// The id of the member
+	// required: true
+	UserID uuid.UUID `db:"user_id" json:"user_id" validate:"required,uuid"`
+
+	// Whether the member takes part in the conversation
+	// required: true
+	Status ConversationStatus `db:"status" json:"status" validate:"required,oneof=accepted request declined"`
+
+	// The time of the last message read by the member
+	LastReadAt *time.Time `db:"last_read_at" json:"last_read_at"`
+
+	// The time the user joined the conversation
+	// required: true
+	JoinedAt time.Time `db:"joined_at" json:"joined_at"`
+
+	// Permissions of the member in the group conversation
+	// required: true
+	Role ConversationRole `db:"role" json:"role" validate:"required,oneof=owner admin member"`
+
+	// Whether the member muted the conversation
+	// required: true
+	Muted bool `db:"muted" json:"muted"`
+}
+
+// ToConversationMember converts the DBConversationMember to ConversationMember model.
+func (m *DBConversationMember) ToConversationMember() ConversationMember {
+	member := ConversationMember{
+		Role:     m.Role,
+		JoinedAt: m.JoinedAt,
+	}
+
+	// Members don't let others know they read message requests until they accept them
+	if m.Status == AcceptedConversation {
+		member.LastReadAt = m.LastReadAt
+	}
+
+	return member
+}
+
+// ConversationMember represents the member of the conversation
+// swagger:model
+type ConversationMember struct {
+	// required: true
+	User User `json:"user"`
+
+	// Permissions of the member in the group conversation
+	// required: true
+	Role ConversationRole `json:"role"`
+
+	// The time the user joined the conversation
+	// required: true
+	JoinedAt time.Time `json:"joined_at"`
+
+	// The time of the last message read by the member, null if none or the conversation is a request
+	LastReadAt *time.Time `json:"last_read_at"`
+}
+
+// DirectConversationKey returns the direct key of the conversation between the users.
+func DirectConversationKey(userID uuid.UUID, otherUserID uuid.UUID) string {
+	first, second := userID.String(), otherUserID.String()
+	if first > second {
+		first, second = second, first
+	}
+
+	return first + ":" + second
+}
+
+// ToConversation converts the DBMemberConversation to Conversation model.
+func (c *DBMemberConversation) ToConversation() Conversation {
+	return Conversation{
+		ID:          c.ID,
+		Type:        c.Type,
+		Name:        c.Name,
+		Status:      c.Status,
+		Role:        c.Role,
+		Muted:       c.Muted,
+		UnreadCount: c.UnreadCount,
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
+	}
+}
+
+// Conversation represents the conversation of the requester
+// swagger:model
+type Conversation struct {
+	// The id for this conversation
+	// required: true
+	ID uuid.UUID `json:"id"`
+
+	// Kind of the conversation
+	// required: true
+	Type ConversationType `json:"type"`
+
+	// Name of the group conversation, null for direct conversations
+	Name *string `json:"name"`
+
+	// Other members of the conversation
+	// required: true
+	Members []User `json:"members"`
+
+	// Whether the requester takes part in the conversation or it's a message request
+	// required: true
+	Status ConversationStatus `json:"status"`
+
+	// Permissions of the requester in the group conversation
+	// required: true
+	Role ConversationRole `json:"role"`
+
+	// Whether the requester muted the conversation, muted conversations are not counted as unread
+	// required: true
+	Muted bool `json:"muted"`
+
+	// Number of messages the requester hasn't read
+	// required: true
+	UnreadCount int `json:"unread_count"`
+
+	// The last message, null if there are no messages
+	LastMessage *Message `json:"last_message"`
+
+	// The time the conversation was started
+	// required: true
+	CreatedAt time.Time `json:"created_at"`
+
+	// The time of the last message
+	// required: true
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// EntityType is type for entities found in text.
+type EntityType string
+
+// Enum for entity type.
+const (
+	URLEntity     EntityType = "url"
+	MentionEntity EntityType = "mention"
+	HashtagEntity EntityType = "hashtag"
+)
+
+// Entity represents a link, mention or hashtag found in the post description or the comment content
+// swagger:model
+type Entity struct {
+	// Kind of the entity
+	// required: true
+	Type EntityType `json:"type"`
+
+	// Offset of the entity in UTF-16 code units
+	// required: true
+	Offset int `json:"offset"`
+
+	// Length of the entity in UTF-16 code units
+	// required: true
+	Length int `json:"length"`
+
+	// Absolute url for links, user id for mentions and lowercase tag without # for hashtags
+	// required: true
+	Target string `json:"target"`
+}
+
+// Entities represents entities of the text, stored in database as jsonb array.
+// Entities are null for texts written before they were stored.
+type Entities []Entity
+
+// Scan implements sql.Scanner interface to read entities from jsonb.
+func (e *Entities) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case []byte:
+		return json.Unmarshal(data, e)
+	case string:
+		return json.Unmarshal([]byte(data), e)
+	case nil:
+		*e = nil
+		return nil
+	default:
+		return fmt.Errorf("unsupported entities type %T", value)
+	}
+}
+
+// Value implements driver.Valuer interface to write entities to jsonb.
+func (e Entities) Value() (driver.Value, error) {
+	if e == nil {
+		return "[]", nil
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// PostInsightsHour represents activity on the post in the one hour.
+type PostInsightsHour struct {
+	// Start of the hour
+	// required: true
+	Hour time.Time `db:"hour" json:"hour"`
+
+	// Number of views in the hour
+	// required: true
+	Views int `db:"views" json:"views"`
+
+	// Number of users who viewed the post in the hour
+	// required: true
+	UniqueViewers int `db:"unique_viewers" json:"unique_viewers"`
+
+	// Number of reactions set in the hour
+	// required: true
+	Likes int `db:"likes" json:"likes"`
+
+	// Number of comments written in the hour
+	// required: true
+	Comments int `db:"comments" json:"comments"`
+}
+
+// PostInsights represents the post statistics for its author
+// swagger:model
+type PostInsights struct {
+	// Total number of views
+	// required: true
+	Views int `db:"views" json:"views"`
+
+	// Total number of users who viewed the post
+	// required: true
+	UniqueViewers int `db:"unique_viewers" json:"unique_viewers"`
+
+	// Number of users who viewed the post in the requested period
+	// required: true
+	PeriodUniqueViewers int `db:"period_unique_viewers" json:"period_unique_viewers"`
+
+	// Total number of reactions
+	// required: true
+	Likes int `db:"likes" json:"likes"`
+
+	// Total number of comments
+	// required: true
+	Comments int `db:"comments" json:"comments"`
+
+	// Activity by hours of the requested period, hours without activity are skipped
+	// required: true
+	Hours []PostInsightsHour `db:"-" json:"hours"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DBMessageAttachment represents an image attached to the message from database.
+type DBMessageAttachment struct {
+	// The id for this attachment
+	// required: true
+	ID uuid.UUID `db:"id" json:"id" validate:"required,uuid"`
+
+	// The id of the user who uploaded the attachment
+	// required: true
+	UserID uuid.UUID `db:"user_id" json:"user_id" validate:"required,uuid"`
+
+	// The id of the message, null until the attachment is sent
+	MessageID *uuid.UUID `db:"message_id" json:"message_id"`
+
+	// Name of the file in the attachments directory
+	// required: true
+	Filename string `db:"filename" json:"filename" validate:"required"`
+
+	// The time the attachment was uploaded
+	// required: true
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// ToMessageAttachment converts the DBMessageAttachment to MessageAttachment model.
+func (a *DBMessageAttachment) ToMessageAttachment() MessageAttachment {
+	return MessageAttachment{
+		ID:   a.ID,
+		Path: MessageAttachmentPath(a.ID),
+	}
+}
+
+// MessageAttachmentPath returns the relative path to get the attachment by members of its conversation.
+func MessageAttachmentPath(id uuid.UUID) string {
+	return "/conversations/attachments/" + id.String()
+}
+
+// MessageAttachment represents the image attached to the message
+// swagger:model
+type MessageAttachment struct {
+	// The id for this attachment
+	// required: true
+	ID uuid.UUID `json:"id"`
+
+	// Relative path to the image, available only to members of the conversation
+	// required: true
+	Path string `json:"path"`
+}
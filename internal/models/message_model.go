@@ -0,0 +1,97 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MessageType is type for kinds of messages.
+type MessageType string
+
+// Enum for message type.
+const (
+	TextMessage   MessageType = "text"
+	SystemMessage MessageType = "system"
+)
+
+// MessageEvent is type for changes of the conversation recorded as system messages.
+type MessageEvent string
+
+// Enum for message event.
+const (
+	GroupCreatedMessageEvent  MessageEvent = "group_created"
+	GroupRenamedMessageEvent  MessageEvent = "group_renamed"
+	MemberAddedMessageEvent   MessageEvent = "member_added"
+	MemberRemovedMessageEvent MessageEvent = "member_removed"
+	MemberLeftMessageEvent    MessageEvent = "member_left"
+)
+
+// BaseMessage represents a base message struct in a system.
+type BaseMessage struct {
+	// The id for this message
+	// required: true
+	ID uuid.UUID `db:"id" json:"id" validate:"required,uuid"`
+
+	// The id of the conversation
+	// required: true
+	ConversationID uuid.UUID `db:"conversation_id" json:"conversation_id" validate:"required,uuid"`
+
+	// The id of the user who sent the message or made the change of the system message,
+	// null if the user is deleted
+	SenderID *uuid.UUID `db:"sender_id" json:"sender_id"`
+
+	// Kind of the message
+	// required: true
+	Type MessageType `db:"type" json:"type" validate:"required,oneof=text system"`
+
+	// Message content, the new name for renaming system messages,
+	// empty for text messages with attachments only
+	// required: true
+	Content string `db:"content" json:"content" validate:"lte=4096"`
+
+	// Change of the conversation recorded by the system message, null for text messages
+	Event *MessageEvent `db:"event" json:"event"`
+
+	// The id of the member added, removed or left by the system message
+	TargetUserID *uuid.UUID `db:"target_user_id" json:"target_user_id"`
+
+	// The time the message was sent
+	// required: true
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+
+	// The time the message was edited, the time it was sent if it's not edited
+	// required: true
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// DBMessage represents a message struct from database.
+type DBMessage struct {
+	BaseMessage
+}
+
+// ToMessage converts the DBMessage to Message model.
+func (m *DBMessage) ToMessage() Message {
+	return Message{
+		BaseMessage: m.BaseMessage,
+		Edited:      m.UpdatedAt.After(m.CreatedAt),
+		Attachments: []MessageAttachment{},
+	}
+}
+
+// Message represents the message of the conversation
+// swagger:model
+type Message struct {
+	BaseMessage
+
+	// Whether the message was edited
+	// required: true
+	Edited bool `json:"edited"`
+
+	// Summary of the system message, like "alice added bob", empty for text messages
+	Text string `json:"text,omitempty"`
+
+	// Images attached to the message
+	// required: true
+	Attachments []MessageAttachment `json:"attachments"`
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
+)
+
+// MutedWordScope is type for places where the muted word is applied.
+type MutedWordScope string
+
+// Enum for muted word scope.
+const (
+	FeedScope          MutedWordScope = "feed"
+	CommentsScope      MutedWordScope = "comments"
+	NotificationsScope MutedWordScope = "notifications"
+)
+
+// MutedWordAction is type for the way muted content is shown.
+type MutedWordAction string
+
+// Enum for muted word action.
+const (
+	HideMuted     MutedWordAction = "hide"
+	CollapseMuted MutedWordAction = "collapse"
+)
+
+// MutedWordScopes represents scopes of the muted word, stored in database as jsonb array.
+type MutedWordScopes []MutedWordScope
+
+// Scan implements sql.Scanner interface to read scopes from jsonb.
+func (s *MutedWordScopes) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case []byte:
+		return json.Unmarshal(data, s)
+	case string:
+		return json.Unmarshal([]byte(data), s)
+	case nil:
+		*s = MutedWordScopes{}
+		return nil
+	default:
+		return fmt.Errorf("unsupported muted word scopes type %T", value)
+	}
+}
+
+// Value implements driver.Valuer interface to write scopes to jsonb.
+func (s MutedWordScopes) Value() (driver.Value, error) {
+	if s == nil {
+		return "[]", nil
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+// Has reports whether the scopes include the given scope.
+func (s MutedWordScopes) Has(scope MutedWordScope) bool {
+	return slices.Contains(s, scope)
+}
+
+// MutedWord represents the word or hashtag muted by the user
+// swagger:model
+type MutedWord struct {
+	// The id for this muted word
+	// required: true
+	ID uuid.UUID `db:"id" json:"id" validate:"required,uuid"`
+
+	// Muted word, phrase or hashtag, matched case-insensitively
+	// required: true
+	// max length: 100
+	Phrase string `db:"phrase" json:"phrase" validate:"required,lte=100"`
+
+	// Whether the phrase is matched only as a whole word
+	WholeWord bool `db:"whole_word" json:"whole_word"`
+
+	// Places where the muted word is applied
+	// required: true
+	Scopes MutedWordScopes `db:"scopes" json:"scopes" validate:"required,min=1,dive,oneof=feed comments notifications"`
+
+	// Whether the muted content is hidden or shown collapsed
+	// required: true
+	Action MutedWordAction `db:"action" json:"action" validate:"required,oneof=hide collapse"`
+
+	// The time the word stops muting, null if the word is muted forever
+	ExpiresAt *time.Time `db:"expires_at" json:"expires_at"`
+
+	// The time the word was muted
+	// required: true
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// DBMutedWord represents a muted word struct from database.
+type DBMutedWord struct {
+	MutedWord
+
+	// The id of the user who muted the word
+	// required: true
+	UserID uuid.UUID `db:"user_id" json:"user_id" validate:"required,uuid"`
+}
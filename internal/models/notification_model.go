@@ -0,0 +1,133 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationType is type for events the user is notified about.
+type NotificationType string
+
+// Enum for notification type.
+const (
+	FollowNotification      NotificationType = "follow"
+	PostLikeNotification    NotificationType = "post_like"
+	CommentLikeNotification NotificationType = "comment_like"
+	CommentNotification     NotificationType = "comment"
+	ReplyNotification       NotificationType = "reply"
+	MentionNotification     NotificationType = "mention"
+)
+
+// NotificationTypes returns all types of notifications.
+func NotificationTypes() []NotificationType {
+	return []NotificationType{
+		FollowNotification,
+		PostLikeNotification,
+		CommentLikeNotification,
+		CommentNotification,
+		ReplyNotification,
+		MentionNotification,
+	}
+}
+
+// DBNotification represents a notification struct from database.
+type DBNotification struct {
+	// The id for this notification
+	// required: true
+	ID uuid.UUID `db:"id" json:"id" validate:"required,uuid"`
+
+	// The id of the notified user
+	// required: true
+	UserID uuid.UUID `db:"user_id" json:"user_id" validate:"required,uuid"`
+
+	// Kind of the event
+	// required: true
+	Type NotificationType `db:"type" json:"type" validate:"required"`
+
+	// The id of the post the event is about
+	PostID *uuid.UUID `db:"post_id" json:"post_id"`
+
+	// The id of the comment the event is about
+	CommentID *uuid.UUID `db:"comment_id" json:"comment_id"`
+
+	// Key of the events grouped into this notification while it is unread
+	// required: true
+	GroupKey string `db:"group_key" json:"group_key"`
+
+	// Number of users who caused the events
+	ActorsCount int `db:"actors_count" json:"actors_count"`
+
+	// The id of the user who caused the last event
+	LastActorID *uuid.UUID `db:"last_actor_id" json:"last_actor_id"`
+
+	// The time the notification was read
+	ReadAt *time.Time `db:"read_at" json:"read_at"`
+
+	// The time of the first event
+	// required: true
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+
+	// The time of the last event
+	// required: true
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// ToNotification converts the DBNotification to Notification model.
+func (n *DBNotification) ToNotification() Notification {
+	return Notification{
+		ID:          n.ID,
+		Type:        n.Type,
+		PostID:      n.PostID,
+		CommentID:   n.CommentID,
+		ActorsCount: n.ActorsCount,
+		Read:        n.ReadAt != nil,
+		CreatedAt:   n.CreatedAt,
+		UpdatedAt:   n.UpdatedAt,
+	}
+}
+
+// Notification represents the notification about events caused by other users
+// swagger:model
+type Notification struct {
+	// The id for this notification
+	// required: true
+	ID uuid.UUID `json:"id"`
+
+	// Kind of the event
+	// required: true
+	Type NotificationType `json:"type"`
+
+	// The id of the post the event is about, null for follows
+	PostID *uuid.UUID `json:"post_id"`
+
+	// The id of the comment the event is about, null for events about posts and follows
+	CommentID *uuid.UUID `json:"comment_id"`
+
+	// Last users who caused the events, newest first
+	// required: true
+	Actors []User `json:"actors"`
+
+	// Number of users who caused the events
+	// required: true
+	ActorsCount int `json:"actors_count"`
+
+	// Summary of the notification, like "alice and 12 others liked your post"
+	// required: true
+	Text string `json:"text"`
+
+	// Whether the notification is read
+	// required: true
+	Read bool `json:"read"`
+
+	// Muted word the notification is collapsed by, null if the notification is not filtered
+	FilteredBy *string `json:"filtered_by"`
+
+	// The time of the first event
+	// required: true
+	CreatedAt time.Time `json:"created_at"`
+
+	// The time of the last event
+	// required: true
+	UpdatedAt time.Time `json:"updated_at"`
+}
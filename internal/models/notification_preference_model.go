@@ -0,0 +1,163 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationChannel is type for ways notifications are delivered to the user.
+type NotificationChannel string
+
+// Enum for notification channel.
+const (
+	InAppChannel NotificationChannel = "in_app"
+	PushChannel  NotificationChannel = "push"
+	EmailChannel NotificationChannel = "email"
+)
+
+// NotificationChannels returns all channels of notifications.
+func NotificationChannels() []NotificationChannel {
+	return []NotificationChannel{InAppChannel, PushChannel, EmailChannel}
+}
+
+// DigestFrequency is type for how often the email digest is sent to the user.
+type DigestFrequency string
+
+// Enum for digest frequency.
+const (
+	NoDigest     DigestFrequency = "off"
+	DailyDigest  DigestFrequency = "daily"
+	WeeklyDigest DigestFrequency = "weekly"
+)
+
+// Period returns the time between two digests of the frequency, zero if digests are off.
+func (f DigestFrequency) Period() time.Duration {
+	switch f {
+	case DailyDigest:
+		return 24 * time.Hour
+	case WeeklyDigest:
+		return 7 * 24 * time.Hour
+	case NoDigest:
+		return 0
+	default:
+		return 0
+	}
+}
+
+// minutesInHour is used to convert quiet hours between minutes since midnight and the clock time.
+const minutesInHour = 60
+
+// NotificationPreference represents whether notifications of the type are delivered through the channel
+// swagger:model
+type NotificationPreference struct {
+	// Kind of the notifications
+	// required: true
+	Type NotificationType `db:"type" json:"type" validate:"required,oneof=follow post_like comment_like comment reply mention"`
+
+	// Way the notifications are delivered
+	// required: true
+	Channel NotificationChannel `db:"channel" json:"channel" validate:"required,oneof=in_app push email"`
+
+	// Whether the notifications are delivered
+	// required: true
+	Enabled bool `db:"enabled" json:"enabled"`
+}
+
+// QuietHours represents the daily time range push notifications are delayed during
+// swagger:model
+type QuietHours struct {
+	// Whether push notifications are delayed
+	// required: true
+	Enabled bool `json:"enabled"`
+
+	// Local time quiet hours start at, like 22:00
+	// pattern: ^([01][0-9]|2[0-3]):[0-5][0-9]$
+	Start string `json:"start" validate:"required_if=Enabled true,omitempty,datetime=15:04"`
+
+	// Local time quiet hours end at, like 07:30
+	// pattern: ^([01][0-9]|2[0-3]):[0-5][0-9]$
+	End string `json:"end" validate:"required_if=Enabled true,omitempty,datetime=15:04"`
+}
+
+// NotificationSettings represents settings of notification delivery of the user
+// swagger:model
+type NotificationSettings struct {
+	// IANA name of the timezone of the user, like Europe/Berlin
+	// required: true
+	Timezone string `json:"timezone" validate:"required"`
+
+	// required: true
+	QuietHours QuietHours `json:"quiet_hours"`
+
+	// How often the email digest of unread notifications is sent
+	// required: true
+	Digest DigestFrequency `json:"digest" validate:"required,oneof=off daily weekly"`
+}
+
+// DBNotificationSettings represents a notification settings struct from database.
+type DBNotificationSettings struct {
+	// The id of the user
+	// required: true
+	UserID uuid.UUID `db:"user_id" json:"user_id" validate:"required,uuid"`
+
+	// IANA name of the timezone of the user
+	// required: true
+	Timezone string `db:"timezone" json:"timezone" validate:"required"`
+
+	// Minutes since the local midnight quiet hours start at, null if there are no quiet hours
+	QuietHoursStart *int `db:"quiet_hours_start" json:"quiet_hours_start" validate:"omitempty,min=0,max=1439"`
+
+	// Minutes since the local midnight quiet hours end at
+	QuietHoursEnd *int `db:"quiet_hours_end" json:"quiet_hours_end" validate:"omitempty,min=0,max=1439"`
+
+	// How often the email digest is sent
+	// required: true
+	Digest DigestFrequency `db:"digest" json:"digest" validate:"required,oneof=off daily weekly"`
+
+	// The time the last email digest was sent
+	LastDigestAt *time.Time `db:"last_digest_at" json:"last_digest_at"`
+}
+
+// DefaultNotificationSettings returns settings of the user who hasn't changed them.
+func DefaultNotificationSettings(userID uuid.UUID) DBNotificationSettings {
+	return DBNotificationSettings{
+		UserID:   userID,
+		Timezone: "UTC",
+		Digest:   NoDigest,
+	}
+}
+
+// ToNotificationSettings converts the DBNotificationSettings to NotificationSettings model.
+func (s *DBNotificationSettings) ToNotificationSettings() NotificationSettings {
+	settings := NotificationSettings{
+		Timezone: s.Timezone,
+		Digest:   s.Digest,
+	}
+
+	if s.QuietHoursStart != nil && s.QuietHoursEnd != nil {
+		settings.QuietHours = QuietHours{
+			Enabled: true,
+			Start:   formatMinutes(*s.QuietHoursStart),
+			End:     formatMinutes(*s.QuietHoursEnd),
+		}
+	}
+
+	return settings
+}
+
+// formatMinutes formats minutes since midnight as the clock time.
+func formatMinutes(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/minutesInHour, minutes%minutesInHour)
+}
+
+// ParseClockMinutes returns minutes since midnight of the clock time, like 22:30.
+func ParseClockMinutes(clock string) (int, error) {
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, err
+	}
+
+	return parsed.Hour()*minutesInHour + parsed.Minute(), nil
+}
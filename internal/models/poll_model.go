@@ -0,0 +1,114 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DBPoll represents a poll attached to the post struct from database.
+type DBPoll struct {
+	// The id for this poll
+	// required: true
+	ID uuid.UUID `db:"id" json:"id" validate:"required,uuid"`
+
+	// The id of the post the poll is attached to
+	// required: true
+	PostID uuid.UUID `db:"post_id" json:"post_id" validate:"required,uuid"`
+
+	// Whether several options can be chosen
+	Multiple bool `db:"multiple" json:"multiple"`
+
+	// Whether the options chosen by voters are not kept
+	Anonymous bool `db:"anonymous" json:"anonymous"`
+
+	// Whether the results are shown before the requester has voted and the poll is closed
+	ShowResults bool `db:"show_results" json:"show_results"`
+
+	// Number of users voted
+	VotersCount int `db:"voters_count" json:"voters_count"`
+
+	// The time the poll expires, null if the poll doesn't expire
+	ExpiresAt *time.Time `db:"expires_at" json:"expires_at"`
+
+	// The time the poll was closed
+	ClosedAt *time.Time `db:"closed_at" json:"closed_at"`
+
+	// The time the poll was created
+	// required: true
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// IsClosed returns whether the poll is closed or expired by the given time.
+// Expired polls are closed in the background, so the expiration is checked too.
+func (p *DBPoll) IsClosed(now time.Time) bool {
+	return p.ClosedAt != nil || (p.ExpiresAt != nil && !p.ExpiresAt.After(now))
+}
+
+// DBPollOption represents an option of the poll struct from database.
+type DBPollOption struct {
+	// The id for this option
+	// required: true
+	ID uuid.UUID `db:"id" json:"id" validate:"required,uuid"`
+
+	// The id of the poll
+	// required: true
+	PollID uuid.UUID `db:"poll_id" json:"poll_id" validate:"required,uuid"`
+
+	// Position of the option in the poll
+	Position int `db:"position" json:"position"`
+
+	// Option text
+	// required: true
+	// max length: 100
+	Text string `db:"text" json:"text" validate:"required,lte=100"`
+
+	// Number of votes for the option
+	Votes int `db:"votes" json:"votes"`
+}
+
+// Poll represents the poll attached to the post
+// swagger:model
+type Poll struct {
+	// The id for this poll
+	// required: true
+	ID uuid.UUID `json:"id"`
+
+	// Whether several options can be chosen
+	Multiple bool `json:"multiple"`
+
+	// Whether the options chosen by voters are not kept
+	Anonymous bool `json:"anonymous"`
+
+	// The time the poll expires, null if the poll doesn't expire
+	ExpiresAt *time.Time `json:"expires_at"`
+
+	// Whether the poll is closed for voting
+	Closed bool `json:"closed"`
+
+	// Whether the requester has voted
+	Voted bool `json:"voted"`
+
+	// Number of users voted, null while the results are hidden
+	VotersCount *int `json:"voters_count"`
+
+	// Options in the order of the poll
+	Options []PollOption `json:"options"`
+
+	// Options chosen by the requester, empty for anonymous polls
+	MyVotes []uuid.UUID `json:"my_votes"`
+}
+
+// PollOption represents the option of the poll.
+type PollOption struct {
+	// The id for this option
+	// required: true
+	ID uuid.UUID `json:"id"`
+
+	// Option text
+	// required: true
+	Text string `json:"text"`
+
+	// Number of votes for the option, null while the results are hidden
+	Votes *int `json:"votes"`
+}
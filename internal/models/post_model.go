@@ -6,6 +6,17 @@ import (
 	"github.com/google/uuid"
 )
 
+// CommentPolicy is type for users allowed to comment the post.
+type CommentPolicy string
+
+// Enum for comment policy.
+const (
+	CommentsFromEveryone  CommentPolicy = "everyone"
+	CommentsFromFollowing CommentPolicy = "following"
+	CommentsFromMentioned CommentPolicy = "mentioned"
+	CommentsOff           CommentPolicy = "off"
+)
+
 // BasePost represents a base post struct in a system.
 type BasePost struct {
 	// The id for this post
@@ -20,12 +31,36 @@ type BasePost struct {
 	// required: true
 	Description string `db:"description" json:"description" validate:"lte=2048"`
 
-	// Number of likes
+	// Total number of reactions
 	Likes int `db:"likes" json:"likes"`
 
+	// Number of reactions of each kind
+	Reactions ReactionCounts `db:"reactions" json:"reactions"`
+
+	// Number of views
+	ViewCount int `db:"view_count" json:"view_count"`
+
+	// Warning shown instead of the post until the viewer opens it
+	// max length: 256
+	ContentWarning string `db:"content_warning" json:"content_warning" validate:"lte=256"`
+
+	// Whether the post contains sensitive media
+	Sensitive bool `db:"sensitive" json:"sensitive"`
+
+	// Users allowed to comment the post
+	// required: true
+	CommentPolicy CommentPolicy `db:"comment_policy" json:"comment_policy" validate:"required"`
+
+	// Whether the description is formatted with markdown
+	Markdown bool `db:"markdown" json:"markdown"`
+
 	// The time the post was created
 	// required: true
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
+
+	// Text search configuration of the post description
+	// required: true
+	Language string `db:"language" json:"language" validate:"required"`
 }
 
 // DBPost represents a post struct from database.
@@ -35,6 +70,33 @@ type DBPost struct {
 	// The id of the user who created the post
 	// required: true
 	UserID uuid.UUID `db:"user_id" json:"user_id" validate:"required,uuid"`
+
+	// Links, mentions and hashtags found in the description when it was written
+	Entities Entities `db:"entities" json:"entities"`
+}
+
+// DBArchivedPost represents an archived post struct from database.
+type DBArchivedPost struct {
+	DBPost
+
+	// The time the post was archived
+	ArchivedAt time.Time `db:"archived_at" json:"archived_at"`
+}
+
+// DBDeletedPost represents a deleted post in the trash struct from database.
+type DBDeletedPost struct {
+	DBPost
+
+	// The time the post was deleted
+	DeletedAt time.Time `db:"deleted_at" json:"deleted_at"`
+}
+
+// DBFeedPost represents a post of the ranked feed session from database.
+type DBFeedPost struct {
+	DBPost
+
+	// Position of the post in the feed session
+	Position int `db:"position" json:"position"`
 }
 
 // ToPost converts the DBPost to Post model.
@@ -51,10 +113,38 @@ type Post struct {
 
 	Comments []Comment `json:"comments"`
 
-	LikedByMe bool `json:"liked_by_me"`
+	// Reaction of the requester, null if the requester has not reacted
+	MyReaction *string `json:"my_reaction"`
+
+	// Whether the post is pinned to the top of the author profile
+	Pinned bool `json:"pinned"`
+
+	// Whether the post is sensitive and should be blurred for the requester
+	Blurred bool `json:"blurred"`
+
+	// Whether the requester is allowed to comment the post
+	CanComment bool `json:"can_comment"`
+
+	// Poll attached to the post, null if the post has no poll
+	Poll *Poll `json:"poll"`
+
+	// Links, mentions and hashtags found in the description
+	Entities []Entity `json:"entities"`
+
+	// Description rendered to safe HTML
+	DescriptionHTML string `json:"description_html"`
+
+	// Muted word the post is collapsed by, null if the post is not filtered
+	FilteredBy *string `json:"filtered_by"`
+
+	// The time the post was archived, only for archived posts
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+
+	// The time the post was deleted, only for posts in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
-// DBPostLike represents a post like struct from database.
+// DBPostLike represents a post reaction struct from database.
 type DBPostLike struct {
 	// The id for this like
 	// required: true
@@ -67,4 +157,12 @@ type DBPostLike struct {
 	// Id of the user who wrote the comment
 	// required: true
 	UserID uuid.UUID `db:"user_id" json:"user_id" validate:"required,uuid"`
+
+	// Kind of the reaction
+	// required: true
+	Reaction string `db:"reaction" json:"reaction" validate:"required"`
+
+	// The time the reaction was set
+	// required: true
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
 }
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PushSubscription represents the push subscription of the browser of the user
+// swagger:model
+type PushSubscription struct {
+	// The id for this push subscription
+	// required: true
+	ID uuid.UUID `db:"id" json:"id" validate:"required,uuid"`
+
+	// URL of the push service the notifications are sent to
+	// required: true
+	Endpoint string `db:"endpoint" json:"endpoint" validate:"required"`
+
+	// The time the subscription was registered
+	// required: true
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// DBPushSubscription represents a push subscription struct from database.
+type DBPushSubscription struct {
+	PushSubscription
+
+	// The id of the subscribed user
+	// required: true
+	UserID uuid.UUID `db:"user_id" json:"user_id" validate:"required,uuid"`
+
+	// Public ECDH key of the browser, base64url-encoded
+	// required: true
+	P256dh string `db:"p256dh" json:"p256dh" validate:"required"`
+
+	// Authentication secret of the browser, base64url-encoded
+	// required: true
+	Auth string `db:"auth" json:"auth" validate:"required"`
+}
+
+// DBPushDelivery represents a queued delivery of the notification to the push subscription.
+type DBPushDelivery struct {
+	// The id for this delivery
+	// required: true
+	ID uuid.UUID `db:"id" json:"id" validate:"required,uuid"`
+
+	// The id of the push subscription the notification is delivered to
+	// required: true
+	SubscriptionID uuid.UUID `db:"subscription_id" json:"subscription_id" validate:"required,uuid"`
+
+	// The id of the delivered notification
+	// required: true
+	NotificationID uuid.UUID `db:"notification_id" json:"notification_id" validate:"required,uuid"`
+
+	// Number of the delivery attempts made
+	Attempts int `db:"attempts" json:"attempts"`
+
+	// The time of the next delivery attempt
+	// required: true
+	NextAttemptAt time.Time `db:"next_attempt_at" json:"next_attempt_at"`
+
+	// The time the delivery was queued
+	// required: true
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
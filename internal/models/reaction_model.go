@@ -0,0 +1,69 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ReactionCounts represents numbers of reactions of each kind,
+// stored in database as jsonb object.
+type ReactionCounts map[string]int
+
+// Scan implements sql.Scanner interface to read reaction counts from jsonb.
+func (r *ReactionCounts) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case []byte:
+		return json.Unmarshal(data, r)
+	case string:
+		return json.Unmarshal([]byte(data), r)
+	case nil:
+		*r = ReactionCounts{}
+		return nil
+	default:
+		return fmt.Errorf("unsupported reaction counts type %T", value)
+	}
+}
+
+// DBLiker represents a user who reacted to a post or comment from database.
+type DBLiker struct {
+	DBUser
+
+	// Kind of the reaction
+	Reaction string `db:"reaction"`
+
+	// The time the reaction was set
+	LikedAt time.Time `db:"liked_at"`
+
+	// Whether the requester follows the user
+	Followed bool `db:"followed"`
+}
+
+// ToLiker converts the DBLiker to Liker model.
+func (l *DBLiker) ToLiker() Liker {
+	return Liker{
+		User:     l.ToUser(),
+		Reaction: l.Reaction,
+		LikedAt:  l.LikedAt,
+		Followed: l.Followed,
+	}
+}
+
+// Liker represents the user who reacted to a post or comment
+// swagger:model
+type Liker struct {
+	// required: true
+	User User `json:"user"`
+
+	// Kind of the reaction
+	// required: true
+	Reaction string `json:"reaction"`
+
+	// The time the reaction was set
+	// required: true
+	LikedAt time.Time `json:"liked_at"`
+
+	// Whether the requester follows the user
+	// required: true
+	Followed bool `json:"followed"`
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// RealtimeEventData represents the payload of the real-time event, stored in database as jsonb.
+type RealtimeEventData json.RawMessage
+
+// Scan implements sql.Scanner interface to read the payload from jsonb.
+func (d *RealtimeEventData) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case []byte:
+		*d = append(RealtimeEventData{}, data...)
+		return nil
+	case string:
+		*d = RealtimeEventData(data)
+		return nil
+	case nil:
+		*d = nil
+		return nil
+	default:
+		return fmt.Errorf("unsupported realtime event data type %T", value)
+	}
+}
+
+// MarshalJSON implements json.Marshaler interface to write the payload as is.
+func (d RealtimeEventData) MarshalJSON() ([]byte, error) {
+	if len(d) == 0 {
+		return []byte("null"), nil
+	}
+
+	return d, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface to keep the payload as is.
+func (d *RealtimeEventData) UnmarshalJSON(data []byte) error {
+	*d = append(RealtimeEventData{}, data...)
+	return nil
+}
+
+// RealtimeEvent represents the event pushed to subscribers of the topic
+// swagger:model
+type RealtimeEvent struct {
+	// Id of the event increasing in the order of commit, used to resume the subscription,
+	// 0 for ephemeral events like typing indicators which are not kept
+	// required: true
+	ID int64 `db:"id" json:"id"`
+
+	// Topic the event is published to
+	// required: true
+	Topic string `db:"topic" json:"topic"`
+
+	// Kind of the event
+	// required: true
+	Type string `db:"type" json:"type"`
+
+	// Payload of the event
+	Data RealtimeEventData `db:"data" json:"data"`
+
+	// The time the event was published
+	// required: true
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// Ephemeral reports whether the event is not kept, so it can't be resumed from.
+func (e *RealtimeEvent) Ephemeral() bool {
+	return e.ID == 0
+}
@@ -0,0 +1,71 @@
+package models
+
+import "github.com/google/uuid"
+
+// DBPostSearchResult represents a post found by full-text search from database.
+type DBPostSearchResult struct {
+	DBPost
+
+	// Relevance of the post to the search query
+	Rank float32 `db:"rank" json:"rank"`
+
+	// Description fragments with highlighted matches
+	Headline string `db:"headline" json:"headline"`
+}
+
+// PostSearchResult represents the post found by full-text search
+// swagger:model
+type PostSearchResult struct {
+	// required: true
+	Post Post `json:"post"`
+
+	// Relevance of the post to the search query
+	// required: true
+	Rank float32 `json:"rank"`
+
+	// Escaped HTML of description fragments with matches in <mark> tags
+	// required: true
+	Headline string `json:"headline"`
+}
+
+// DBCommentSearchResult represents a comment found by full-text search from database.
+type DBCommentSearchResult struct {
+	DBComment
+
+	// Relevance of the comment to the search query
+	Rank float32 `db:"rank" json:"rank"`
+
+	// Content fragments with highlighted matches
+	Headline string `db:"headline" json:"headline"`
+}
+
+// CommentSearchResult represents the comment found by full-text search
+// swagger:model
+type CommentSearchResult struct {
+	// required: true
+	Comment Comment `json:"comment"`
+
+	// Parent post id
+	// required: true
+	PostID uuid.UUID `json:"post_id"`
+
+	// Relevance of the comment to the search query
+	// required: true
+	Rank float32 `json:"rank"`
+
+	// Escaped HTML of content fragments with matches in <mark> tags
+	// required: true
+	Headline string `json:"headline"`
+}
+
+// Hashtag represents the hashtag used in posts
+// swagger:model
+type Hashtag struct {
+	// Hashtag without leading #
+	// required: true
+	Tag string `db:"tag" json:"tag"`
+
+	// Number of posts with the hashtag
+	// required: true
+	Posts int `db:"posts" json:"posts"`
+}
@@ -6,6 +6,25 @@ import (
 	"github.com/google/uuid"
 )
 
+// UserRole is type for permissions of the user.
+type UserRole string
+
+// Enum for user role.
+const (
+	RegularUser UserRole = "user"
+	Moderator   UserRole = "moderator"
+)
+
+// SensitiveContentPreference is type for the way sensitive posts are shown to the user.
+type SensitiveContentPreference string
+
+// Enum for sensitive content preference.
+const (
+	ShowSensitive SensitiveContentPreference = "show"
+	BlurSensitive SensitiveContentPreference = "blur"
+	HideSensitive SensitiveContentPreference = "hide"
+)
+
 // BaseUser represents a base user struct in a system.
 type BaseUser struct {
 	// The id for this user
@@ -37,6 +56,10 @@ type BaseUser struct {
 	// min length: 0
 	// max length: 2048
 	About *string `db:"about" json:"about"`
+
+	// Permissions of the user
+	// required: true
+	Role UserRole `db:"role" json:"role"`
 }
 
 // DBUser represents a user struct from database.
@@ -46,6 +69,10 @@ type DBUser struct {
 	// The password for this user
 	// required: true
 	Password string `db:"password" json:"password,omitempty" validate:"required,gte=8,lte=256"`
+
+	// The way sensitive posts of other users are shown
+	// required: true
+	SensitiveContent SensitiveContentPreference `db:"sensitive_content" json:"sensitive_content" validate:"required"`
 }
 
 // ToUser converts the DBUser to User model.
@@ -53,8 +80,17 @@ func (u *DBUser) ToUser() User {
 	return User{BaseUser: u.BaseUser}
 }
 
+// ToOwnUser converts the DBUser to User model with private settings,
+// it is used only to send the user to himself.
+func (u *DBUser) ToOwnUser() User {
+	return User{BaseUser: u.BaseUser, SensitiveContent: &u.SensitiveContent}
+}
+
 // User represents the user for this application
 // swagger:model
 type User struct {
 	BaseUser
+
+	// The way sensitive posts of other users are shown, only for the requester
+	SensitiveContent *SensitiveContentPreference `json:"sensitive_content,omitempty"`
 }
@@ -7,6 +7,16 @@ import (
 	"github.com/google/uuid"
 )
 
+// CommentSort is type for order of comments.
+type CommentSort string
+
+// Enum for comment sort.
+const (
+	TopComments    CommentSort = "top"
+	NewestComments CommentSort = "newest"
+	OldestComments CommentSort = "oldest"
+)
+
 // PostCommentIDParams includes the id of the post and id of the comment.
 type PostCommentIDParams struct {
 	PostIDParams
@@ -18,11 +28,28 @@ type PostCommentIDParams struct {
 
 // PostCommentIDRequest is used to represent a request thet requires a
 // post id and comment id parameters, such as deleting comment.
-// swagger:parameters deleteComment likeComment unlikeComment
+// swagger:parameters deleteComment likeComment unlikeComment unreactToComment
 type PostCommentIDRequest struct {
 	PostCommentIDParams
 }
 
+// CommentModerationRequest is used to represent a request of the post author
+// to moderate the comment under the post, such as hiding or pinning.
+// swagger:parameters hideComment unhideComment pinComment unpinComment
+type CommentModerationRequest struct {
+	PostCommentIDParams
+}
+
+// CommentReactionRequest is used for setting the reaction to the comment.
+// swagger:parameters reactToComment
+type CommentReactionRequest struct {
+	PostCommentIDParams
+
+	// in: body
+	// required: true
+	Body ReactionRequestBody
+}
+
 // CommentAddRequestParams include the ID of the post where you want to create a comment.
 type CommentAddRequestParams struct {
 	PostIDParams
@@ -32,6 +59,12 @@ type CommentAddRequestParams struct {
 type CommentAddRequestBody struct {
 	// required: true
 	Content string `json:"content" validate:"required"`
+
+	// Text search configuration of the content
+	Language string `json:"language"`
+
+	// Whether the content is formatted with markdown
+	Markdown bool `json:"markdown"`
 }
 
 // CommentAddRequest is used for adding a new comment to post.
@@ -45,10 +78,23 @@ type CommentAddRequest struct {
 	Body CommentAddRequestBody
 }
 
+// CommentReplyRequest is used for replying to the comment of the post.
+// swagger:parameters replyToComment
+type CommentReplyRequest struct {
+	PostCommentIDParams
+
+	// in: body
+	// required: true
+	Body CommentAddRequestBody
+}
+
 // CommentUpdateRequestBody includes the content of the comment.
 type CommentUpdateRequestBody struct {
 	// required: true
 	Content string `json:"content" validate:"required"`
+
+	// Whether the content is formatted with markdown, unchanged if omitted
+	Markdown *bool `json:"markdown"`
 }
 
 // CommentUpdateRequest is used for updating a comment of the post.
@@ -62,15 +108,18 @@ type CommentUpdateRequest struct {
 	Body CommentUpdateRequestBody
 }
 
-// CommentsFetchRequestQuery includes the ID and creation time of the last seen comment,
+// CommentsFetchRequestQuery includes the order of comments and the cursor returned with the previous page,
 // as well as a count of the number of comments to retrieve.
 type CommentsFetchRequestQuery struct {
+	// Order of comments: top by likes with time decay, newest or oldest, newest by default.
+	// Top is best-effort across pages: comments may be skipped or repeated if their likes change
+	// while scrolling, only newest and oldest pages are stable
 	// in: query
-	LastSeenCommentID uuid.UUID `query:"last_seen_comment_id" json:"last_seen_comment_id" validate:"uuid"`
+	Sort CommentSort `query:"sort" json:"sort" validate:"omitempty,oneof=top newest oldest"`
 
-	//nolint:lll
+	// Opaque cursor returned with the previous page, must be used with the same sort
 	// in: query
-	LastSeenCommentCreatedAt time.Time `query:"last_seen_comment_created_at" json:"last_seen_comment_created_at" validate:"uuid,required_with=last_seen_comment_id"`
+	Cursor string `query:"cursor" json:"cursor"`
 
 	// in: query
 	// required: true
@@ -87,8 +136,60 @@ type CommentsFetchRequest struct {
 	CommentsFetchRequestQuery
 }
 
+// CommentsCursor is the position of the last fetched comment in the given order.
+// Scores of top comments are computed at the time of the first page, but from current likes,
+// so the top order is best-effort across pages.
+type CommentsCursor struct {
+	Sort CommentSort `json:"sort"`
+
+	RankedAt time.Time `json:"ranked_at"`
+
+	Score float64 `json:"score"`
+
+	CreatedAt time.Time `json:"created_at"`
+
+	CommentID uuid.UUID `json:"comment_id"`
+}
+
 // CommentsFetchCountRequest is a struct that encapsulates a query used to fetch comments count.
 // swagger:parameters getCommentsCount
 type CommentsFetchCountRequest struct {
 	PostIDParams
 }
+
+// RepliesFetchRequestQuery includes the cursor returned with the previous page of replies,
+// as well as a count of the number of replies to retrieve.
+type RepliesFetchRequestQuery struct {
+	// Opaque cursor returned with the previous page
+	// in: query
+	Cursor string `query:"cursor" json:"cursor"`
+
+	// in: query
+	// required: true
+	// min: 1
+	// max: 50
+	Count int `query:"count" json:"count" validate:"required,min=1,max=50"`
+}
+
+// RepliesFetchRequest is a struct that encapsulates a query used to fetch replies to the comment.
+// swagger:parameters getCommentReplies
+type RepliesFetchRequest struct {
+	PostCommentIDParams
+
+	RepliesFetchRequestQuery
+}
+
+// RepliesCursor is the position of the last fetched reply.
+type RepliesCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+
+	CommentID uuid.UUID `json:"comment_id"`
+}
+
+// CommentLikesFetchRequest is a struct that encapsulates a query used to fetch users who liked the comment.
+// swagger:parameters getCommentLikes
+type CommentLikesFetchRequest struct {
+	PostCommentIDParams
+
+	LikesFetchRequestQuery
+}
@@ -0,0 +1,260 @@
+package parameters
+
+import (
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/google/uuid"
+)
+
+// ConversationIDParams includes the id of the conversation.
+type ConversationIDParams struct {
+	// in: path
+	// required: true
+	Conversation uuid.UUID `params:"conversation" json:"conversation" validate:"required"`
+}
+
+// ConversationIDRequest is used to represent a request that requires a conversation id parameter,
+// such as fetching, reading, accepting or leaving the conversation.
+// swagger:parameters getConversation readConversation acceptConversation declineConversation
+// swagger:parameters getConversationMembers leaveConversation muteConversation unmuteConversation
+// swagger:parameters typingConversation
+type ConversationIDRequest struct {
+	ConversationIDParams
+}
+
+// ConversationMessageIDParams includes the id of the conversation and id of the message.
+type ConversationMessageIDParams struct {
+	ConversationIDParams
+
+	// in: path
+	// required: true
+	Message uuid.UUID `params:"message" json:"message" validate:"required"`
+}
+
+// ConversationMessageIDRequest is used to represent a request that requires
+// a conversation id and message id parameters, such as deleting message.
+// swagger:parameters deleteMessage
+type ConversationMessageIDRequest struct {
+	ConversationMessageIDParams
+}
+
+// ConversationMemberIDParams includes the id of the conversation and id of its member.
+type ConversationMemberIDParams struct {
+	ConversationIDParams
+
+	// in: path
+	// required: true
+	User uuid.UUID `params:"user" json:"user" validate:"required"`
+}
+
+// ConversationMemberIDRequest is used to represent a request that requires
+// a conversation id and member id parameters, such as removing the member.
+// swagger:parameters removeConversationMember
+type ConversationMemberIDRequest struct {
+	ConversationMemberIDParams
+}
+
+// ConversationsFetchRequestQuery includes the cursor returned with the previous page of conversations,
+// as well as a count of the number of conversations to retrieve.
+type ConversationsFetchRequestQuery struct {
+	// Opaque cursor returned with the previous page
+	// in: query
+	Cursor string `query:"cursor" json:"cursor"`
+
+	// Whether message requests are fetched instead of accepted conversations
+	// in: query
+	Requests bool `query:"requests" json:"requests"`
+
+	// in: query
+	// required: true
+	// min: 1
+	// max: 50
+	Count int `query:"count" json:"count" validate:"required,min=1,max=50"`
+}
+
+// ConversationsFetchRequest is a struct that encapsulates a query used to fetch conversations.
+// swagger:parameters getConversations
+type ConversationsFetchRequest struct {
+	ConversationsFetchRequestQuery
+}
+
+// ConversationsCursor is the position of the last fetched conversation.
+type ConversationsCursor struct {
+	UpdatedAt time.Time `json:"updated_at"`
+
+	ConversationID uuid.UUID `json:"conversation_id"`
+}
+
+// ConversationCreateRequestBody includes the user to start the direct conversation with.
+type ConversationCreateRequestBody struct {
+	// The id of the user
+	// required: true
+	User uuid.UUID `json:"user" validate:"required"`
+}
+
+// ConversationCreateRequest is used for starting the direct conversation with the user.
+// swagger:parameters createConversation
+type ConversationCreateRequest struct {
+	// in: body
+	// required: true
+	Body ConversationCreateRequestBody
+}
+
+// GroupCreateRequestBody includes the name and members of the group conversation.
+type GroupCreateRequestBody struct {
+	// required: true
+	// min length: 1
+	// max length: 64
+	Name string `json:"name" validate:"required,min=1,max=64"`
+
+	// Ids of users to add to the group, except the requester
+	// required: true
+	// min items: 1
+	// max items: 49
+	Members []uuid.UUID `json:"members" validate:"required,min=1,max=49,unique"`
+}
+
+// GroupCreateRequest is used for creating the group conversation.
+// swagger:parameters createGroup
+type GroupCreateRequest struct {
+	// in: body
+	// required: true
+	Body GroupCreateRequestBody
+}
+
+// ConversationUpdateRequestBody includes the new name of the group conversation.
+type ConversationUpdateRequestBody struct {
+	// required: true
+	// min length: 1
+	// max length: 64
+	Name string `json:"name" validate:"required,min=1,max=64"`
+}
+
+// ConversationUpdateRequest is used for renaming the group conversation.
+// swagger:parameters updateConversation
+type ConversationUpdateRequest struct {
+	ConversationIDParams
+
+	// in: body
+	// required: true
+	Body ConversationUpdateRequestBody
+}
+
+// ConversationMembersAddRequestBody includes users to add to the group conversation.
+type ConversationMembersAddRequestBody struct {
+	// Ids of users to add to the group
+	// required: true
+	// min items: 1
+	// max items: 49
+	Users []uuid.UUID `json:"users" validate:"required,min=1,max=49,unique"`
+}
+
+// ConversationMembersAddRequest is used for adding users to the group conversation.
+// swagger:parameters addConversationMembers
+type ConversationMembersAddRequest struct {
+	ConversationIDParams
+
+	// in: body
+	// required: true
+	Body ConversationMembersAddRequestBody
+}
+
+// ConversationMemberRoleRequestBody includes the new role of the member of the group conversation.
+type ConversationMemberRoleRequestBody struct {
+	// required: true
+	// enum: admin,member
+	Role models.ConversationRole `json:"role" validate:"required,oneof=admin member"`
+}
+
+// ConversationMemberRoleRequest is used for changing the role of the member of the group conversation.
+// swagger:parameters updateConversationMemberRole
+type ConversationMemberRoleRequest struct {
+	ConversationMemberIDParams
+
+	// in: body
+	// required: true
+	Body ConversationMemberRoleRequestBody
+}
+
+// MessagesFetchRequestQuery includes the cursor returned with the previous page of messages,
+// as well as a count of the number of messages to retrieve.
+type MessagesFetchRequestQuery struct {
+	// Opaque cursor returned with the previous page
+	// in: query
+	Cursor string `query:"cursor" json:"cursor"`
+
+	// in: query
+	// required: true
+	// min: 1
+	// max: 100
+	Count int `query:"count" json:"count" validate:"required,min=1,max=100"`
+}
+
+// MessagesFetchRequest is a struct that encapsulates a query used to fetch messages of the conversation.
+// swagger:parameters getMessages
+type MessagesFetchRequest struct {
+	ConversationIDParams
+
+	MessagesFetchRequestQuery
+}
+
+// MessagesCursor is the position of the last fetched message.
+type MessagesCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+
+	MessageID uuid.UUID `json:"message_id"`
+}
+
+// MessageSendRequestBody includes the content of the message and its attachments.
+type MessageSendRequestBody struct {
+	// Required unless the message has attachments
+	// max length: 4096
+	Content string `json:"content" validate:"required_without=Attachments,max=4096"`
+
+	// Ids of images uploaded for messages by the requester
+	// max items: 10
+	Attachments []uuid.UUID `json:"attachments" validate:"omitempty,max=10,unique"`
+}
+
+// MessageSendRequest is used for sending the message to the conversation.
+// swagger:parameters sendMessage
+type MessageSendRequest struct {
+	ConversationIDParams
+
+	// in: body
+	// required: true
+	Body MessageSendRequestBody
+}
+
+// MessageUpdateRequestBody includes the new content of the message.
+type MessageUpdateRequestBody struct {
+	// required: true
+	// max length: 4096
+	Content string `json:"content" validate:"required,max=4096"`
+}
+
+// MessageUpdateRequest is used for editing the message sent by the requester.
+// swagger:parameters updateMessage
+type MessageUpdateRequest struct {
+	ConversationMessageIDParams
+
+	// in: body
+	// required: true
+	Body MessageUpdateRequestBody
+}
+
+// MessageAttachmentIDParams includes the id of the message attachment.
+type MessageAttachmentIDParams struct {
+	// in: path
+	// required: true
+	Attachment uuid.UUID `params:"attachment" json:"attachment" validate:"required"`
+}
+
+// MessageAttachmentRequest is used to get the image attached to the message with optional size.
+// swagger:parameters getMessageAttachment
+type MessageAttachmentRequest struct {
+	MessageAttachmentIDParams
+
+	GetDataRequestQuery
+}
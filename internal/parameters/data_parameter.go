@@ -7,10 +7,20 @@ type UploadDataRequestForm struct {
 	File interface{} `json:"file"`
 }
 
+// UploadDataRequestQuery includes what the uploaded file is for.
+type UploadDataRequestQuery struct {
+	// Files uploaded for messages are kept private and can only be attached to messages
+	// in: query
+	// enum: message
+	Purpose string `query:"purpose" json:"purpose" validate:"omitempty,oneof=message"`
+}
+
 // UploadDataRequest is used for upload a new file.
 // swagger:parameters uploadData
 type UploadDataRequest struct {
 	UploadDataRequestForm
+
+	UploadDataRequestQuery
 }
 
 // DataTypeParams includes the type of the data.
@@ -0,0 +1,58 @@
+package parameters
+
+import (
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/google/uuid"
+)
+
+// MutedWordIDParams includes the id of the user and the id of the muted word.
+type MutedWordIDParams struct {
+	UserIDParams
+
+	// in: path
+	// required: true
+	MutedWord uuid.UUID `params:"mutedWord" json:"mutedWord" validate:"required"`
+}
+
+// MutedWordsFetchRequest is used for fetching active muted words of the user.
+// swagger:parameters getMutedWords
+type MutedWordsFetchRequest struct {
+	UserIDParams
+}
+
+// MutedWordCreateRequestBody includes the phrase and settings of the new muted word.
+type MutedWordCreateRequestBody struct {
+	// Word, phrase or hashtag to mute
+	// required: true
+	// max length: 100
+	Phrase string `json:"phrase" validate:"required,lte=100"`
+
+	// Whether the phrase is matched only as a whole word, true by default
+	WholeWord *bool `json:"whole_word"`
+
+	// Places where the word is muted, everywhere by default
+	Scopes models.MutedWordScopes `json:"scopes" validate:"omitempty,dive,oneof=feed comments notifications"`
+
+	// Whether the muted content is hidden or shown collapsed, hidden by default
+	Action models.MutedWordAction `json:"action" validate:"omitempty,oneof=hide collapse"`
+
+	// Duration of muting in seconds, the word is muted forever if not set
+	// minimum: 60
+	ExpiresIn int `json:"expires_in" validate:"omitempty,min=60"`
+}
+
+// MutedWordCreateRequest is used for muting the word.
+// swagger:parameters muteWord
+type MutedWordCreateRequest struct {
+	UserIDParams
+
+	// in: body
+	// required: true
+	Body MutedWordCreateRequestBody
+}
+
+// MutedWordDeleteRequest is used for unmuting the word.
+// swagger:parameters unmuteWord
+type MutedWordDeleteRequest struct {
+	MutedWordIDParams
+}
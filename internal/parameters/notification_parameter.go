@@ -0,0 +1,75 @@
+package parameters
+
+import (
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/google/uuid"
+)
+
+// NotificationIDParams includes the id of the notification.
+type NotificationIDParams struct {
+	// in: path
+	// required: true
+	Notification uuid.UUID `params:"notification" json:"notification" validate:"required"`
+}
+
+// NotificationIDRequest is used to represent a request that requires a notification id parameter.
+// swagger:parameters readNotification
+type NotificationIDRequest struct {
+	NotificationIDParams
+}
+
+// NotificationsFetchRequestQuery includes the cursor returned with the previous page of notifications,
+// as well as a count of the number of notifications to retrieve.
+type NotificationsFetchRequestQuery struct {
+	// Opaque cursor returned with the previous page
+	// in: query
+	Cursor string `query:"cursor" json:"cursor"`
+
+	// Whether only unread notifications are fetched
+	// in: query
+	UnreadOnly bool `query:"unread_only" json:"unread_only"`
+
+	// in: query
+	// required: true
+	// min: 1
+	// max: 50
+	Count int `query:"count" json:"count" validate:"required,min=1,max=50"`
+}
+
+// NotificationsFetchRequest is a struct that encapsulates a query used to fetch notifications.
+// swagger:parameters getNotifications
+type NotificationsFetchRequest struct {
+	NotificationsFetchRequestQuery
+}
+
+// NotificationsCursor is the position of the last fetched notification.
+type NotificationsCursor struct {
+	UpdatedAt time.Time `json:"updated_at"`
+
+	NotificationID uuid.UUID `json:"notification_id"`
+}
+
+// NotificationPreferencesUpdateRequestBody includes preferences and settings of notifications to change,
+// fields not set are kept.
+type NotificationPreferencesUpdateRequestBody struct {
+	// Preferences to change, types and channels not listed are kept
+	Preferences []models.NotificationPreference `json:"preferences" validate:"omitempty,max=50,dive"`
+
+	// IANA name of the timezone quiet hours and digests are scheduled in, like Europe/Berlin
+	Timezone *string `json:"timezone" validate:"omitempty,min=1,max=64"`
+
+	QuietHours *models.QuietHours `json:"quiet_hours"`
+
+	// How often the email digest of unread notifications is sent
+	Digest *models.DigestFrequency `json:"digest" validate:"omitempty,oneof=off daily weekly"`
+}
+
+// NotificationPreferencesUpdateRequest is used for changing notification preferences and settings.
+// swagger:parameters updateNotificationPreferences
+type NotificationPreferencesUpdateRequest struct {
+	// in: body
+	// required: true
+	Body NotificationPreferencesUpdateRequestBody
+}
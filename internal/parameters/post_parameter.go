@@ -3,6 +3,7 @@ package parameters
 import (
 	"time"
 
+	"github.com/MangriMen/Diverse-Back/internal/models"
 	"github.com/google/uuid"
 )
 
@@ -25,11 +26,33 @@ type PostIDParams struct {
 
 // PostIDRequest is used to represent a request that requires a post id parameter,
 // such as fetching a specific post or deleting a post.
-// swagger:parameters getPost updatePost deletePost likePost unlikePost
+// swagger:parameters updatePost deletePost likePost unlikePost unreactToPost pinPost unpinPost viewPost
 type PostIDRequest struct {
 	PostIDParams
 }
 
+// PostFetchRequestQuery includes the order of comments embedded into the post.
+type PostFetchRequestQuery struct {
+	// Order of embedded comments: top, newest or oldest, newest by default
+	// in: query
+	CommentsSort CommentSort `query:"comments_sort" json:"comments_sort" validate:"omitempty,oneof=top newest oldest"`
+}
+
+// PostFetchRequest is a struct that encapsulates a query used to fetch the post.
+// swagger:parameters getPost
+type PostFetchRequest struct {
+	PostIDParams
+
+	PostFetchRequestQuery
+}
+
+// PostVisibilityRequest is used to represent a request that changes visibility of the post,
+// such as archiving or restoring from the trash.
+// swagger:parameters archivePost unarchivePost restorePost
+type PostVisibilityRequest struct {
+	PostIDParams
+}
+
 // PostCreateRequestBody includes the content, description and id of the user creating the post.
 type PostCreateRequestBody struct {
 	// required: true
@@ -38,6 +61,47 @@ type PostCreateRequestBody struct {
 	// required: true
 	// max length: 2048
 	Description string `json:"description" validate:"lte=2048"`
+
+	// Text search configuration of the description
+	Language string `json:"language"`
+
+	// Warning shown instead of the post until the viewer opens it
+	// max length: 256
+	ContentWarning string `json:"content_warning" validate:"lte=256"`
+
+	// Whether the post contains sensitive media
+	Sensitive bool `json:"sensitive"`
+
+	// Users allowed to comment the post, everyone by default
+	CommentPolicy models.CommentPolicy `json:"comment_policy" validate:"omitempty,oneof=everyone following mentioned off"`
+
+	// Poll attached to the post
+	Poll *PollCreateRequestBody `json:"poll"`
+
+	// Whether the description is formatted with markdown
+	Markdown bool `json:"markdown"`
+}
+
+// PollCreateRequestBody includes the options and settings of the poll attached to the new post.
+type PollCreateRequestBody struct {
+	// required: true
+	// min items: 2
+	// max items: 10
+	Options []string `json:"options" validate:"required,min=2,max=10,dive,required,lte=100"`
+
+	// Whether several options can be chosen
+	Multiple bool `json:"multiple"`
+
+	// Whether the options chosen by voters are not kept
+	Anonymous bool `json:"anonymous"`
+
+	// Whether the results are shown before voting, otherwise they are hidden until voted or closed
+	ShowResults bool `json:"show_results"`
+
+	// Duration of the poll in seconds, the poll doesn't expire if not set
+	// minimum: 300
+	// maximum: 2592000
+	ExpiresIn int `json:"expires_in" validate:"omitempty,min=300,max=2592000"`
 }
 
 // PostCreateRequest is used for creating a new post.
@@ -49,11 +113,37 @@ type PostCreateRequest struct {
 	Body PostCreateRequestBody
 }
 
-// PostUpdateRequestBody includes the new description for the post.
+// PostSensitivityUpdateRequestBody includes the new content warning and sensitive flag of the post.
+type PostSensitivityUpdateRequestBody struct {
+	// Warning shown instead of the post until the viewer opens it, empty to remove
+	// max length: 256
+	ContentWarning string `json:"content_warning" validate:"lte=256"`
+
+	// Whether the post contains sensitive media
+	Sensitive bool `json:"sensitive"`
+}
+
+// PostSensitivityUpdateRequest is used for flagging the post as sensitive.
+// swagger:parameters updatePostSensitivity
+type PostSensitivityUpdateRequest struct {
+	PostIDParams
+
+	// in: body
+	// required: true
+	Body PostSensitivityUpdateRequestBody
+}
+
+// PostUpdateRequestBody includes the new description and comment policy for the post.
 type PostUpdateRequestBody struct {
 	// required: true
 	// max length: 2048
 	Description string `json:"description" validate:"lte=2048"`
+
+	// Users allowed to comment the post
+	CommentPolicy models.CommentPolicy `json:"comment_policy" validate:"omitempty,oneof=everyone following mentioned off"`
+
+	// Whether the description is formatted with markdown, unchanged if omitted
+	Markdown *bool `json:"markdown"`
 }
 
 // PostUpdateRequest is used for updating an existing post.
@@ -66,6 +156,36 @@ type PostUpdateRequest struct {
 	Body PostUpdateRequestBody
 }
 
+// ReactionRequestBody includes the reaction to set to the post or comment.
+type ReactionRequestBody struct {
+	// required: true
+	Reaction string `json:"reaction" validate:"required"`
+}
+
+// PostReactionRequest is used for setting the reaction to the post.
+// swagger:parameters reactToPost
+type PostReactionRequest struct {
+	PostIDParams
+
+	// in: body
+	// required: true
+	Body ReactionRequestBody
+}
+
+// PinnedPostsUpdateRequestBody includes ids of the pinned posts in the new pin order.
+type PinnedPostsUpdateRequestBody struct {
+	// required: true
+	Posts []uuid.UUID `json:"posts" validate:"required,unique"`
+}
+
+// PinnedPostsUpdateRequest is used for reordering pinned posts.
+// swagger:parameters updatePinnedPosts
+type PinnedPostsUpdateRequest struct {
+	// in: body
+	// required: true
+	Body PinnedPostsUpdateRequestBody
+}
+
 // PostsFetchCountRequestQuery includes the ID and creation time of the last seen post,
 // as well as a count of the number of posts to retrieve.
 type PostsFetchCountRequestQuery struct {
@@ -98,6 +218,14 @@ type PostsFetchRequestQuery struct {
 	// in: query
 	UserID uuid.UUID `query:"user_id" json:"user_id" validate:"uuid,required_with=type"`
 
+	// Opaque cursor returned with the previous page, used instead of last seen post by the ranked all feed
+	// in: query
+	Cursor string `query:"cursor" json:"cursor"`
+
+	// Order of embedded comments: top, newest or oldest, newest by default
+	// in: query
+	CommentsSort CommentSort `query:"comments_sort" json:"comments_sort" validate:"omitempty,oneof=top newest oldest"`
+
 	// in: query
 	// required: true
 	// min: 1
@@ -110,3 +238,120 @@ type PostsFetchRequestQuery struct {
 type PostsFetchRequest struct {
 	PostsFetchRequestQuery
 }
+
+// FeedCursor is the position of the last fetched post in the ranked feed session.
+type FeedCursor struct {
+	SnapshotID uuid.UUID `json:"snapshot_id"`
+
+	Position int `json:"position"`
+}
+
+// LikesFetchRequestQuery includes the cursor returned with the previous page of likes,
+// as well as a count of the number of likes to retrieve.
+type LikesFetchRequestQuery struct {
+	// Opaque cursor returned with the previous page
+	// in: query
+	Cursor string `query:"cursor" json:"cursor"`
+
+	// in: query
+	// required: true
+	// min: 1
+	// max: 50
+	Count int `query:"count" json:"count" validate:"required,min=1,max=50"`
+}
+
+// PostLikesFetchRequest is a struct that encapsulates a query used to fetch users who liked the post.
+// swagger:parameters getPostLikes
+type PostLikesFetchRequest struct {
+	PostIDParams
+
+	LikesFetchRequestQuery
+}
+
+// LikesCursor is the position of the last fetched like, followed users go first.
+type LikesCursor struct {
+	Followed bool `json:"followed"`
+
+	LikedAt time.Time `json:"liked_at"`
+
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// PollVoteRequestBody includes ids of the options chosen by the user.
+type PollVoteRequestBody struct {
+	// required: true
+	// min items: 1
+	// max items: 10
+	Options []uuid.UUID `json:"options" validate:"required,min=1,max=10"`
+}
+
+// PollVoteRequest is used for voting in the poll attached to the post.
+// swagger:parameters votePoll
+type PollVoteRequest struct {
+	PostIDParams
+
+	// in: body
+	// required: true
+	Body PollVoteRequestBody
+}
+
+// PostsViewRequestBody includes ids of the posts seen by the user.
+type PostsViewRequestBody struct {
+	// required: true
+	// max items: 100
+	Posts []uuid.UUID `json:"posts" validate:"required,min=1,max=100"`
+}
+
+// PostsViewRequest is used for recording views of the posts seen during the feed scroll.
+// swagger:parameters viewPosts
+type PostsViewRequest struct {
+	// in: body
+	// required: true
+	Body PostsViewRequestBody
+}
+
+// PostInsightsRequestQuery includes the period of the post insights.
+type PostInsightsRequestQuery struct {
+	// Start of the period, a week before the end by default
+	// in: query
+	From time.Time `query:"from" json:"from"`
+
+	// End of the period, now by default
+	// in: query
+	To time.Time `query:"to" json:"to"`
+}
+
+// PostInsightsRequest is a struct that encapsulates a query used to fetch the post insights.
+// swagger:parameters getPostInsights
+type PostInsightsRequest struct {
+	PostIDParams
+
+	PostInsightsRequestQuery
+}
+
+// HiddenPostsFetchRequestQuery includes the cursor returned with the previous page of archived
+// or deleted posts, as well as a count of the number of posts to retrieve.
+type HiddenPostsFetchRequestQuery struct {
+	// Opaque cursor returned with the previous page
+	// in: query
+	Cursor string `query:"cursor" json:"cursor"`
+
+	// in: query
+	// required: true
+	// min: 1
+	// max: 50
+	Count int `query:"count" json:"count" validate:"required,min=1,max=50"`
+}
+
+// HiddenPostsFetchRequest is a struct that encapsulates a query used to fetch archived or deleted posts.
+// swagger:parameters getArchivedPosts getDeletedPosts
+type HiddenPostsFetchRequest struct {
+	HiddenPostsFetchRequestQuery
+}
+
+// HiddenPostsCursor is the position of the last fetched archived or deleted post.
+type HiddenPostsCursor struct {
+	HiddenAt time.Time `json:"hidden_at"`
+
+	ID uuid.UUID `json:"id"`
+}
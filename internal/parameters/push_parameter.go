@@ -0,0 +1,46 @@
+package parameters
+
+import "github.com/google/uuid"
+
+// PushSubscriptionIDParams includes the id of the push subscription.
+type PushSubscriptionIDParams struct {
+	// in: path
+	// required: true
+	Subscription uuid.UUID `params:"subscription" json:"subscription" validate:"required"`
+}
+
+// PushSubscriptionDeleteRequest is used for unregistering the push subscription.
+// swagger:parameters deletePushSubscription
+type PushSubscriptionDeleteRequest struct {
+	PushSubscriptionIDParams
+}
+
+// PushSubscriptionKeys includes keys the browser generated for encryption of push messages.
+type PushSubscriptionKeys struct {
+	// Public ECDH key of the browser, base64url-encoded
+	// required: true
+	P256dh string `json:"p256dh" validate:"required,lte=128"`
+
+	// Authentication secret of the browser, base64url-encoded
+	// required: true
+	Auth string `json:"auth" validate:"required,lte=64"`
+}
+
+// PushSubscriptionCreateRequestBody includes the push subscription as serialized by the browser.
+type PushSubscriptionCreateRequestBody struct {
+	// URL of the push service
+	// required: true
+	// max length: 2048
+	Endpoint string `json:"endpoint" validate:"required,url,lte=2048"`
+
+	// required: true
+	Keys PushSubscriptionKeys `json:"keys" validate:"required"`
+}
+
+// PushSubscriptionCreateRequest is used for registering the push subscription of the browser.
+// swagger:parameters createPushSubscription
+type PushSubscriptionCreateRequest struct {
+	// in: body
+	// required: true
+	Body PushSubscriptionCreateRequestBody
+}
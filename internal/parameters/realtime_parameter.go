@@ -0,0 +1,37 @@
+package parameters
+
+// RealtimeConnectRequest is used for connecting to the WebSocket gateway from browsers,
+// which can't set the authorization header.
+// swagger:parameters connectRealtime
+type RealtimeConnectRequest struct {
+	// JWT of the user, used instead of the authorization header
+	// in: query
+	Token string `query:"token" json:"token"`
+}
+
+// RealtimeStreamRequestQuery includes topics of the event stream and the id of the last received event.
+type RealtimeStreamRequestQuery struct {
+	// JWT of the user, used instead of the authorization header
+	// in: query
+	Token string `query:"token" json:"token"`
+
+	// Topics to subscribe to, all topics of the user if empty
+	// in: query
+	// max items: 100
+	Topics []string `query:"topic" json:"topic" validate:"max=100,dive,required"`
+
+	// Id of the last received event, the Last-Event-ID header takes precedence
+	// in: query
+	// min: 0
+	LastEventID int64 `query:"last_event_id" json:"last_event_id" validate:"min=0"`
+}
+
+// RealtimeStreamRequest is used for opening the event stream.
+// swagger:parameters streamRealtime
+type RealtimeStreamRequest struct {
+	RealtimeStreamRequestQuery
+
+	// Id of the last received event, sent by EventSource on reconnect
+	// in: header
+	LastEventIDHeader string `json:"Last-Event-ID"`
+}
@@ -0,0 +1,85 @@
+package parameters
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SearchRequestQuery includes the search text, optional filters
+// and the cursor of the last fetched page.
+type SearchRequestQuery struct {
+	// in: query
+	// required: true
+	// max length: 256
+	Query string `query:"q" json:"q" validate:"required,lte=256"`
+
+	// Text search configuration, results in other languages are skipped
+	// in: query
+	Language string `query:"language" json:"language"`
+
+	// in: query
+	AuthorID uuid.UUID `query:"author_id" json:"author_id" validate:"uuid"`
+
+	// in: query
+	From time.Time `query:"from" json:"from"`
+
+	// in: query
+	To time.Time `query:"to" json:"to"`
+
+	// Filter posts, or posts of the comments, with or without media
+	// in: query
+	HasMedia *bool `query:"has_media" json:"has_media"`
+
+	// Opaque cursor returned with the previous page
+	// in: query
+	Cursor string `query:"cursor" json:"cursor"`
+
+	// in: query
+	// required: true
+	// min: 1
+	// max: 50
+	Count int `query:"count" json:"count" validate:"required,min=1,max=50"`
+}
+
+// SearchPostsRequest is a struct that encapsulates a query used to search posts.
+// swagger:parameters searchPosts
+type SearchPostsRequest struct {
+	SearchRequestQuery
+}
+
+// SearchCommentsRequest is a struct that encapsulates a query used to search comments.
+// swagger:parameters searchComments
+type SearchCommentsRequest struct {
+	SearchRequestQuery
+}
+
+// SearchCursor is the position of the last fetched search result.
+type SearchCursor struct {
+	Rank float32 `json:"rank"`
+
+	CreatedAt time.Time `json:"created_at"`
+
+	ID uuid.UUID `json:"id"`
+}
+
+// SearchSuggestionsRequestQuery includes the beginning of the search text
+// and a count of suggestions of each type to retrieve.
+type SearchSuggestionsRequestQuery struct {
+	// in: query
+	// required: true
+	// max length: 64
+	Query string `query:"q" json:"q" validate:"required,lte=64"`
+
+	// in: query
+	// required: true
+	// min: 1
+	// max: 20
+	Count int `query:"count" json:"count" validate:"required,min=1,max=20"`
+}
+
+// SearchSuggestionsRequest is a struct that encapsulates a query used to fetch search suggestions.
+// swagger:parameters getSearchSuggestions
+type SearchSuggestionsRequest struct {
+	SearchSuggestionsRequestQuery
+}
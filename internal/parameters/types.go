@@ -8,7 +8,14 @@ type RequestParams interface {
 		PostIDParams |
 		PostCommentIDParams |
 		CommentAddRequestParams |
-		GetDataRequestParams
+		GetDataRequestParams |
+		MutedWordIDParams |
+		NotificationIDParams |
+		PushSubscriptionIDParams |
+		ConversationIDParams |
+		ConversationMessageIDParams |
+		ConversationMemberIDParams |
+		MessageAttachmentIDParams
 }
 
 // RequestQuery is interface to union all request queries in one type.
@@ -19,7 +26,19 @@ type RequestQuery interface {
 		RelationGetCountRequestQuery |
 		RelationGetRequestQuery |
 		RelationAddDeleteRequestQuery |
-		GetDataRequestQuery
+		GetDataRequestQuery |
+		SearchRequestQuery |
+		SearchSuggestionsRequestQuery |
+		LikesFetchRequestQuery |
+		PostInsightsRequestQuery |
+		HiddenPostsFetchRequestQuery |
+		RepliesFetchRequestQuery |
+		PostFetchRequestQuery |
+		NotificationsFetchRequestQuery |
+		RealtimeStreamRequestQuery |
+		ConversationsFetchRequestQuery |
+		MessagesFetchRequestQuery |
+		UploadDataRequestQuery
 }
 
 // RequestBody is interface to union all request body in one type.
@@ -30,6 +49,21 @@ type RequestBody interface {
 		UserUpdatePasswordRequestBody |
 		PostCreateRequestBody |
 		PostUpdateRequestBody |
+		PinnedPostsUpdateRequestBody |
+		ReactionRequestBody |
+		PostsViewRequestBody |
+		PostSensitivityUpdateRequestBody |
+		PollVoteRequestBody |
 		CommentAddRequestBody |
-		CommentUpdateRequestBody
+		CommentUpdateRequestBody |
+		MutedWordCreateRequestBody |
+		PushSubscriptionCreateRequestBody |
+		NotificationPreferencesUpdateRequestBody |
+		ConversationCreateRequestBody |
+		MessageSendRequestBody |
+		MessageUpdateRequestBody |
+		GroupCreateRequestBody |
+		ConversationUpdateRequestBody |
+		ConversationMembersAddRequestBody |
+		ConversationMemberRoleRequestBody
 }
@@ -1,6 +1,9 @@
 package parameters
 
-import "github.com/google/uuid"
+import (
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/google/uuid"
+)
 
 // UserIDParams includes the id of the user.
 type UserIDParams struct {
@@ -99,6 +102,10 @@ type UserUpdateRequestBody struct {
 	// min length: 0
 	// max length: 2048
 	About *string `db:"about" json:"about"`
+
+	// The way sensitive posts of other users are shown
+	// enum: show,blur,hide
+	SensitiveContent models.SensitiveContentPreference `json:"sensitive_content" validate:"omitempty,oneof=show blur hide"`
 }
 
 // UserUpdateRequest represents a request to update a user's information,
@@ -81,3 +81,10 @@ type RelationAddDeleteRequest struct {
 	RelationGetStatusParams
 	RelationAddDeleteRequestQuery
 }
+
+// CommentRestrictionRequest is a struct that encapsulates a request used to restrict the user
+// from commenting posts of the requester or to lift the restriction.
+// swagger:parameters restrictUser unrestrictUser
+type CommentRestrictionRequest struct {
+	RelationGetStatusParams
+}
@@ -2,6 +2,9 @@
 package queries
 
 import (
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/configs"
 	"github.com/MangriMen/Diverse-Back/internal/models"
 	"github.com/MangriMen/Diverse-Back/internal/parameters"
 	"github.com/google/uuid"
@@ -19,7 +22,8 @@ func (q *PostQueries) GetCommentsCount(postID uuid.UUID) (int, error) {
 
 	query := `SELECT Count(*)
 		FROM comments_view
-		WHERE post_id = $1`
+		WHERE post_id = $1
+		AND NOT tombstoned`
 
 	err := q.Get(
 		&commentsCount,
@@ -33,27 +37,110 @@ func (q *PostQueries) GetCommentsCount(postID uuid.UUID) (int, error) {
 	return commentsCount, nil
 }
 
-// GetComments is used to fetch comments related to a post based
-// on a provided post ID.
+// commentVisibleCondition returns the condition for comments_view rows visible to the requester.
+// Hidden comments and comments of users restricted by the post author are visible only
+// to the comment author and the post author.
+func commentVisibleCondition(requesterParam string) string {
+	return `AND (
+			comments_view.user_id = ` + requesterParam + `
+			OR EXISTS (
+				SELECT 1
+				FROM posts
+				WHERE posts.id = comments_view.post_id
+				AND posts.user_id = ` + requesterParam + `
+			)
+			OR (
+				NOT comments_view.hidden
+				AND NOT EXISTS (
+					SELECT 1
+					FROM comment_restrictions
+					JOIN posts ON posts.user_id = comment_restrictions.user_id
+					WHERE posts.id = comments_view.post_id
+					AND comment_restrictions.restricted_user_id = comments_view.user_id
+				)
+			)
+		)`
+}
+
+// commentsOrder returns the sort key column with its type and the comparison operator of the keyset
+// condition for the sort. Comments are ordered by the key, the comment id breaks ties.
+func commentsOrder(sort parameters.CommentSort) (string, string, string) {
+	switch sort {
+	case parameters.TopComments:
+		return "score", "float8", "<"
+	case parameters.OldestComments:
+		return "created_at", "timestamptz", ">"
+	case parameters.NewestComments:
+		return "created_at", "timestamptz", "<"
+	default:
+		return "created_at", "timestamptz", "<"
+	}
+}
+
+// GetComments is used to fetch top-level comments related to a post based
+// on a provided post ID in the given order after the cursor.
+// The pinned comment is skipped, it is fetched separately.
+//
+// The score of the top order is
+//
+//	(1 + likes) / (age in hours at the ranking time + 2) ^ gravity
+//
+// Likes are counted on each page, so the top order is best-effort: a comment which likes changed
+// since the previous page may be skipped or repeated. Newest and oldest orders are stable.
 func (q *PostQueries) GetComments(
 	postID uuid.UUID,
-	commentsFetchRequestQuery *parameters.CommentsFetchRequestQuery,
-) ([]models.DBComment, error) {
-	comments := []models.DBComment{}
+	userID uuid.UUID,
+	sort parameters.CommentSort,
+	cursor *parameters.CommentsCursor,
+	count int,
+	rankedAt time.Time,
+) ([]models.DBRankedComment, error) {
+	comments := []models.DBRankedComment{}
+
+	key, keyType, operator := commentsOrder(sort)
+
+	direction := "DESC"
+	if operator == ">" {
+		direction = "ASC"
+	}
 
 	query := `SELECT *
-		FROM comments_view
-		WHERE post_id = $1
-		AND created_at < $2
-		ORDER BY created_at DESC
-		FETCH FIRST $3 ROWS ONLY`
+		FROM (
+			SELECT comments_view.*,
+				(1 + comments_view.likes)
+				/ power(greatest(extract(EPOCH FROM $2::timestamptz - comments_view.created_at), 0) / 3600 + 2,
+					$3::float8) AS score
+			FROM comments_view
+			WHERE post_id = $1
+			AND parent_comment_id IS NULL
+			AND id NOT IN (SELECT comment_id FROM pinned_comments WHERE post_id = $1)
+			` + commentVisibleCondition("$7") + `
+		) AS comments
+		WHERE $5::uuid IS NULL
+		OR (` + key + `, id) ` + operator + ` ($4::` + keyType + `, $5::uuid)
+		ORDER BY ` + key + ` ` + direction + `, id ` + direction + `
+		FETCH FIRST $6 ROWS ONLY`
+
+	var cursorKey, cursorID interface{}
+	if cursor != nil {
+		cursorID = cursor.CommentID
+		if sort == parameters.TopComments {
+			cursorKey = cursor.Score
+		} else {
+			cursorKey = cursor.CreatedAt
+		}
+	}
 
 	err := q.Select(
 		&comments,
 		query,
 		postID,
-		commentsFetchRequestQuery.LastSeenCommentCreatedAt,
-		commentsFetchRequestQuery.Count,
+		rankedAt,
+		configs.CommentsTopGravity,
+		cursorKey,
+		cursorID,
+		count,
+		userID,
 	)
 	if err != nil {
 		return comments, err
@@ -62,6 +149,36 @@ func (q *PostQueries) GetComments(
 	return comments, nil
 }
 
+// GetCommentReplies is used to fetch direct replies to the comment after the cursor, oldest first.
+func (q *PostQueries) GetCommentReplies(
+	commentID uuid.UUID,
+	userID uuid.UUID,
+	cursor *parameters.RepliesCursor,
+	count int,
+) ([]models.DBComment, error) {
+	replies := []models.DBComment{}
+
+	query := `SELECT *
+		FROM comments_view
+		WHERE parent_comment_id = $1
+		AND ($2::timestamptz IS NULL OR (created_at, id) > ($2::timestamptz, $3::uuid))
+		` + commentVisibleCondition("$5") + `
+		ORDER BY created_at, id
+		FETCH FIRST $4 ROWS ONLY`
+
+	var createdAt, id interface{}
+	if cursor != nil {
+		createdAt, id = cursor.CreatedAt, cursor.CommentID
+	}
+
+	err := q.Select(&replies, query, commentID, createdAt, id, count, userID)
+	if err != nil {
+		return replies, err
+	}
+
+	return replies, nil
+}
+
 // GetComment retrieves a single comment from the database based on the given id parameter.
 func (q *PostQueries) GetComment(id uuid.UUID) (models.DBComment, error) {
 	comment := models.DBComment{}
@@ -80,13 +197,27 @@ func (q *PostQueries) GetComment(id uuid.UUID) (models.DBComment, error) {
 
 // AddComment add a single comment to the database based on the given comment object.
 func (q *PostQueries) AddComment(b *models.DBComment) error {
-	query := `INSERT INTO comments
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	query := `INSERT INTO comments (id, post_id, user_id, content, created_at, updated_at, likes, language,
+			parent_comment_id, markdown, entities)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11::jsonb)
 			ON CONFLICT (id) DO
 		UPDATE
 			SET deleted_at = NULL`
 
-	_, err := q.Exec(query, b.ID, b.PostID, b.UserID, b.Content, b.CreatedAt, b.UpdatedAt, b.Likes)
+	_, err := q.Exec(
+		query,
+		b.ID,
+		b.PostID,
+		b.UserID,
+		b.Content,
+		b.CreatedAt,
+		b.UpdatedAt,
+		b.Likes,
+		b.Language,
+		b.ParentCommentID,
+		b.Markdown,
+		b.Entities,
+	)
 	if err != nil {
 		return err
 	}
@@ -98,11 +229,13 @@ func (q *PostQueries) AddComment(b *models.DBComment) error {
 func (q *UserQueries) UpdateComment(b *models.DBComment) error {
 	query := `UPDATE comments
 		SET
-			content = $2
-			updated_at = $3
+			content = $2,
+			updated_at = $3,
+			markdown = $4,
+			entities = $5::jsonb
 		WHERE id = $1`
 
-	_, err := q.Exec(query, b.ID, b.Content, b.UpdatedAt)
+	_, err := q.Exec(query, b.ID, b.Content, b.UpdatedAt, b.Markdown, b.Entities)
 	if err != nil {
 		return err
 	}
@@ -110,15 +243,37 @@ func (q *UserQueries) UpdateComment(b *models.DBComment) error {
 	return nil
 }
 
-// LikeComment sets like the comment by ID.
+// SetCommentEntities saves entities of the comment written before entities were stored.
+func (q *PostQueries) SetCommentEntities(id uuid.UUID, entities models.Entities) error {
+	query := `UPDATE comments
+		SET
+			entities = $2::jsonb
+		WHERE id = $1
+		AND entities IS NULL`
+
+	_, err := q.Exec(query, id, entities)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// LikeComment sets the reaction of the user to the comment, replacing the previous one.
+// The like time is kept when only the reaction is changed.
 func (q *PostQueries) LikeComment(l *models.DBCommentLike) error {
-	query := `INSERT INTO comment_likes
-		VALUES ($1, $2, $3)
+	query := `INSERT INTO comment_likes (id, comment_id, user_id, reaction, created_at)
+		VALUES ($1, $2, $3, $4, $5)
 			ON CONFLICT (comment_id, user_id) DO
 		UPDATE
-			SET deleted_at = NULL`
-
-	_, err := q.Exec(query, l.ID, l.CommentID, l.UserID)
+			SET deleted_at = NULL,
+			reaction = EXCLUDED.reaction,
+			created_at = CASE
+				WHEN comment_likes.deleted_at IS NULL THEN comment_likes.created_at
+				ELSE EXCLUDED.created_at
+			END`
+
+	_, err := q.Exec(query, l.ID, l.CommentID, l.UserID, l.Reaction, l.CreatedAt)
 	if err != nil {
 		return err
 	}
@@ -126,12 +281,13 @@ func (q *PostQueries) LikeComment(l *models.DBCommentLike) error {
 	return nil
 }
 
-// UnlikeComment sets like the comment by ID.
+// UnlikeComment removes the reaction of the user to the comment.
 func (q *PostQueries) UnlikeComment(l *models.DBCommentLike) error {
 	query := `UPDATE comment_likes
 		SET
-			deleted_at = now()	
-		WHERE comment_id = $1 AND user_id = $2`
+			deleted_at = now()
+		WHERE comment_id = $1 AND user_id = $2
+		AND deleted_at IS NULL`
 
 	_, err := q.Exec(query, l.CommentID, l.UserID)
 	if err != nil {
@@ -141,27 +297,29 @@ func (q *PostQueries) UnlikeComment(l *models.DBCommentLike) error {
 	return nil
 }
 
-// GetCommentIsLiked gets status of like for given comment and user.
-func (q *PostQueries) GetCommentIsLiked(commentID uuid.UUID, userID uuid.UUID) (bool, error) {
-	likesCount := 0
+// GetCommentReaction gets the reaction of the user to the comment, empty if there is no reaction.
+func (q *PostQueries) GetCommentReaction(commentID uuid.UUID, userID uuid.UUID) (string, error) {
+	reactions := []string{}
 
-	query := `SELECT Count(*)
+	query := `SELECT reaction
 		FROM comment_likes_view
 		WHERE comment_id = $1 AND user_id = $2`
 
-	err := q.Get(&likesCount, query, commentID, userID)
-	if err != nil {
-		return false, err
+	err := q.Select(&reactions, query, commentID, userID)
+	if err != nil || len(reactions) == 0 {
+		return "", err
 	}
 
-	return likesCount > 0, nil
+	return reactions[0], nil
 }
 
 // DeleteComment deletes comment from database based on the given comment ID.
+// A comment with replies is turned into a tombstone to keep the thread.
 func (q *PostQueries) DeleteComment(id uuid.UUID) error {
 	query := `UPDATE comments
 		SET
-			deleted_at = now()
+			deleted_at = CASE WHEN reply_count > 0 THEN NULL ELSE now() END,
+			tombstoned_at = CASE WHEN reply_count > 0 THEN now() END
 		WHERE id = $1`
 
 	_, err := q.Exec(query, id)
@@ -171,3 +329,113 @@ func (q *PostQueries) DeleteComment(id uuid.UUID) error {
 
 	return nil
 }
+
+// GetPinnedComment is used to fetch the pinned comment of the post if it is visible to the user.
+func (q *PostQueries) GetPinnedComment(postID uuid.UUID, userID uuid.UUID) (models.DBComment, error) {
+	comment := models.DBComment{}
+
+	query := `SELECT comments_view.*
+		FROM pinned_comments
+		JOIN comments_view ON comments_view.id = pinned_comments.comment_id
+		WHERE pinned_comments.post_id = $1
+		` + commentVisibleCondition("$2")
+
+	err := q.Get(&comment, query, postID, userID)
+	if err != nil {
+		return comment, err
+	}
+
+	return comment, nil
+}
+
+// PinComment pins the comment to the top of the post comments, replacing the previously pinned one.
+func (q *PostQueries) PinComment(postID uuid.UUID, commentID uuid.UUID) error {
+	query := `INSERT INTO pinned_comments (post_id, comment_id)
+		VALUES ($1, $2)
+			ON CONFLICT (post_id) DO
+		UPDATE
+			SET comment_id = EXCLUDED.comment_id,
+			pinned_at = now()`
+
+	_, err := q.Exec(query, postID, commentID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UnpinComment unpins the comment of the post. Returns false if the comment is not pinned.
+func (q *PostQueries) UnpinComment(postID uuid.UUID, commentID uuid.UUID) (bool, error) {
+	query := `DELETE FROM pinned_comments
+		WHERE post_id = $1 AND comment_id = $2`
+
+	result, err := q.Exec(query, postID, commentID)
+	if err != nil {
+		return false, err
+	}
+
+	unpinned, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return unpinned > 0, nil
+}
+
+// SetCommentHidden hides the comment from everyone except its author and the post author, or shows it again.
+func (q *PostQueries) SetCommentHidden(id uuid.UUID, hidden bool) error {
+	query := `UPDATE comments
+		SET
+			hidden_at = CASE WHEN $2::boolean THEN coalesce(hidden_at, now()) END
+		WHERE id = $1`
+
+	_, err := q.Exec(query, id, hidden)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RestrictUser restricts the user from commenting posts of the given author.
+func (q *PostQueries) RestrictUser(userID uuid.UUID, restrictedUserID uuid.UUID) error {
+	query := `INSERT INTO comment_restrictions (user_id, restricted_user_id)
+		VALUES ($1, $2)
+			ON CONFLICT DO NOTHING`
+
+	_, err := q.Exec(query, userID, restrictedUserID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UnrestrictUser allows the restricted user to comment posts of the given author again.
+func (q *PostQueries) UnrestrictUser(userID uuid.UUID, restrictedUserID uuid.UUID) error {
+	query := `DELETE FROM comment_restrictions
+		WHERE user_id = $1 AND restricted_user_id = $2`
+
+	_, err := q.Exec(query, userID, restrictedUserID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// IsUserRestricted returns whether the user is restricted from commenting posts of the given author.
+func (q *PostQueries) IsUserRestricted(userID uuid.UUID, restrictedUserID uuid.UUID) (bool, error) {
+	restricted := false
+
+	query := `SELECT EXISTS (
+			SELECT 1
+			FROM comment_restrictions
+			WHERE user_id = $1 AND restricted_user_id = $2
+		)`
+
+	err := q.Get(&restricted, query, userID, restrictedUserID)
+
+	return restricted, err
+}
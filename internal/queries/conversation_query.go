@@ -0,0 +1,527 @@
+package queries
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/MangriMen/Diverse-Back/internal/parameters"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/samber/lo"
+)
+
+// ConversationQueries is struct for interacting with a database for conversation-related queries.
+type ConversationQueries struct {
+	*sqlx.DB
+}
+
+// memberConversationColumns selects conversations joined with conversation_members as seen by the member
+// with the count of messages of other members sent after the last read one and since the member joined.
+const memberConversationColumns = `conversations.*,
+		conversation_members.status,
+		conversation_members.last_read_at,
+		conversation_members.role,
+		conversation_members.muted,
+		conversation_members.joined_at,
+		(
+			SELECT Count(*)
+			FROM messages
+			WHERE messages.conversation_id = conversations.id
+			AND messages.sender_id IS DISTINCT FROM conversation_members.user_id
+			AND messages.created_at > GREATEST(conversation_members.last_read_at, conversation_members.joined_at)
+		) AS unread_count`
+
+// insertMembersQuery adds users to the conversation as members. Users who don't follow
+// the member who added them get the conversation as a message request.
+const insertMembersQuery = `INSERT INTO conversation_members (conversation_id, user_id, status, role, joined_at)
+		SELECT
+			$1,
+			new_members.user_id,
+			CASE
+				WHEN EXISTS (
+					SELECT 1
+					FROM user_relations_view
+					WHERE user_id = new_members.user_id
+					AND relation_user_id = $2
+					AND type = 'following'
+				) THEN 'accepted'
+				ELSE 'request'
+			END,
+			'member',
+			$4
+		FROM unnest($3::uuid[]) AS new_members(user_id)
+			ON CONFLICT (conversation_id, user_id) DO NOTHING`
+
+// CreateDirectConversation starts the direct conversation of the user with the other user,
+// or returns the existing one. The other user gets the new conversation as a message request
+// unless they follow the user. Returns true if the conversation is created.
+func (q *ConversationQueries) CreateDirectConversation(
+	c *models.DBConversation,
+	userID uuid.UUID,
+	otherUserID uuid.UUID,
+) (bool, error) {
+	conversationQuery := `INSERT INTO conversations (id, type, direct_key, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+			ON CONFLICT (direct_key) DO NOTHING
+		RETURNING *`
+
+	existingQuery := `SELECT *
+		FROM conversations
+		WHERE direct_key = $1`
+
+	ownerQuery := `INSERT INTO conversation_members (conversation_id, user_id, status, role, joined_at)
+		VALUES ($1, $2, 'accepted', $3, $4)`
+
+	tx, err := q.Beginx()
+	if err != nil {
+		return false, err
+	}
+
+	err = tx.Get(c, conversationQuery, c.ID, c.Type, c.DirectKey, c.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		if err = tx.Get(c, existingQuery, c.DirectKey); err != nil {
+			_ = tx.Rollback()
+			return false, err
+		}
+
+		return false, tx.Commit()
+	}
+
+	if err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	if _, err = tx.Exec(ownerQuery, c.ID, userID, models.MemberRole, c.CreatedAt); err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	_, err = tx.Exec(insertMembersQuery, c.ID, userID, []string{otherUserID.String()}, c.CreatedAt)
+	if err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// CreateGroupConversation creates the group conversation owned by the user with the given members
+// and records its creation with the system message.
+func (q *ConversationQueries) CreateGroupConversation(
+	c *models.DBConversation,
+	ownerID uuid.UUID,
+	memberIDs []uuid.UUID,
+	m *models.DBMessage,
+) error {
+	conversationQuery := `INSERT INTO conversations (id, type, name, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)`
+
+	ownerQuery := `INSERT INTO conversation_members (conversation_id, user_id, status, role, joined_at)
+		VALUES ($1, $2, 'accepted', $3, $4)`
+
+	tx, err := q.Beginx()
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(conversationQuery, c.ID, c.Type, c.Name, c.CreatedAt); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if _, err = tx.Exec(ownerQuery, c.ID, ownerID, models.OwnerRole, c.CreatedAt); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if _, err = tx.Exec(insertMembersQuery, c.ID, ownerID, uuidStrings(memberIDs), c.CreatedAt); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err = insertMessage(tx, m); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// AddConversationMembers adds users to the group conversation and records each addition with the system message.
+// Returns false if the group would have more members than the limit. The conversation row is locked,
+// so concurrent additions can't exceed the limit together.
+func (q *ConversationQueries) AddConversationMembers(
+	conversationID uuid.UUID,
+	actorID uuid.UUID,
+	userIDs []uuid.UUID,
+	messages []models.DBMessage,
+) (bool, error) {
+	lockQuery := `SELECT 1
+		FROM conversations
+		WHERE id = $1
+		FOR NO KEY UPDATE`
+
+	countQuery := `SELECT Count(*)
+		FROM conversation_members
+		WHERE conversation_id = $1`
+
+	tx, err := q.Beginx()
+	if err != nil {
+		return false, err
+	}
+
+	if _, err = tx.Exec(lockQuery, conversationID); err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	joinedAt := time.Now()
+	if len(messages) > 0 {
+		joinedAt = messages[0].CreatedAt
+	}
+
+	if _, err = tx.Exec(insertMembersQuery, conversationID, actorID, uuidStrings(userIDs), joinedAt); err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	var membersCount int
+	if err = tx.Get(&membersCount, countQuery, conversationID); err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	if membersCount > configs.ConversationMaxMembers {
+		_ = tx.Rollback()
+		return false, nil
+	}
+
+	for i := range messages {
+		if err = insertMessage(tx, &messages[i]); err != nil {
+			_ = tx.Rollback()
+			return false, err
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+// RemoveConversationMember removes the member from the group conversation and records the removal
+// with the system message. The ownership passes to the earliest admin, or the earliest member
+// if there are no admins, when the owner leaves. The conversation is deleted when the last member leaves.
+func (q *ConversationQueries) RemoveConversationMember(
+	conversationID uuid.UUID,
+	userID uuid.UUID,
+	m *models.DBMessage,
+) error {
+	memberQuery := `DELETE FROM conversation_members
+		WHERE conversation_id = $1
+		AND user_id = $2`
+
+	ownerQuery := `UPDATE conversation_members
+		SET
+			role = 'owner'
+		WHERE conversation_id = $1
+		AND user_id = (
+			SELECT user_id
+			FROM conversation_members
+			WHERE conversation_id = $1
+			ORDER BY role = 'admin' DESC, joined_at, user_id
+			FETCH FIRST 1 ROWS ONLY
+		)
+		AND NOT EXISTS (
+			SELECT 1
+			FROM conversation_members
+			WHERE conversation_id = $1
+			AND role = 'owner'
+		)`
+
+	conversationQuery := `DELETE FROM conversations
+		WHERE id = $1
+		AND NOT EXISTS (
+			SELECT 1
+			FROM conversation_members
+			WHERE conversation_id = $1
+		)`
+
+	tx, err := q.Beginx()
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(memberQuery, conversationID, userID); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if _, err = tx.Exec(ownerQuery, conversationID); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	result, err := tx.Exec(conversationQuery, conversationID)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if deleted, _ := result.RowsAffected(); deleted == 0 {
+		if err = insertMessage(tx, m); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UpdateConversationName renames the group conversation and records the change with the system message.
+func (q *ConversationQueries) UpdateConversationName(c *models.DBConversation, m *models.DBMessage) error {
+	query := `UPDATE conversations
+		SET
+			name = $2
+		WHERE id = $1`
+
+	tx, err := q.Beginx()
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(query, c.ID, c.Name); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err = insertMessage(tx, m); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateMemberRole changes the role of the member of the group conversation.
+func (q *ConversationQueries) UpdateMemberRole(
+	conversationID uuid.UUID,
+	userID uuid.UUID,
+	role models.ConversationRole,
+) error {
+	query := `UPDATE conversation_members
+		SET
+			role = $3
+		WHERE conversation_id = $1
+		AND user_id = $2`
+
+	_, err := q.Exec(query, conversationID, userID, role)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateConversationMuted mutes or unmutes the conversation for the member.
+func (q *ConversationQueries) UpdateConversationMuted(conversationID uuid.UUID, userID uuid.UUID, muted bool) error {
+	query := `UPDATE conversation_members
+		SET
+			muted = $3
+		WHERE conversation_id = $1
+		AND user_id = $2`
+
+	_, err := q.Exec(query, conversationID, userID, muted)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetConversationMember retrieves the member of the conversation.
+func (q *ConversationQueries) GetConversationMember(
+	conversationID uuid.UUID,
+	userID uuid.UUID,
+) (models.DBConversationMember, error) {
+	member := models.DBConversationMember{}
+
+	query := `SELECT *
+		FROM conversation_members
+		WHERE conversation_id = $1
+		AND user_id = $2`
+
+	err := q.Get(&member, query, conversationID, userID)
+	if err != nil {
+		return member, err
+	}
+
+	return member, nil
+}
+
+// uuidStrings converts ids to strings to pass them as the uuid array parameter.
+func uuidStrings(ids []uuid.UUID) []string {
+	return lo.Map(ids, func(item uuid.UUID, index int) string {
+		return item.String()
+	})
+}
+
+// GetMemberConversation retrieves the conversation as seen by the member,
+// sql.ErrNoRows is returned if the user is not a member of the conversation.
+func (q *ConversationQueries) GetMemberConversation(
+	conversationID uuid.UUID,
+	userID uuid.UUID,
+) (models.DBMemberConversation, error) {
+	conversation := models.DBMemberConversation{}
+
+	query := `SELECT ` + memberConversationColumns + `
+		FROM conversations
+		JOIN conversation_members ON conversation_members.conversation_id = conversations.id
+		WHERE conversations.id = $1
+		AND conversation_members.user_id = $2`
+
+	err := q.Get(&conversation, query, conversationID, userID)
+	if err != nil {
+		return conversation, err
+	}
+
+	return conversation, nil
+}
+
+// GetMemberConversations retrieves a page of conversations of the member with the given status
+// after the cursor, conversations with the latest messages first.
+func (q *ConversationQueries) GetMemberConversations(
+	userID uuid.UUID,
+	status models.ConversationStatus,
+	cursor *parameters.ConversationsCursor,
+	count int,
+) ([]models.DBMemberConversation, error) {
+	conversations := []models.DBMemberConversation{}
+
+	query := `SELECT ` + memberConversationColumns + `
+		FROM conversations
+		JOIN conversation_members ON conversation_members.conversation_id = conversations.id
+		WHERE conversation_members.user_id = $1
+		AND conversation_members.status = $2
+		AND ($3::timestamptz IS NULL OR (conversations.updated_at, conversations.id) < ($3::timestamptz, $4::uuid))
+		ORDER BY conversations.updated_at DESC, conversations.id DESC
+		FETCH FIRST $5 ROWS ONLY`
+
+	var updatedAt, id interface{}
+	if cursor != nil {
+		updatedAt, id = cursor.UpdatedAt, cursor.ConversationID
+	}
+
+	err := q.Select(&conversations, query, userID, status, updatedAt, id, count)
+	if err != nil {
+		return conversations, err
+	}
+
+	return conversations, nil
+}
+
+// GetConversationMembers retrieves members of the conversation in the order they joined.
+func (q *ConversationQueries) GetConversationMembers(conversationID uuid.UUID) ([]models.DBConversationMember, error) {
+	members := []models.DBConversationMember{}
+
+	query := `SELECT *
+		FROM conversation_members
+		WHERE conversation_id = $1
+		ORDER BY joined_at, user_id`
+
+	err := q.Select(&members, query, conversationID)
+	if err != nil {
+		return members, err
+	}
+
+	return members, nil
+}
+
+// GetUnreadConversationsCount retrieves the number of accepted conversations of the user with unread messages,
+// except muted ones, and the number of message requests of the user.
+func (q *ConversationQueries) GetUnreadConversationsCount(userID uuid.UUID) (int, int, error) {
+	counts := struct {
+		Unread   int `db:"unread"`
+		Requests int `db:"requests"`
+	}{}
+
+	query := `SELECT
+			Count(*) FILTER (WHERE status = 'accepted' AND NOT muted AND unread_count > 0) AS unread,
+			Count(*) FILTER (WHERE status = 'request') AS requests
+		FROM (
+			SELECT ` + memberConversationColumns + `
+			FROM conversations
+			JOIN conversation_members ON conversation_members.conversation_id = conversations.id
+			WHERE conversation_members.user_id = $1
+			AND conversation_members.status IN ('accepted', 'request')
+		) AS member_conversations`
+
+	err := q.Get(&counts, query, userID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return counts.Unread, counts.Requests, nil
+}
+
+// UpdateConversationStatus accepts or declines the conversation for the member.
+func (q *ConversationQueries) UpdateConversationStatus(
+	conversationID uuid.UUID,
+	userID uuid.UUID,
+	status models.ConversationStatus,
+) error {
+	query := `UPDATE conversation_members
+		SET
+			status = $3
+		WHERE conversation_id = $1
+		AND user_id = $2`
+
+	_, err := q.Exec(query, conversationID, userID, status)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ReadConversation marks messages of the conversation sent until the given time as read by the member.
+// The read marker never moves back.
+func (q *ConversationQueries) ReadConversation(conversationID uuid.UUID, userID uuid.UUID, readAt time.Time) error {
+	query := `UPDATE conversation_members
+		SET
+			last_read_at = GREATEST(last_read_at, $3)
+		WHERE conversation_id = $1
+		AND user_id = $2`
+
+	_, err := q.Exec(query, conversationID, userID, readAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// IsConversationBlocked reports whether the user blocked, or was blocked by, any other member of the conversation.
+func (q *ConversationQueries) IsConversationBlocked(conversationID uuid.UUID, userID uuid.UUID) (bool, error) {
+	blocked := false
+
+	query := `SELECT EXISTS (
+			SELECT 1
+			FROM conversation_members
+			JOIN user_relations_view AS blocks ON blocks.type = 'blocked'
+			AND (
+				blocks.user_id = $2 AND blocks.relation_user_id = conversation_members.user_id
+				OR blocks.user_id = conversation_members.user_id AND blocks.relation_user_id = $2
+			)
+			WHERE conversation_members.conversation_id = $1
+			AND conversation_members.user_id <> $2
+		)`
+
+	err := q.Get(&blocked, query, conversationID, userID)
+	if err != nil {
+		return blocked, err
+	}
+
+	return blocked, nil
+}
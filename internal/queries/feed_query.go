@@ -0,0 +1,167 @@
+package queries
+
+import (
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// FeedQueries is struct for interacting with a database for feed-related queries.
+type FeedQueries struct {
+	*sqlx.DB
+}
+
+// CreateFeedSnapshot ranks posts for the user and saves their order as a new feed session.
+//
+// The score of the post is
+//
+//	(1 + likesWeight * ln(1 + likes) + commentsWeight * ln(1 + recent comments)) * affinity
+//	/ (age in hours + 2) ^ gravity
+//
+// where the affinity grows with likes the user gave to the author and following the author.
+// Each next post of the same author is penalized to keep the feed diverse.
+// Own posts and posts of users blocked by or blocking the user are skipped.
+func (q *FeedQueries) CreateFeedSnapshot(id uuid.UUID, userID uuid.UUID, createdAt time.Time) error {
+	query := `WITH liked_authors AS (
+			SELECT liked_posts.user_id, Count(*) AS likes
+			FROM post_likes_view
+			JOIN posts_view AS liked_posts ON liked_posts.id = post_likes_view.post_id
+			WHERE post_likes_view.user_id = $2
+			GROUP BY liked_posts.user_id
+		),
+		followed_authors AS (
+			SELECT relation_user_id AS user_id
+			FROM user_relations_view
+			WHERE user_id = $2 AND type = 'following'
+		),
+		scored_posts AS (
+			SELECT posts_view.id,
+				posts_view.user_id,
+				(1 + $5::float8 * ln(1 + posts_view.likes) + $6::float8 * ln(1 + recent_comments.count))
+				* (1 + ln(1 + coalesce(liked_authors.likes, 0))
+					+ CASE WHEN followed_authors.user_id IS NULL THEN 0 ELSE $7::float8 END)
+				/ power(extract(EPOCH FROM $3::timestamptz - posts_view.created_at) / 3600 + 2, $8::float8) AS score
+			FROM posts_view
+			LEFT JOIN liked_authors USING (user_id)
+			LEFT JOIN followed_authors USING (user_id)
+			CROSS JOIN LATERAL (
+				SELECT Count(*) AS count
+				FROM comments_view
+				WHERE comments_view.post_id = posts_view.id
+				AND comments_view.created_at > $3 - make_interval(secs => $9)
+			) AS recent_comments
+			WHERE posts_view.created_at <= $3
+			AND posts_view.created_at > $3 - make_interval(secs => $4)
+			AND posts_view.user_id <> $2
+			` + notBlockedCondition("posts_view.user_id", "$2") + `
+		),
+		diversified_posts AS (
+			SELECT id,
+				score * power($10::float8, author_position - 1) AS score
+			FROM (
+				SELECT id,
+					score,
+					ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY score DESC, id) AS author_position
+				FROM scored_posts
+			) AS positioned_posts
+			WHERE author_position <= $11
+			ORDER BY score DESC, id
+			FETCH FIRST $12 ROWS ONLY
+		)
+		INSERT INTO feed_snapshots (id, user_id, post_ids, created_at)
+		SELECT $1, $2, coalesce(array_agg(id ORDER BY score DESC, id), '{}'), $3
+		FROM diversified_posts`
+
+	_, err := q.Exec(
+		query,
+		id,
+		userID,
+		createdAt,
+		configs.FeedCandidateWindow.Seconds(),
+		configs.FeedLikesWeight,
+		configs.FeedCommentsWeight,
+		configs.FeedFollowingAffinity,
+		configs.FeedGravity,
+		configs.FeedCommentVelocityWindow.Seconds(),
+		configs.FeedAuthorRepeatPenalty,
+		configs.FeedMaxPostsPerAuthor,
+		configs.FeedSnapshotSize,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetFeedPosts is used to fetch posts of the user feed session after the given position.
+// Deleted posts and posts of users blocked since the session was created are skipped.
+func (q *FeedQueries) GetFeedPosts(
+	snapshotID uuid.UUID,
+	userID uuid.UUID,
+	lastSeenPosition int,
+	count int,
+) ([]models.DBFeedPost, error) {
+	posts := []models.DBFeedPost{}
+
+	query := `SELECT posts_view.*, feed.position
+		FROM feed_snapshots
+		CROSS JOIN LATERAL unnest(feed_snapshots.post_ids) WITH ORDINALITY AS feed(id, position)
+		JOIN posts_view ON posts_view.id = feed.id
+		WHERE feed_snapshots.id = $1
+		AND feed_snapshots.user_id = $2
+		AND feed_snapshots.created_at > $3
+		AND feed.position > $4
+		` + notBlockedCondition("posts_view.user_id", "$2") + `
+		` + notMutedCondition("posts_view", "$2") + `
+		ORDER BY feed.position
+		FETCH FIRST $5 ROWS ONLY`
+
+	err := q.Select(
+		&posts,
+		query,
+		snapshotID,
+		userID,
+		time.Now().Add(-configs.FeedSnapshotLifetime),
+		lastSeenPosition,
+		count,
+	)
+	if err != nil {
+		return posts, err
+	}
+
+	return posts, nil
+}
+
+// FeedSnapshotExists is used to check whether the feed session of the user can still be scrolled.
+func (q *FeedQueries) FeedSnapshotExists(id uuid.UUID, userID uuid.UUID) (bool, error) {
+	var exists bool
+
+	query := `SELECT EXISTS (
+			SELECT 1 FROM feed_snapshots
+			WHERE id = $1 AND user_id = $2 AND created_at > $3
+		)`
+
+	err := q.Get(&exists, query, id, userID, time.Now().Add(-configs.FeedSnapshotLifetime))
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// DeleteExpiredFeedSnapshots deletes feed sessions which can't be scrolled anymore.
+func (q *FeedQueries) DeleteExpiredFeedSnapshots() error {
+	query := `DELETE FROM feed_snapshots
+		WHERE created_at < $1`
+
+	_, err := q.Exec(query, time.Now().Add(-configs.FeedSnapshotLifetime))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
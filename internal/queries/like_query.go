@@ -0,0 +1,85 @@
+package queries
+
+import (
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/MangriMen/Diverse-Back/internal/parameters"
+	"github.com/google/uuid"
+)
+
+// likersQuery selects users who reacted to the target in the given likes view, $1 is target id,
+// $2 is requester id, $3 - $5 is the cursor and $6 is count. Users followed by the requester go first,
+// then the latest likes. Users blocked by or blocking the requester are skipped.
+func likersQuery(likesView string, targetColumn string) string {
+	return `SELECT *
+		FROM (
+			SELECT users_view.*,
+				` + likesView + `.reaction,
+				` + likesView + `.created_at AS liked_at,
+				EXISTS (
+					SELECT 1
+					FROM user_relations_view AS followings
+					WHERE followings.user_id = $2
+					AND followings.relation_user_id = users_view.id
+					AND followings.type = 'following'
+				) AS followed
+			FROM ` + likesView + `
+			JOIN users_view ON users_view.id = ` + likesView + `.user_id
+			WHERE ` + likesView + `.` + targetColumn + ` = $1
+			` + notBlockedCondition("users_view.id", "$2") + `
+		) AS likers
+		WHERE $3::boolean IS NULL
+		OR (followed, liked_at, id) < ($3::boolean, $4::timestamptz, $5::uuid)
+		ORDER BY followed DESC, liked_at DESC, id DESC
+		FETCH FIRST $6 ROWS ONLY`
+}
+
+// GetPostLikers is used to fetch users who reacted to the post after the cursor.
+func (q *PostQueries) GetPostLikers(
+	postID uuid.UUID,
+	userID uuid.UUID,
+	cursor *parameters.LikesCursor,
+	count int,
+) ([]models.DBLiker, error) {
+	likers := []models.DBLiker{}
+
+	err := q.Select(
+		&likers,
+		likersQuery("post_likes_view", "post_id"),
+		append([]interface{}{postID, userID}, likesCursorArgs(cursor, count)...)...,
+	)
+	if err != nil {
+		return likers, err
+	}
+
+	return likers, nil
+}
+
+// GetCommentLikers is used to fetch users who reacted to the comment after the cursor.
+func (q *PostQueries) GetCommentLikers(
+	commentID uuid.UUID,
+	userID uuid.UUID,
+	cursor *parameters.LikesCursor,
+	count int,
+) ([]models.DBLiker, error) {
+	likers := []models.DBLiker{}
+
+	err := q.Select(
+		&likers,
+		likersQuery("comment_likes_view", "comment_id"),
+		append([]interface{}{commentID, userID}, likesCursorArgs(cursor, count)...)...,
+	)
+	if err != nil {
+		return likers, err
+	}
+
+	return likers, nil
+}
+
+// likesCursorArgs returns arguments $3 - $6 of the likers query.
+func likesCursorArgs(cursor *parameters.LikesCursor, count int) []interface{} {
+	if cursor == nil {
+		return []interface{}{nil, nil, nil, count}
+	}
+
+	return []interface{}{cursor.Followed, cursor.LikedAt, cursor.UserID, count}
+}
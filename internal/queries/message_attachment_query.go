@@ -0,0 +1,80 @@
+package queries
+
+import (
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// MessageAttachmentQueries is struct for interacting with a database for message attachment-related queries.
+type MessageAttachmentQueries struct {
+	*sqlx.DB
+}
+
+// GetMessageAttachment retrieves the attachment with given id.
+func (q *MessageAttachmentQueries) GetMessageAttachment(id uuid.UUID) (models.DBMessageAttachment, error) {
+	attachment := models.DBMessageAttachment{}
+
+	query := `SELECT *
+		FROM message_attachments
+		WHERE id = $1`
+
+	err := q.Get(&attachment, query, id)
+	if err != nil {
+		return attachment, err
+	}
+
+	return attachment, nil
+}
+
+// GetMessageAttachments retrieves attachments of the message in the order they were uploaded.
+func (q *MessageAttachmentQueries) GetMessageAttachments(messageID uuid.UUID) ([]models.DBMessageAttachment, error) {
+	attachments := []models.DBMessageAttachment{}
+
+	query := `SELECT *
+		FROM message_attachments
+		WHERE message_id = $1
+		ORDER BY created_at, id`
+
+	err := q.Select(&attachments, query, messageID)
+	if err != nil {
+		return attachments, err
+	}
+
+	return attachments, nil
+}
+
+// CreateMessageAttachment saves the uploaded attachment which is not sent yet.
+func (q *MessageAttachmentQueries) CreateMessageAttachment(a *models.DBMessageAttachment) error {
+	query := `INSERT INTO message_attachments (id, user_id, filename, created_at)
+		VALUES ($1, $2, $3, $4)`
+
+	_, err := q.Exec(query, a.ID, a.UserID, a.Filename, a.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteUnusedMessageAttachments deletes attachments uploaded before the given time which are not sent
+// or whose messages are deleted. Returns deleted attachments to remove their files.
+func (q *MessageAttachmentQueries) DeleteUnusedMessageAttachments(
+	before time.Time,
+) ([]models.DBMessageAttachment, error) {
+	attachments := []models.DBMessageAttachment{}
+
+	query := `DELETE FROM message_attachments
+		WHERE message_id IS NULL
+		AND created_at < $1
+		RETURNING *`
+
+	err := q.Select(&attachments, query, before)
+	if err != nil {
+		return attachments, err
+	}
+
+	return attachments, nil
+}
@@ -0,0 +1,185 @@
+package queries
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/MangriMen/Diverse-Back/internal/parameters"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// MessageQueries is struct for interacting with a database for message-related queries.
+type MessageQueries struct {
+	*sqlx.DB
+}
+
+// GetMessage retrieves the message by the given id.
+func (q *MessageQueries) GetMessage(id uuid.UUID) (models.DBMessage, error) {
+	message := models.DBMessage{}
+
+	query := `SELECT *
+		FROM messages
+		WHERE id = $1`
+
+	err := q.Get(&message, query, id)
+	if err != nil {
+		return message, err
+	}
+
+	return message, nil
+}
+
+// GetMessages retrieves a page of messages of the conversation sent since the given time
+// after the cursor, newest first.
+func (q *MessageQueries) GetMessages(
+	conversationID uuid.UUID,
+	since time.Time,
+	cursor *parameters.MessagesCursor,
+	count int,
+) ([]models.DBMessage, error) {
+	messages := []models.DBMessage{}
+
+	query := `SELECT *
+		FROM messages
+		WHERE conversation_id = $1
+		AND created_at >= $2
+		AND ($3::timestamptz IS NULL OR (created_at, id) < ($3::timestamptz, $4::uuid))
+		ORDER BY created_at DESC, id DESC
+		FETCH FIRST $5 ROWS ONLY`
+
+	var createdAt, id interface{}
+	if cursor != nil {
+		createdAt, id = cursor.CreatedAt, cursor.MessageID
+	}
+
+	err := q.Select(&messages, query, conversationID, since, createdAt, id, count)
+	if err != nil {
+		return messages, err
+	}
+
+	return messages, nil
+}
+
+// GetLastMessage retrieves the newest message of the conversation sent since the given time.
+func (q *MessageQueries) GetLastMessage(conversationID uuid.UUID, since time.Time) (models.DBMessage, error) {
+	message := models.DBMessage{}
+
+	query := `SELECT *
+		FROM messages
+		WHERE conversation_id = $1
+		AND created_at >= $2
+		ORDER BY created_at DESC, id DESC
+		FETCH FIRST 1 ROWS ONLY`
+
+	err := q.Get(&message, query, conversationID, since)
+	if err != nil {
+		return message, err
+	}
+
+	return message, nil
+}
+
+// CreateMessage sends the message to the conversation with the attachments uploaded by the sender.
+// The conversation is marked as read up to the message for the sender, and the message request is accepted
+// if the sender replies to it. Returns sql.ErrNoRows if any attachment is not found or already sent.
+func (q *MessageQueries) CreateMessage(m *models.DBMessage, attachmentIDs []uuid.UUID) error {
+	memberQuery := `UPDATE conversation_members
+		SET
+			status = 'accepted',
+			last_read_at = GREATEST(last_read_at, $3)
+		WHERE conversation_id = $1
+		AND user_id = $2`
+
+	attachmentsQuery := `UPDATE message_attachments
+		SET
+			message_id = $1
+		WHERE id = ANY($2::uuid[])
+		AND user_id = $3
+		AND message_id IS NULL`
+
+	tx, err := q.Beginx()
+	if err != nil {
+		return err
+	}
+
+	if err = insertMessage(tx, m); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if len(attachmentIDs) > 0 {
+		result, attachErr := tx.Exec(attachmentsQuery, m.ID, uuidStrings(attachmentIDs), m.SenderID)
+		if attachErr != nil {
+			_ = tx.Rollback()
+			return attachErr
+		}
+
+		if attached, _ := result.RowsAffected(); attached != int64(len(attachmentIDs)) {
+			_ = tx.Rollback()
+			return sql.ErrNoRows
+		}
+	}
+
+	if _, err = tx.Exec(memberQuery, m.ConversationID, m.SenderID, m.CreatedAt); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateMessage updates the content of the message.
+func (q *MessageQueries) UpdateMessage(m *models.DBMessage) error {
+	query := `UPDATE messages
+		SET
+			content = $2,
+			updated_at = $3
+		WHERE id = $1`
+
+	_, err := q.Exec(query, m.ID, m.Content, m.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteMessage deletes the message.
+func (q *MessageQueries) DeleteMessage(id uuid.UUID) error {
+	query := `DELETE FROM messages
+		WHERE id = $1`
+
+	_, err := q.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// insertMessage saves the message in the transaction.
+func insertMessage(tx *sqlx.Tx, m *models.DBMessage) error {
+	query := `INSERT INTO messages (id, conversation_id, sender_id, type, content, event, target_user_id,
+			created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+
+	_, err := tx.Exec(
+		query,
+		m.ID,
+		m.ConversationID,
+		m.SenderID,
+		m.Type,
+		m.Content,
+		m.Event,
+		m.TargetUserID,
+		m.CreatedAt,
+		m.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package queries
+
+import (
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// MutedWordQueries is struct for interacting with a database for muted word-related queries.
+type MutedWordQueries struct {
+	*sqlx.DB
+}
+
+// GetMutedWords retrieves muted words of the user not expired at the given time, newest first.
+func (q *MutedWordQueries) GetMutedWords(userID uuid.UUID, now time.Time) ([]models.DBMutedWord, error) {
+	mutedWords := []models.DBMutedWord{}
+
+	query := `SELECT *
+		FROM muted_words
+		WHERE user_id = $1
+		AND (expires_at IS NULL OR expires_at > $2)
+		ORDER BY created_at DESC, id DESC`
+
+	err := q.Select(&mutedWords, query, userID, now)
+	if err != nil {
+		return mutedWords, err
+	}
+
+	return mutedWords, nil
+}
+
+// GetActiveMutedWords retrieves muted words of the user applied in the scope and not expired at the given time.
+func (q *MutedWordQueries) GetActiveMutedWords(
+	userID uuid.UUID,
+	scope models.MutedWordScope,
+	now time.Time,
+) ([]models.DBMutedWord, error) {
+	mutedWords := []models.DBMutedWord{}
+
+	query := `SELECT *
+		FROM muted_words
+		WHERE user_id = $1
+		AND scopes ? $2
+		AND (expires_at IS NULL OR expires_at > $3)`
+
+	err := q.Select(&mutedWords, query, userID, scope, now)
+	if err != nil {
+		return mutedWords, err
+	}
+
+	return mutedWords, nil
+}
+
+// CreateMutedWord mutes the word for the user, expired words of the user are removed first.
+// Returns false if the user has already muted the maximum number of words.
+// The user row is locked, so concurrent requests can't exceed the limit together.
+func (q *MutedWordQueries) CreateMutedWord(b *models.DBMutedWord) (bool, error) {
+	lockQuery := `SELECT 1
+		FROM users
+		WHERE id = $1
+		FOR NO KEY UPDATE`
+
+	expiredQuery := `DELETE FROM muted_words
+		WHERE user_id = $1
+		AND expires_at <= $2`
+
+	query := `INSERT INTO muted_words (id, user_id, phrase, whole_word, scopes, action, expires_at, created_at)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8
+		FROM muted_words
+		WHERE user_id = $2
+		HAVING Count(*) < $9`
+
+	tx, err := q.Beginx()
+	if err != nil {
+		return false, err
+	}
+
+	if _, err = tx.Exec(lockQuery, b.UserID); err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	if _, err = tx.Exec(expiredQuery, b.UserID, b.CreatedAt); err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	result, err := tx.Exec(
+		query,
+		b.ID,
+		b.UserID,
+		b.Phrase,
+		b.WholeWord,
+		b.Scopes,
+		b.Action,
+		b.ExpiresAt,
+		b.CreatedAt,
+		configs.MutedWordsMaxCount,
+	)
+	if err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	created, err := result.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	return created > 0, tx.Commit()
+}
+
+// DeleteMutedWord unmutes the word of the user. Returns false if the word is not found.
+func (q *MutedWordQueries) DeleteMutedWord(userID uuid.UUID, id uuid.UUID) (bool, error) {
+	query := `DELETE FROM muted_words
+		WHERE id = $1 AND user_id = $2`
+
+	result, err := q.Exec(query, id, userID)
+	if err != nil {
+		return false, err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return deleted > 0, nil
+}
+
+// notMutedCondition returns a condition which skips posts of the given table hidden from the user
+// in the given query parameter by muted words of the feed scope. Own posts are never hidden.
+func notMutedCondition(postTable string, requesterParameter string) string {
+	return `AND NOT EXISTS (
+			SELECT 1
+			FROM muted_words
+			WHERE muted_words.user_id = ` + requesterParameter + `
+			AND ` + postTable + `.user_id <> ` + requesterParameter + `
+			AND muted_words.action = '` + string(models.HideMuted) + `'
+			AND muted_words.scopes ? '` + string(models.FeedScope) + `'
+			AND (muted_words.expires_at IS NULL OR muted_words.expires_at > now())
+			AND (
+				matches_muted_word(` + postTable + `.description, muted_words.phrase, muted_words.whole_word)
+				OR matches_muted_word(` + postTable + `.content_warning, muted_words.phrase, muted_words.whole_word)
+			)
+		)`
+}
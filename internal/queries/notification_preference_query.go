@@ -0,0 +1,193 @@
+package queries
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// NotificationPreferenceQueries is struct for interacting with a database
+// for notification preference-related queries.
+type NotificationPreferenceQueries struct {
+	*sqlx.DB
+}
+
+// GetNotificationOptOuts retrieves types and channels of notifications the user doesn't want to receive.
+func (q *NotificationPreferenceQueries) GetNotificationOptOuts(
+	userID uuid.UUID,
+) ([]models.NotificationPreference, error) {
+	optOuts := []models.NotificationPreference{}
+
+	query := `SELECT type, channel, false AS enabled
+		FROM notification_opt_outs
+		WHERE user_id = $1`
+
+	err := q.Select(&optOuts, query, userID)
+	if err != nil {
+		return optOuts, err
+	}
+
+	return optOuts, nil
+}
+
+// UpdateNotificationOptOuts opts the user out of notifications of disabled types in their channels
+// and back in for enabled ones. Queued push deliveries of types disabled for push are dropped.
+func (q *NotificationPreferenceQueries) UpdateNotificationOptOuts(
+	userID uuid.UUID,
+	preferences []models.NotificationPreference,
+) error {
+	optOutQuery := `INSERT INTO notification_opt_outs (user_id, type, channel)
+		VALUES ($1, $2, $3)
+			ON CONFLICT (user_id, type, channel) DO NOTHING`
+
+	deliveriesQuery := `DELETE FROM push_deliveries
+		USING notifications
+		WHERE push_deliveries.notification_id = notifications.id
+		AND notifications.user_id = $1
+		AND notifications.type = $2
+		AND $3 = 'push'`
+
+	optInQuery := `DELETE FROM notification_opt_outs
+		WHERE user_id = $1
+		AND type = $2
+		AND channel = $3`
+
+	tx, err := q.Beginx()
+	if err != nil {
+		return err
+	}
+
+	for _, preference := range preferences {
+		queries := []string{optInQuery}
+		if !preference.Enabled {
+			queries = []string{optOutQuery, deliveriesQuery}
+		}
+
+		for _, query := range queries {
+			if _, err = tx.Exec(query, userID, preference.Type, preference.Channel); err != nil {
+				_ = tx.Rollback()
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetNotificationSettings retrieves notification settings of the user, defaults if they are not changed.
+func (q *NotificationPreferenceQueries) GetNotificationSettings(
+	userID uuid.UUID,
+) (models.DBNotificationSettings, error) {
+	settings := models.DBNotificationSettings{}
+
+	query := `SELECT *
+		FROM notification_settings
+		WHERE user_id = $1`
+
+	err := q.Get(&settings, query, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.DefaultNotificationSettings(userID), nil
+	}
+
+	if err != nil {
+		return settings, err
+	}
+
+	return settings, nil
+}
+
+// UpdateNotificationSettings saves notification settings of the user.
+func (q *NotificationPreferenceQueries) UpdateNotificationSettings(b *models.DBNotificationSettings) error {
+	query := `INSERT INTO notification_settings (user_id, timezone, quiet_hours_start, quiet_hours_end, digest)
+		VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (user_id) DO
+		UPDATE
+			SET timezone = EXCLUDED.timezone,
+			quiet_hours_start = EXCLUDED.quiet_hours_start,
+			quiet_hours_end = EXCLUDED.quiet_hours_end,
+			digest = EXCLUDED.digest`
+
+	_, err := q.Exec(query, b.UserID, b.Timezone, b.QuietHoursStart, b.QuietHoursEnd, b.Digest)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// IsKnownTimezone reports whether the database knows the timezone with the given name.
+func (q *NotificationPreferenceQueries) IsKnownTimezone(name string) (bool, error) {
+	known := false
+
+	query := `SELECT EXISTS (
+			SELECT 1
+			FROM pg_timezone_names
+			WHERE name = $1
+		)`
+
+	err := q.Get(&known, query, name)
+	if err != nil {
+		return known, err
+	}
+
+	return known, nil
+}
+
+// ClaimDueDigests marks email digests due at the given time as sent and retrieves settings of their users
+// with the time of the previous digest, so other instances don't send them again. The digest is due when
+// the local time of the user reached the hour of digests and the period passed since the last digest,
+// less the tolerance for the job schedule.
+func (q *NotificationPreferenceQueries) ClaimDueDigests(
+	now time.Time,
+	hour int,
+	tolerance time.Duration,
+	count int,
+) ([]models.DBNotificationSettings, error) {
+	settings := []models.DBNotificationSettings{}
+
+	query := `WITH due AS (
+			SELECT user_id, last_digest_at
+			FROM notification_settings
+			WHERE digest <> 'off'
+			AND EXTRACT(hour FROM $1::timestamptz AT TIME ZONE timezone) >= $2
+			AND (
+				last_digest_at IS NULL
+				OR digest = 'daily' AND last_digest_at <= $3
+				OR digest = 'weekly' AND last_digest_at <= $4
+			)
+			ORDER BY last_digest_at NULLS FIRST
+			FETCH FIRST $5 ROWS ONLY
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE notification_settings
+		SET
+			last_digest_at = $1
+		FROM due
+		WHERE notification_settings.user_id = due.user_id
+		RETURNING
+			notification_settings.user_id,
+			notification_settings.timezone,
+			notification_settings.quiet_hours_start,
+			notification_settings.quiet_hours_end,
+			notification_settings.digest,
+			due.last_digest_at`
+
+	err := q.Select(
+		&settings,
+		query,
+		now,
+		hour,
+		now.Add(-models.DailyDigest.Period()+tolerance),
+		now.Add(-models.WeeklyDigest.Period()+tolerance),
+		count,
+	)
+	if err != nil {
+		return settings, err
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,241 @@
+package queries
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/MangriMen/Diverse-Back/internal/parameters"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// NotificationQueries is struct for interacting with a database for notification-related queries.
+type NotificationQueries struct {
+	*sqlx.DB
+}
+
+// CreateNotification notifies the user about the event caused by the actor. The event joins the unread
+// notification with the same group key if there is one. Users blocked by or blocking the notified user
+// cause no notifications. Returns the id of the created or joined notification,
+// uuid.Nil if the user is not notified.
+func (q *NotificationQueries) CreateNotification(n *models.DBNotification, actorID uuid.UUID) (uuid.UUID, error) {
+	notificationQuery := `INSERT INTO notifications (id, user_id, type, post_id, comment_id, group_key,
+			last_actor_id, created_at, updated_at)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, $8
+		WHERE 1 = 1
+		` + notBlockedCondition("$7", "$2") + `
+			ON CONFLICT (user_id, group_key) WHERE read_at IS NULL DO
+		UPDATE
+			SET last_actor_id = EXCLUDED.last_actor_id,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id`
+
+	actorQuery := `INSERT INTO notification_actors (notification_id, actor_id, created_at)
+		VALUES ($1, $2, $3)
+			ON CONFLICT (notification_id, actor_id) DO
+		UPDATE
+			SET created_at = EXCLUDED.created_at`
+
+	tx, err := q.Beginx()
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	var notificationID uuid.UUID
+
+	err = tx.Get(
+		&notificationID,
+		notificationQuery,
+		n.ID,
+		n.UserID,
+		n.Type,
+		n.PostID,
+		n.CommentID,
+		n.GroupKey,
+		actorID,
+		n.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return uuid.Nil, tx.Rollback()
+	}
+
+	if err != nil {
+		_ = tx.Rollback()
+		return uuid.Nil, err
+	}
+
+	if _, err = tx.Exec(actorQuery, notificationID, actorID, n.UpdatedAt); err != nil {
+		_ = tx.Rollback()
+		return uuid.Nil, err
+	}
+
+	return notificationID, tx.Commit()
+}
+
+// GetNotification retrieves the notification by the given id.
+func (q *NotificationQueries) GetNotification(id uuid.UUID) (models.DBNotification, error) {
+	notification := models.DBNotification{}
+
+	query := `SELECT *
+		FROM notifications
+		WHERE id = $1`
+
+	err := q.Get(&notification, query, id)
+	if err != nil {
+		return notification, err
+	}
+
+	return notification, nil
+}
+
+// GetNotifications retrieves a page of notifications of the user after the cursor, latest events first.
+// Notifications of types the user opted out of in the app are skipped.
+func (q *NotificationQueries) GetNotifications(
+	userID uuid.UUID,
+	unreadOnly bool,
+	cursor *parameters.NotificationsCursor,
+	count int,
+) ([]models.DBNotification, error) {
+	notifications := []models.DBNotification{}
+
+	query := `SELECT *
+		FROM notifications
+		WHERE user_id = $1
+		AND (NOT $2 OR read_at IS NULL)
+		AND type NOT IN (
+			SELECT type
+			FROM notification_opt_outs
+			WHERE user_id = $1
+			AND channel = 'in_app'
+		)
+		AND ($3::timestamptz IS NULL OR (updated_at, id) < ($3::timestamptz, $4::uuid))
+		ORDER BY updated_at DESC, id DESC
+		FETCH FIRST $5 ROWS ONLY`
+
+	var updatedAt, id interface{}
+	if cursor != nil {
+		updatedAt, id = cursor.UpdatedAt, cursor.NotificationID
+	}
+
+	err := q.Select(&notifications, query, userID, unreadOnly, updatedAt, id, count)
+	if err != nil {
+		return notifications, err
+	}
+
+	return notifications, nil
+}
+
+// GetDigestNotifications retrieves unread notifications of the user updated after the given time
+// for the email digest, unless the user opted out of emailing their type. Notifications with
+// the most actors go first.
+func (q *NotificationQueries) GetDigestNotifications(
+	userID uuid.UUID,
+	since time.Time,
+	count int,
+) ([]models.DBNotification, error) {
+	notifications := []models.DBNotification{}
+
+	query := `SELECT *
+		FROM notifications
+		WHERE user_id = $1
+		AND read_at IS NULL
+		AND updated_at > $2
+		AND type NOT IN (
+			SELECT type
+			FROM notification_opt_outs
+			WHERE user_id = $1
+			AND channel = 'email'
+		)
+		ORDER BY actors_count DESC, updated_at DESC
+		FETCH FIRST $3 ROWS ONLY`
+
+	err := q.Select(&notifications, query, userID, since, count)
+	if err != nil {
+		return notifications, err
+	}
+
+	return notifications, nil
+}
+
+// GetNotificationActors retrieves the last users who caused events of the notification, newest first.
+func (q *NotificationQueries) GetNotificationActors(notificationID uuid.UUID, count int) ([]models.DBUser, error) {
+	users := []models.DBUser{}
+
+	query := `SELECT users_view.*
+		FROM notification_actors
+		JOIN users_view ON users_view.id = notification_actors.actor_id
+		WHERE notification_actors.notification_id = $1
+		ORDER BY notification_actors.created_at DESC
+		FETCH FIRST $2 ROWS ONLY`
+
+	err := q.Select(&users, query, notificationID, count)
+	if err != nil {
+		return users, err
+	}
+
+	return users, nil
+}
+
+// GetUnreadNotificationsCount retrieves the number of unread notifications of the user shown in the app.
+func (q *NotificationQueries) GetUnreadNotificationsCount(userID uuid.UUID) (int, error) {
+	var count int
+
+	query := `SELECT Count(*)
+		FROM notifications
+		WHERE user_id = $1
+		AND read_at IS NULL
+		AND type NOT IN (
+			SELECT type
+			FROM notification_opt_outs
+			WHERE user_id = $1
+			AND channel = 'in_app'
+		)`
+
+	err := q.Get(&count, query, userID)
+	if err != nil {
+		return count, err
+	}
+
+	return count, nil
+}
+
+// ReadNotification marks the notification of the user as read.
+// Returns false if the notification is not found.
+func (q *NotificationQueries) ReadNotification(userID uuid.UUID, id uuid.UUID, readAt time.Time) (bool, error) {
+	query := `UPDATE notifications
+		SET
+			read_at = coalesce(read_at, $3)
+		WHERE id = $1
+		AND user_id = $2`
+
+	result, err := q.Exec(query, id, userID, readAt)
+	if err != nil {
+		return false, err
+	}
+
+	read, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return read > 0, nil
+}
+
+// ReadAllNotifications marks all notifications of the user updated before the given time as read.
+func (q *NotificationQueries) ReadAllNotifications(userID uuid.UUID, readAt time.Time) error {
+	query := `UPDATE notifications
+		SET
+			read_at = $2
+		WHERE user_id = $1
+		AND read_at IS NULL
+		AND updated_at <= $2`
+
+	_, err := q.Exec(query, userID, readAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
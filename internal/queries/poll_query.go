@@ -0,0 +1,193 @@
+package queries
+
+import (
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/samber/lo"
+)
+
+// PollQueries is struct for interacting with a database for poll-related queries.
+type PollQueries struct {
+	*sqlx.DB
+}
+
+// insertPoll creates the poll with the options in the order given in the transaction.
+func insertPoll(tx *sqlx.Tx, poll *models.DBPoll, options []models.DBPollOption) error {
+	pollQuery := `INSERT INTO polls (id, post_id, multiple, anonymous, show_results, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	optionsQuery := `INSERT INTO poll_options (id, poll_id, position, text)
+		SELECT option.id, $1, option.position, option.text
+		FROM unnest($2::uuid[], $3::text[]) WITH ORDINALITY AS option(id, text, position)`
+
+	ids := lo.Map(options, func(item models.DBPollOption, index int) string {
+		return item.ID.String()
+	})
+	texts := lo.Map(options, func(item models.DBPollOption, index int) string {
+		return item.Text
+	})
+
+	_, err := tx.Exec(
+		pollQuery,
+		poll.ID,
+		poll.PostID,
+		poll.Multiple,
+		poll.Anonymous,
+		poll.ShowResults,
+		poll.ExpiresAt,
+		poll.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(optionsQuery, poll.ID, ids, texts); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetPostPoll is used to fetch the poll attached to the post.
+func (q *PollQueries) GetPostPoll(postID uuid.UUID) (models.DBPoll, error) {
+	poll := models.DBPoll{}
+
+	query := `SELECT id, post_id, multiple, anonymous, show_results, voters_count, expires_at, closed_at, created_at
+		FROM polls
+		WHERE post_id = $1`
+
+	err := q.Get(&poll, query, postID)
+	if err != nil {
+		return poll, err
+	}
+
+	return poll, nil
+}
+
+// GetPollOptions is used to fetch options of the poll in the order of the poll.
+func (q *PollQueries) GetPollOptions(pollID uuid.UUID) ([]models.DBPollOption, error) {
+	options := []models.DBPollOption{}
+
+	query := `SELECT id, poll_id, position, text, votes
+		FROM poll_options
+		WHERE poll_id = $1
+		ORDER BY position`
+
+	err := q.Select(&options, query, pollID)
+
+	return options, err
+}
+
+// HasVotedInPoll returns whether the user has voted in the poll.
+func (q *PollQueries) HasVotedInPoll(pollID uuid.UUID, userID uuid.UUID) (bool, error) {
+	voted := false
+
+	query := `SELECT EXISTS (
+			SELECT 1
+			FROM poll_voters
+			WHERE poll_id = $1 AND user_id = $2
+		)`
+
+	err := q.Get(&voted, query, pollID, userID)
+
+	return voted, err
+}
+
+// GetPollVotes is used to fetch ids of the options chosen by the user.
+// Votes of anonymous polls are not kept, so the result is empty for them.
+func (q *PollQueries) GetPollVotes(pollID uuid.UUID, userID uuid.UUID) ([]uuid.UUID, error) {
+	votes := []uuid.UUID{}
+
+	query := `SELECT option_id
+		FROM poll_votes
+		WHERE poll_id = $1 AND user_id = $2`
+
+	err := q.Select(&votes, query, pollID, userID)
+
+	return votes, err
+}
+
+// VotePoll records the vote of the user for the options of the poll.
+// Returns false if the user has already voted or the poll is closed.
+func (q *PollQueries) VotePoll(
+	poll *models.DBPoll,
+	userID uuid.UUID,
+	optionIDs []uuid.UUID,
+	votedAt time.Time,
+) (bool, error) {
+	voterQuery := `INSERT INTO poll_voters (poll_id, user_id, voted_at)
+		SELECT id, $2, $3
+		FROM polls
+		WHERE id = $1
+		AND closed_at IS NULL
+		AND (expires_at IS NULL OR expires_at > $3)
+			ON CONFLICT DO NOTHING`
+
+	optionsQuery := `UPDATE poll_options
+		SET votes = votes + 1
+		WHERE poll_id = $1 AND id = ANY($2::uuid[])`
+
+	votesQuery := `INSERT INTO poll_votes (option_id, poll_id, user_id)
+		SELECT unnest($2::uuid[]), $1, $3`
+
+	pollQuery := `UPDATE polls
+		SET voters_count = voters_count + 1
+		WHERE id = $1`
+
+	ids := lo.Map(optionIDs, func(item uuid.UUID, index int) string {
+		return item.String()
+	})
+
+	tx, err := q.Beginx()
+	if err != nil {
+		return false, err
+	}
+
+	result, err := tx.Exec(voterQuery, poll.ID, userID, votedAt)
+	if err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	voted, err := result.RowsAffected()
+	if err != nil || voted == 0 {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	if _, err = tx.Exec(optionsQuery, poll.ID, ids); err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	if !poll.Anonymous {
+		if _, err = tx.Exec(votesQuery, poll.ID, ids, userID); err != nil {
+			_ = tx.Rollback()
+			return false, err
+		}
+	}
+
+	if _, err = tx.Exec(pollQuery, poll.ID); err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// CloseExpiredPolls closes polls which have expired by the given time.
+func (q *PollQueries) CloseExpiredPolls(now time.Time) error {
+	query := `UPDATE polls
+		SET closed_at = expires_at
+		WHERE closed_at IS NULL AND expires_at <= $1`
+
+	_, err := q.Exec(query, now)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -1,29 +1,39 @@
 package queries
 
 import (
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/configs"
 	"github.com/MangriMen/Diverse-Back/internal/models"
 	"github.com/MangriMen/Diverse-Back/internal/parameters"
+	"github.com/MangriMen/Diverse-Back/internal/search"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/samber/lo"
 )
 
 // PostQueries is struct for interacting with a database for post-related queries.
 type PostQueries struct {
 	*sqlx.DB
+
+	// Index is updated after posts are written to the database.
+	Index search.Index
 }
 
-// GetPostsCount is used to fetch posts count.
-func (q *PostQueries) GetPostsCount(postFromCondition string) (int, error) {
+// GetPostsCount is used to fetch the count of posts not hidden from the user by muted words.
+func (q *PostQueries) GetPostsCount(userID uuid.UUID, postFromCondition string) (int, error) {
 	postsCount := 0
 
 	query := `SELECT Count(*)
 		FROM posts_view
-		WHERE 1 = 1` +
+		WHERE 1 = 1
+		` + notMutedCondition("posts_view", "$1") +
 		"\n" + postFromCondition + "\n"
 
 	err := q.Get(
 		&postsCount,
 		query,
+		userID,
 	)
 	if err != nil {
 		return postsCount, err
@@ -32,8 +42,9 @@ func (q *PostQueries) GetPostsCount(postFromCondition string) (int, error) {
 	return postsCount, nil
 }
 
-// GetPosts is used to fetch posts.
+// GetPosts is used to fetch posts not hidden from the user by muted words.
 func (q *PostQueries) GetPosts(
+	userID uuid.UUID,
 	postsFetchRequestQuery *parameters.PostsFetchRequestQuery,
 	postFromCondition string,
 ) ([]models.DBPost, error) {
@@ -42,7 +53,8 @@ func (q *PostQueries) GetPosts(
 	query := `SELECT *
 		FROM posts_view
 		WHERE created_at < $1
-		AND id <> $2` +
+		AND id <> $2
+		` + notMutedCondition("posts_view", "$4") +
 		"\n" + postFromCondition + "\n" +
 		`ORDER BY created_at DESC
 		FETCH FIRST $3 ROWS ONLY`
@@ -53,6 +65,7 @@ func (q *PostQueries) GetPosts(
 		postsFetchRequestQuery.LastSeenPostCreatedAt,
 		postsFetchRequestQuery.LastSeenPostID,
 		postsFetchRequestQuery.Count,
+		userID,
 	)
 	if err != nil {
 		return posts, err
@@ -77,19 +90,91 @@ func (q *PostQueries) GetPost(id uuid.UUID) (models.DBPost, error) {
 	return post, nil
 }
 
-// CreatePost creates a new post at the database based on the given post object.
-func (q *PostQueries) CreatePost(b *models.DBPost) error {
-	query := `INSERT INTO posts
-		VALUES ($1, $2, $3, $4, $5, $6)
+// GetPostsByIDs is used to fetch posts with the given ids in the order of the ids, unknown ids are skipped.
+func (q *PostQueries) GetPostsByIDs(ids []uuid.UUID) ([]models.DBPost, error) {
+	posts := []models.DBPost{}
+
+	query := `SELECT *
+		FROM posts_view
+		WHERE id = ANY($1::uuid[])
+		ORDER BY array_position($1::uuid[], id)`
+
+	err := q.Select(&posts, query, uuidStrings(ids))
+	if err != nil {
+		return posts, err
+	}
+
+	return posts, nil
+}
+
+// GetPostsAfter is used to fetch posts ordered by id starting after the given id.
+func (q *PostQueries) GetPostsAfter(lastSeenPostID uuid.UUID, count int) ([]models.DBPost, error) {
+	posts := []models.DBPost{}
+
+	query := `SELECT *
+		FROM posts_view
+		WHERE id > $1
+		ORDER BY id
+		FETCH FIRST $2 ROWS ONLY`
+
+	err := q.Select(&posts, query, lastSeenPostID, count)
+	if err != nil {
+		return posts, err
+	}
+
+	return posts, nil
+}
+
+// CreatePost creates a new post at the database based on the given post object,
+// with the poll and its options if the poll is not nil.
+func (q *PostQueries) CreatePost(b *models.DBPost, poll *models.DBPoll, pollOptions []models.DBPollOption) error {
+	query := `INSERT INTO posts (id, user_id, content, description, likes, created_at, language,
+			content_warning, sensitive, comment_policy, markdown, entities)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12::jsonb)
 			ON CONFLICT (id) DO
 		UPDATE
 			SET deleted_at = NULL`
 
-	_, err := q.Exec(query, b.ID, b.UserID, b.Content, b.Description, b.Likes, b.CreatedAt)
+	tx, err := q.Beginx()
 	if err != nil {
 		return err
 	}
 
+	_, err = tx.Exec(
+		query,
+		b.ID,
+		b.UserID,
+		b.Content,
+		b.Description,
+		b.Likes,
+		b.CreatedAt,
+		b.Language,
+		b.ContentWarning,
+		b.Sensitive,
+		b.CommentPolicy,
+		b.Markdown,
+		b.Entities,
+	)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if poll != nil {
+		if err = insertPoll(tx, poll, pollOptions); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	updateIndex(q.Index, func(index search.Index) error {
+		return index.IndexPost(b)
+	})
+
 	return nil
 }
 
@@ -97,26 +182,71 @@ func (q *PostQueries) CreatePost(b *models.DBPost) error {
 func (q *UserQueries) UpdatePost(b *models.DBPost) error {
 	query := `UPDATE posts
 		SET
-			description = $2
+			description = $2,
+			comment_policy = $3,
+			markdown = $4,
+			entities = $5::jsonb
 		WHERE id = $1`
 
-	_, err := q.Exec(query, b.ID, b.Description)
+	_, err := q.Exec(query, b.ID, b.Description, b.CommentPolicy, b.Markdown, b.Entities)
 	if err != nil {
 		return err
 	}
 
+	updateIndex(q.Index, func(index search.Index) error {
+		return index.IndexPost(b)
+	})
+
 	return nil
 }
 
-// LikePost sets like the post by ID.
+// SetPostEntities saves entities of the post written before entities were stored.
+func (q *PostQueries) SetPostEntities(id uuid.UUID, entities models.Entities) error {
+	query := `UPDATE posts
+		SET
+			entities = $2::jsonb
+		WHERE id = $1
+		AND entities IS NULL`
+
+	_, err := q.Exec(query, id, entities)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdatePostSensitivity sets the content warning and the sensitive flag of the post.
+func (q *PostQueries) UpdatePostSensitivity(b *models.DBPost) error {
+	query := `UPDATE posts
+		SET
+			content_warning = $2,
+			sensitive = $3
+		WHERE id = $1`
+
+	_, err := q.Exec(query, b.ID, b.ContentWarning, b.Sensitive)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// LikePost sets the reaction of the user to the post, replacing the previous one.
+// The like time is kept when only the reaction is changed.
 func (q *PostQueries) LikePost(l *models.DBPostLike) error {
-	query := `INSERT INTO post_likes
-		VALUES ($1, $2, $3)
+	query := `INSERT INTO post_likes (id, post_id, user_id, reaction, created_at)
+		VALUES ($1, $2, $3, $4, $5)
 			ON CONFLICT(post_id, user_id) DO
 		UPDATE
-			SET deleted_at = NULL`
-
-	_, err := q.Exec(query, l.ID, l.PostID, l.UserID)
+			SET deleted_at = NULL,
+			reaction = EXCLUDED.reaction,
+			created_at = CASE
+				WHEN post_likes.deleted_at IS NULL THEN post_likes.created_at
+				ELSE EXCLUDED.created_at
+			END`
+
+	_, err := q.Exec(query, l.ID, l.PostID, l.UserID, l.Reaction, l.CreatedAt)
 	if err != nil {
 		return err
 	}
@@ -124,12 +254,13 @@ func (q *PostQueries) LikePost(l *models.DBPostLike) error {
 	return nil
 }
 
-// UnlikePost sets like the post by ID.
+// UnlikePost removes the reaction of the user to the post.
 func (q *PostQueries) UnlikePost(l *models.DBPostLike) error {
 	query := `UPDATE post_likes
 		SET
 			deleted_at = now()
-		WHERE post_id = $1 AND user_id = $2`
+		WHERE post_id = $1 AND user_id = $2
+		AND deleted_at IS NULL`
 
 	_, err := q.Exec(query, l.PostID, l.UserID)
 	if err != nil {
@@ -139,25 +270,29 @@ func (q *PostQueries) UnlikePost(l *models.DBPostLike) error {
 	return nil
 }
 
-// GetPostIsLiked gets status of like for given post and user.
-func (q *PostQueries) GetPostIsLiked(postID uuid.UUID, userID uuid.UUID) (bool, error) {
-	likesCount := 0
+// GetPostReaction gets the reaction of the user to the post, empty if there is no reaction.
+func (q *PostQueries) GetPostReaction(postID uuid.UUID, userID uuid.UUID) (string, error) {
+	reactions := []string{}
 
-	query := `SELECT Count(*)
+	query := `SELECT reaction
 		FROM post_likes_view
 		WHERE post_id = $1 AND user_id = $2`
 
-	err := q.Get(&likesCount, query, postID, userID)
-	if err != nil {
-		return false, err
+	err := q.Select(&reactions, query, postID, userID)
+	if err != nil || len(reactions) == 0 {
+		return "", err
 	}
 
-	return likesCount > 0, nil
+	return reactions[0], nil
 }
 
-// DeletePost deletes post based on the given ID.
+// DeletePost deletes post based on the given ID and unpins it.
 func (q *PostQueries) DeletePost(id uuid.UUID) error {
-	query := `UPDATE posts
+	query := `WITH unpinned AS (
+			DELETE FROM pinned_posts
+			WHERE post_id = $1
+		)
+		UPDATE posts
 		SET
 			deleted_at = now()
 		WHERE id = $1`
@@ -167,5 +302,320 @@ func (q *PostQueries) DeletePost(id uuid.UUID) error {
 		return err
 	}
 
+	updateIndex(q.Index, func(index search.Index) error {
+		return index.DeletePost(id)
+	})
+
 	return nil
 }
+
+// GetPinnedPosts is used to fetch pinned posts of the user in the pin order.
+func (q *PostQueries) GetPinnedPosts(userID uuid.UUID) ([]models.DBPost, error) {
+	posts := []models.DBPost{}
+
+	query := `SELECT posts_view.*
+		FROM pinned_posts
+		JOIN posts_view ON posts_view.id = pinned_posts.post_id
+		WHERE pinned_posts.user_id = $1
+		ORDER BY pinned_posts.position`
+
+	err := q.Select(&posts, query, userID)
+	if err != nil {
+		return posts, err
+	}
+
+	return posts, nil
+}
+
+// PinPost pins the post after the already pinned posts of the user.
+// Returns false if the user has already pinned the maximum number of posts.
+// The user row is locked, so concurrent requests can't exceed the limit together.
+func (q *PostQueries) PinPost(userID uuid.UUID, postID uuid.UUID) (bool, error) {
+	lockQuery := `SELECT 1
+		FROM users
+		WHERE id = $1
+		FOR NO KEY UPDATE`
+
+	query := `INSERT INTO pinned_posts (post_id, user_id, position)
+		SELECT $1, $2, coalesce(max(position), 0) + 1
+		FROM pinned_posts
+		WHERE user_id = $2
+		HAVING Count(*) < $3`
+
+	tx, err := q.Beginx()
+	if err != nil {
+		return false, err
+	}
+
+	if _, err = tx.Exec(lockQuery, userID); err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	result, err := tx.Exec(query, postID, userID, configs.PostMaxPinned)
+	if err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	pinned, err := result.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	return pinned > 0, tx.Commit()
+}
+
+// UnpinPost unpins the post of the user. Returns false if the post is not pinned.
+func (q *PostQueries) UnpinPost(userID uuid.UUID, postID uuid.UUID) (bool, error) {
+	query := `DELETE FROM pinned_posts
+		WHERE post_id = $1 AND user_id = $2`
+
+	result, err := q.Exec(query, postID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	unpinned, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return unpinned > 0, nil
+}
+
+// ReorderPinnedPosts sets the pin order of the user posts to the order of the given ids.
+// Returns false if the ids are not exactly the pinned posts of the user.
+func (q *PostQueries) ReorderPinnedPosts(userID uuid.UUID, postIDs []uuid.UUID) (bool, error) {
+	query := `UPDATE pinned_posts
+		SET
+			position = ordered.position
+		FROM unnest($2::uuid[]) WITH ORDINALITY AS ordered(post_id, position)
+		WHERE pinned_posts.post_id = ordered.post_id
+		AND pinned_posts.user_id = $1
+		AND (
+			SELECT array_agg(post_id ORDER BY post_id)
+			FROM pinned_posts
+			WHERE user_id = $1
+		) = (
+			SELECT array_agg(id ORDER BY id)
+			FROM unnest($2::uuid[]) AS id
+		)`
+
+	ids := lo.Map(postIDs, func(item uuid.UUID, index int) string {
+		return item.String()
+	})
+
+	result, err := q.Exec(query, userID, ids)
+	if err != nil {
+		return false, err
+	}
+
+	reordered, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return reordered > 0, nil
+}
+
+// hiddenPostsCursorCondition cuts hidden posts up to the given cursor, $2 - $3 is hide time and id.
+func hiddenPostsCursorCondition(hiddenAtColumn string) string {
+	return `AND ($2::timestamptz IS NULL OR (` + hiddenAtColumn + `, id) < ($2::timestamptz, $3::uuid))`
+}
+
+// hiddenPostsCursorArgs returns arguments $2 - $3 of the hidden posts query.
+func hiddenPostsCursorArgs(cursor *parameters.HiddenPostsCursor) []interface{} {
+	if cursor == nil {
+		return []interface{}{nil, nil}
+	}
+
+	return []interface{}{cursor.HiddenAt, cursor.ID}
+}
+
+// GetArchivedPost is used to fetch the archived post by ID.
+func (q *PostQueries) GetArchivedPost(id uuid.UUID) (models.DBArchivedPost, error) {
+	post := models.DBArchivedPost{}
+
+	query := `SELECT *
+		FROM archived_posts_view
+		WHERE id = $1`
+
+	err := q.Get(&post, query, id)
+	if err != nil {
+		return post, err
+	}
+
+	return post, nil
+}
+
+// GetArchivedPosts is used to fetch archived posts of the user, the latest archived first.
+func (q *PostQueries) GetArchivedPosts(
+	userID uuid.UUID,
+	cursor *parameters.HiddenPostsCursor,
+	count int,
+) ([]models.DBArchivedPost, error) {
+	posts := []models.DBArchivedPost{}
+
+	query := `SELECT *
+		FROM archived_posts_view
+		WHERE user_id = $1
+		` + hiddenPostsCursorCondition("archived_at") + `
+		ORDER BY archived_at DESC, id DESC
+		FETCH FIRST $4 ROWS ONLY`
+
+	err := q.Select(&posts, query, append(append([]interface{}{userID}, hiddenPostsCursorArgs(cursor)...), count)...)
+	if err != nil {
+		return posts, err
+	}
+
+	return posts, nil
+}
+
+// GetDeletedPosts is used to fetch posts of the user deleted after the given time,
+// the latest deleted first.
+func (q *PostQueries) GetDeletedPosts(
+	userID uuid.UUID,
+	deletedAfter time.Time,
+	cursor *parameters.HiddenPostsCursor,
+	count int,
+) ([]models.DBDeletedPost, error) {
+	posts := []models.DBDeletedPost{}
+
+	query := `SELECT *
+		FROM deleted_posts_view
+		WHERE user_id = $1
+		AND deleted_at > $5
+		` + hiddenPostsCursorCondition("deleted_at") + `
+		ORDER BY deleted_at DESC, id DESC
+		FETCH FIRST $4 ROWS ONLY`
+
+	err := q.Select(
+		&posts,
+		query,
+		append(append([]interface{}{userID}, hiddenPostsCursorArgs(cursor)...), count, deletedAfter)...,
+	)
+	if err != nil {
+		return posts, err
+	}
+
+	return posts, nil
+}
+
+// ArchivePost hides the post of the user from everyone but the user and unpins it.
+// Returns false if the post is not found or already archived.
+func (q *PostQueries) ArchivePost(userID uuid.UUID, id uuid.UUID) (bool, error) {
+	query := `WITH archived AS (
+			UPDATE posts
+			SET
+				archived_at = now()
+			WHERE id = $1
+			AND user_id = $2
+			AND deleted_at IS NULL
+			AND archived_at IS NULL
+			RETURNING id
+		),
+		unpinned AS (
+			DELETE FROM pinned_posts
+			WHERE post_id IN (SELECT id FROM archived)
+		)
+		SELECT Count(*) > 0
+		FROM archived`
+
+	archived := false
+
+	err := q.Get(&archived, query, id, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return archived, nil
+}
+
+// UnarchivePost shows the archived post of the user to everyone again.
+// Returns false if the post is not found or not archived.
+func (q *PostQueries) UnarchivePost(userID uuid.UUID, id uuid.UUID) (bool, error) {
+	query := `UPDATE posts
+		SET
+			archived_at = NULL
+		WHERE id = $1
+		AND user_id = $2
+		AND deleted_at IS NULL
+		AND archived_at IS NOT NULL`
+
+	result, err := q.Exec(query, id, userID)
+	if err != nil {
+		return false, err
+	}
+
+	unarchived, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return unarchived > 0, nil
+}
+
+// RestorePost restores the post of the user deleted after the given time from the trash.
+// Returns false if the post is not found in the trash.
+func (q *PostQueries) RestorePost(userID uuid.UUID, id uuid.UUID, deletedAfter time.Time) (bool, error) {
+	posts := []models.DBPost{}
+
+	query := `UPDATE posts
+		SET
+			deleted_at = NULL
+		WHERE id = $1
+		AND user_id = $2
+		AND deleted_at > $3
+		RETURNING id, user_id, content, description, likes, created_at,
+			language::text AS language, reactions, view_count, content_warning, sensitive, comment_policy,
+			markdown, entities`
+
+	err := q.Select(&posts, query, id, userID, deletedAfter)
+	if err != nil {
+		return false, err
+	}
+
+	for i := range posts {
+		updateIndex(q.Index, func(index search.Index) error {
+			return index.IndexPost(&posts[i])
+		})
+	}
+
+	return len(posts) > 0, nil
+}
+
+// PurgeDeletedPosts permanently deletes posts deleted before the given time
+// with their comments and likes.
+func (q *PostQueries) PurgeDeletedPosts(deletedBefore time.Time) error {
+	queries := []string{
+		`DELETE FROM comment_likes
+		WHERE comment_id IN (
+			SELECT comments.id
+			FROM comments
+			JOIN posts ON posts.id = comments.post_id
+			WHERE posts.deleted_at < $1
+		)`,
+		`DELETE FROM comments
+		WHERE post_id IN (SELECT id FROM posts WHERE deleted_at < $1)`,
+		`DELETE FROM post_likes
+		WHERE post_id IN (SELECT id FROM posts WHERE deleted_at < $1)`,
+		`DELETE FROM posts
+		WHERE deleted_at < $1`,
+	}
+
+	tx, err := q.Beginx()
+	if err != nil {
+		return err
+	}
+
+	for _, query := range queries {
+		if _, err = tx.Exec(query, deletedBefore); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
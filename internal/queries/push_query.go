@@ -0,0 +1,226 @@
+package queries
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// PushQueries is struct for interacting with a database for push notification-related queries.
+type PushQueries struct {
+	*sqlx.DB
+}
+
+// GetPushSubscriptions retrieves push subscriptions of the user, newest first.
+func (q *PushQueries) GetPushSubscriptions(userID uuid.UUID) ([]models.DBPushSubscription, error) {
+	subscriptions := []models.DBPushSubscription{}
+
+	query := `SELECT *
+		FROM push_subscriptions
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC`
+
+	err := q.Select(&subscriptions, query, userID)
+	if err != nil {
+		return subscriptions, err
+	}
+
+	return subscriptions, nil
+}
+
+// GetPushSubscription retrieves the push subscription by the given id.
+func (q *PushQueries) GetPushSubscription(id uuid.UUID) (models.DBPushSubscription, error) {
+	subscription := models.DBPushSubscription{}
+
+	query := `SELECT *
+		FROM push_subscriptions
+		WHERE id = $1`
+
+	err := q.Get(&subscription, query, id)
+	if err != nil {
+		return subscription, err
+	}
+
+	return subscription, nil
+}
+
+// CreatePushSubscription registers the push subscription of the user or updates keys of the registered one.
+// The endpoint registered by another user is moved to the user, because the browser is signed in again.
+// The id and the creation time of the registered subscription are set to the object.
+// Returns false if the user has already registered the maximum number of subscriptions.
+func (q *PushQueries) CreatePushSubscription(b *models.DBPushSubscription) (bool, error) {
+	movedQuery := `DELETE FROM push_subscriptions
+		WHERE endpoint = $1
+		AND user_id <> $2`
+
+	query := `INSERT INTO push_subscriptions (id, user_id, endpoint, p256dh, auth, created_at)
+		SELECT $1, $2, $3, $4, $5, $6
+		WHERE (
+			SELECT Count(*)
+			FROM push_subscriptions
+			WHERE user_id = $2
+			AND endpoint <> $3
+		) < $7
+			ON CONFLICT (endpoint) DO
+		UPDATE
+			SET p256dh = EXCLUDED.p256dh,
+			auth = EXCLUDED.auth
+		RETURNING id, created_at`
+
+	tx, err := q.Beginx()
+	if err != nil {
+		return false, err
+	}
+
+	if _, err = tx.Exec(movedQuery, b.Endpoint, b.UserID); err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	err = tx.QueryRowx(
+		query,
+		b.ID,
+		b.UserID,
+		b.Endpoint,
+		b.P256dh,
+		b.Auth,
+		b.CreatedAt,
+		configs.PushSubscriptionsMaxCount,
+	).Scan(&b.ID, &b.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, tx.Rollback()
+	}
+
+	if err != nil {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// DeletePushSubscription deletes the push subscription of the user with its queued deliveries.
+// Returns false if the user has no subscription with the given id.
+func (q *PushQueries) DeletePushSubscription(userID uuid.UUID, id uuid.UUID) (bool, error) {
+	query := `DELETE FROM push_subscriptions
+		WHERE id = $1
+		AND user_id = $2`
+
+	result, err := q.Exec(query, id, userID)
+	if err != nil {
+		return false, err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return deleted > 0, nil
+}
+
+// DeleteExpiredPushSubscription deletes the push subscription the push service no longer accepts.
+func (q *PushQueries) DeleteExpiredPushSubscription(id uuid.UUID) error {
+	query := `DELETE FROM push_subscriptions
+		WHERE id = $1`
+
+	_, err := q.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// EnqueuePushDeliveries queues delivery of the notification to each push subscription of its user
+// at the given time, unless the user opted out of pushing its type. The notification already queued
+// to the subscription isn't queued again, the delivery sends its latest state.
+func (q *PushQueries) EnqueuePushDeliveries(
+	notification *models.DBNotification,
+	now time.Time,
+	deliverAt time.Time,
+) error {
+	query := `INSERT INTO push_deliveries (id, subscription_id, notification_id, next_attempt_at, created_at)
+		SELECT gen_random_uuid(), id, $2, $5, $4
+		FROM push_subscriptions
+		WHERE user_id = $1
+		AND NOT EXISTS (
+			SELECT 1
+			FROM notification_opt_outs
+			WHERE user_id = $1
+			AND type = $3
+			AND channel = 'push'
+		)
+			ON CONFLICT (subscription_id, notification_id) DO NOTHING`
+
+	_, err := q.Exec(query, notification.UserID, notification.ID, notification.Type, now, deliverAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ClaimPushDeliveries retrieves deliveries due at the given time and postpones them until the lease ends,
+// so other instances don't send them meanwhile. The attempt is counted for each claimed delivery.
+func (q *PushQueries) ClaimPushDeliveries(
+	now time.Time,
+	leaseUntil time.Time,
+	count int,
+) ([]models.DBPushDelivery, error) {
+	deliveries := []models.DBPushDelivery{}
+
+	query := `UPDATE push_deliveries
+		SET
+			attempts = attempts + 1,
+			next_attempt_at = $2
+		WHERE id IN (
+			SELECT id
+			FROM push_deliveries
+			WHERE next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			FETCH FIRST $3 ROWS ONLY
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`
+
+	err := q.Select(&deliveries, query, now, leaseUntil, count)
+	if err != nil {
+		return deliveries, err
+	}
+
+	return deliveries, nil
+}
+
+// RetryPushDelivery schedules the next attempt of the delivery.
+func (q *PushQueries) RetryPushDelivery(id uuid.UUID, nextAttemptAt time.Time) error {
+	query := `UPDATE push_deliveries
+		SET
+			next_attempt_at = $2
+		WHERE id = $1`
+
+	_, err := q.Exec(query, id, nextAttemptAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeletePushDelivery deletes the delivery which is sent or can't be sent.
+func (q *PushQueries) DeletePushDelivery(id uuid.UUID) error {
+	query := `DELETE FROM push_deliveries
+		WHERE id = $1`
+
+	_, err := q.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package queries
+
+import (
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// RealtimeQueries is struct for interacting with a database for real-time event-related queries.
+type RealtimeQueries struct {
+	*sqlx.DB
+}
+
+// PublishRealtimeEvent saves the event, the database notifies all listening instances about it.
+func (q *RealtimeQueries) PublishRealtimeEvent(topic string, eventType string, data []byte) error {
+	query := `INSERT INTO realtime_events (topic, type, data)
+		VALUES ($1, $2, $3::jsonb)`
+
+	_, err := q.Exec(query, topic, eventType, string(data))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PublishEphemeralRealtimeEvent notifies all listening instances about the event without saving it,
+// so it gets id 0 and isn't sent to clients resuming the subscription.
+func (q *RealtimeQueries) PublishEphemeralRealtimeEvent(topic string, eventType string, data []byte) error {
+	query := `SELECT pg_notify('realtime_events', json_build_object(
+			'id', 0,
+			'topic', $1::text,
+			'type', $2::text,
+			'data', $3::jsonb,
+			'created_at', now()
+		)::text)`
+
+	_, err := q.Exec(query, topic, eventType, string(data))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PublishFeedRealtimeEvent saves the event to feed topics of the author followers.
+// The topic format gets the id of the follower. Like the timeline fan out,
+// it's skipped for authors with more followers than the limit.
+func (q *RealtimeQueries) PublishFeedRealtimeEvent(
+	authorID uuid.UUID,
+	topicFormat string,
+	eventType string,
+	data []byte,
+) error {
+	query := `WITH followers AS (
+			SELECT user_id
+			FROM user_relations_view
+			WHERE relation_user_id = $1 AND type = 'following'
+		)
+		INSERT INTO realtime_events (topic, type, data)
+		SELECT format($2, user_id), $3, $4::jsonb
+		FROM followers
+		WHERE (SELECT Count(*) FROM followers) <= $5`
+
+	_, err := q.Exec(query, authorID, topicFormat, eventType, string(data), configs.TimelineFanOutLimit)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetRealtimeEvents retrieves events of the topics published after the event with the given id, oldest first.
+func (q *RealtimeQueries) GetRealtimeEvents(topics []string, afterID int64, count int) ([]models.RealtimeEvent, error) {
+	events := []models.RealtimeEvent{}
+
+	query := `SELECT *
+		FROM realtime_events
+		WHERE topic = ANY($1::text[])
+		AND id > $2
+		ORDER BY id
+		FETCH FIRST $3 ROWS ONLY`
+
+	err := q.Select(&events, query, topics, afterID, count)
+	if err != nil {
+		return events, err
+	}
+
+	return events, nil
+}
+
+// DeleteExpiredRealtimeEvents deletes events published before the given time.
+func (q *RealtimeQueries) DeleteExpiredRealtimeEvents(before time.Time) error {
+	query := `DELETE FROM realtime_events
+		WHERE created_at < $1`
+
+	_, err := q.Exec(query, before)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -124,6 +124,26 @@ func (q *RelationQueries) GetRelationStatus(
 	return relations, nil
 }
 
+// GetBlockedUserIDs is used to fetch ids of users blocked by or blocking the given user.
+func (q *RelationQueries) GetBlockedUserIDs(userID uuid.UUID) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+
+	query := `SELECT relation_user_id
+		FROM user_relations_view
+		WHERE user_id = $1 AND type = 'blocked'
+		UNION
+		SELECT user_id
+		FROM user_relations_view
+		WHERE relation_user_id = $1 AND type = 'blocked'`
+
+	err := q.Select(&ids, query, userID)
+	if err != nil {
+		return ids, err
+	}
+
+	return ids, nil
+}
+
 // AddRelation is used to add new relation with given parameters.
 func (q *RelationQueries) AddRelation(r *models.DBRelation) error {
 	query := `INSERT INTO user_relations
@@ -164,3 +184,17 @@ func (q *RelationQueries) DeleteRelation(
 
 	return nil
 }
+
+// notBlockedCondition returns a condition which skips rows where the user in the given column
+// blocked, or was blocked by, the user in the given query parameter.
+func notBlockedCondition(userColumn string, requesterParameter string) string {
+	return `AND NOT EXISTS (
+			SELECT 1
+			FROM user_relations_view AS blocks
+			WHERE blocks.type = 'blocked'
+			AND (
+				blocks.user_id = ` + requesterParameter + ` AND blocks.relation_user_id = ` + userColumn + `
+				OR blocks.user_id = ` + userColumn + ` AND blocks.relation_user_id = ` + requesterParameter + `
+			)
+		)`
+}
@@ -0,0 +1,171 @@
+package queries
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/helpers"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/MangriMen/Diverse-Back/internal/parameters"
+	"github.com/MangriMen/Diverse-Back/internal/search"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/samber/lo"
+)
+
+// SearchQueries is struct for interacting with a database for full-text search queries.
+type SearchQueries struct {
+	*sqlx.DB
+}
+
+// searchCursorCondition cuts results up to the given cursor, $9 - $11 is rank, creation time and id.
+const searchCursorCondition = `WHERE $9::real IS NULL
+	OR (rank, created_at, id) < ($9::real, $10::timestamptz, $11::uuid)`
+
+// searchCutFilter orders results by relevance and limits its count, $12 is count.
+const searchCutFilter = `ORDER BY rank DESC, created_at DESC, id DESC
+	FETCH FIRST $12 ROWS ONLY`
+
+// SearchPosts is used to fetch posts which description matches the search query,
+// ordered by relevance. Posts of users blocked by or blocking the requester are skipped.
+func (q *SearchQueries) SearchPosts(
+	userID uuid.UUID,
+	searchRequestQuery *parameters.SearchRequestQuery,
+	cursor *parameters.SearchCursor,
+) ([]models.DBPostSearchResult, error) {
+	results := []models.DBPostSearchResult{}
+
+	query := `SELECT *
+		FROM (
+			SELECT posts_view.*,
+				ts_rank_cd(posts.search_vector, search_query) AS rank,
+				ts_headline(posts.language, posts_view.description, search_query, $3) AS headline
+			FROM posts_view
+			JOIN posts USING (id)
+			CROSS JOIN LATERAL websearch_to_tsquery(posts.language, $1) AS search_query
+			WHERE posts.search_vector @@ (` + searchIndexQuery(searchRequestQuery.Language) + `)
+			AND posts.search_vector @@ search_query
+			AND ($4 = '' OR posts_view.language = $4)
+			AND ($5::uuid IS NULL OR posts_view.user_id = $5)
+			AND ($6::timestamptz IS NULL OR posts_view.created_at >= $6)
+			AND ($7::timestamptz IS NULL OR posts_view.created_at < $7)
+			AND ($8::boolean IS NULL OR (posts_view.content <> '') = $8)
+			` + notBlockedCondition("posts_view.user_id", "$2") + `
+		) AS results
+		` + searchCursorCondition + `
+		` + searchCutFilter
+
+	err := q.Select(
+		&results,
+		query,
+		append(
+			searchArgs(userID, searchRequestQuery),
+			searchCursorArgs(cursor, searchRequestQuery.Count)...,
+		)...,
+	)
+	if err != nil {
+		return results, err
+	}
+
+	return results, nil
+}
+
+// SearchComments is used to fetch comments which content matches the search query,
+// ordered by relevance. Comments under deleted posts and comments or posts of users
+// blocked by or blocking the requester are skipped.
+func (q *SearchQueries) SearchComments(
+	userID uuid.UUID,
+	searchRequestQuery *parameters.SearchRequestQuery,
+	cursor *parameters.SearchCursor,
+) ([]models.DBCommentSearchResult, error) {
+	results := []models.DBCommentSearchResult{}
+
+	query := `SELECT *
+		FROM (
+			SELECT comments_view.*,
+				ts_rank_cd(comments.search_vector, search_query) AS rank,
+				ts_headline(comments.language, comments_view.content, search_query, $3) AS headline
+			FROM comments_view
+			JOIN comments USING (id)
+			JOIN posts_view ON posts_view.id = comments_view.post_id
+			CROSS JOIN LATERAL websearch_to_tsquery(comments.language, $1) AS search_query
+			WHERE comments.search_vector @@ (` + searchIndexQuery(searchRequestQuery.Language) + `)
+			AND comments.search_vector @@ search_query
+			AND ($4 = '' OR comments_view.language = $4)
+			AND ($5::uuid IS NULL OR comments_view.user_id = $5)
+			AND ($6::timestamptz IS NULL OR comments_view.created_at >= $6)
+			AND ($7::timestamptz IS NULL OR comments_view.created_at < $7)
+			AND ($8::boolean IS NULL OR (posts_view.content <> '') = $8)
+			AND NOT comments_view.tombstoned
+			AND NOT comments_view.hidden
+			` + notBlockedCondition("comments_view.user_id", "$2") + `
+			` + notBlockedCondition("posts_view.user_id", "$2") + `
+		) AS results
+		` + searchCursorCondition + `
+		` + searchCutFilter
+
+	err := q.Select(
+		&results,
+		query,
+		append(
+			searchArgs(userID, searchRequestQuery),
+			searchCursorArgs(cursor, searchRequestQuery.Count)...,
+		)...,
+	)
+	if err != nil {
+		return results, err
+	}
+
+	return results, nil
+}
+
+// searchIndexQuery returns the query $1 in the given search language, or in all of them combined.
+// Unlike the query in the language of each row, it's the same for all rows, so the search vector index is used,
+// and matched rows are checked with the query in their language.
+func searchIndexQuery(language string) string {
+	languages := configs.GetSearchLanguages()
+	if language != "" {
+		languages = []string{language}
+	}
+
+	return strings.Join(lo.Map(languages, func(item string, index int) string {
+		return fmt.Sprintf("websearch_to_tsquery('%s', $1)", item)
+	}), " || ")
+}
+
+// searchArgs returns arguments $1 - $8 of the search query.
+func searchArgs(userID uuid.UUID, searchRequestQuery *parameters.SearchRequestQuery) []interface{} {
+	return []interface{}{
+		searchRequestQuery.Query,
+		userID,
+		configs.SearchHeadlineOptions,
+		searchRequestQuery.Language,
+		helpers.PtrOrNil(searchRequestQuery.AuthorID),
+		helpers.PtrOrNil(searchRequestQuery.From),
+		helpers.PtrOrNil(searchRequestQuery.To),
+		searchRequestQuery.HasMedia,
+	}
+}
+
+// searchCursorArgs returns arguments $9 - $12 of the search query.
+func searchCursorArgs(cursor *parameters.SearchCursor, count int) []interface{} {
+	if cursor == nil {
+		return []interface{}{nil, nil, nil, count}
+	}
+
+	return []interface{}{cursor.Rank, cursor.CreatedAt, cursor.ID, count}
+}
+
+// updateIndex applies the change to the search index. The error is only logged,
+// because the change is already saved to the database and the index can be rebuilt.
+func updateIndex(index search.Index, apply func(index search.Index) error) {
+	if index == nil {
+		return
+	}
+
+	if err := apply(index); err != nil {
+		log.Printf("Search index is not updated. Reason: %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package queries
+
+import (
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/MangriMen/Diverse-Back/internal/parameters"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// TimelineQueries is struct for interacting with a database for home timeline queries.
+type TimelineQueries struct {
+	*sqlx.DB
+}
+
+// timelinePostsQuery selects posts of the user timeline, $1 is user id. Posts of authors with too many
+// followers are not fanned out, so they are read from followed authors posts.
+const timelinePostsQuery = `SELECT posts_view.*
+		FROM timelines
+		JOIN posts_view ON posts_view.id = timelines.post_id
+		WHERE timelines.user_id = $1
+		UNION ALL
+		SELECT posts_view.*
+		FROM user_relations_view AS followings
+		JOIN posts ON posts.user_id = followings.relation_user_id AND NOT posts.fanned_out
+		JOIN posts_view ON posts_view.id = posts.id
+		WHERE followings.user_id = $1 AND followings.type = 'following'`
+
+// GetTimelinePostsCount is used to fetch the user timeline posts count
+// skipping posts hidden by the sensitive content preference of the user.
+func (q *TimelineQueries) GetTimelinePostsCount(
+	userID uuid.UUID,
+	sensitiveContent models.SensitiveContentPreference,
+) (int, error) {
+	postsCount := 0
+
+	query := `SELECT Count(*)
+		FROM (` + timelinePostsQuery + `) AS timeline_posts
+		WHERE 1 = 1
+		` + notBlockedCondition("timeline_posts.user_id", "$1") + `
+		` + notMutedCondition("timeline_posts", "$1") + `
+		` + notSensitiveCondition("timeline_posts", "$1", "$2")
+
+	err := q.Get(&postsCount, query, userID, sensitiveContent)
+	if err != nil {
+		return postsCount, err
+	}
+
+	return postsCount, nil
+}
+
+// GetTimelinePosts is used to fetch the user timeline posts created before the last seen post,
+// newest first, skipping posts hidden by the sensitive content preference of the user.
+func (q *TimelineQueries) GetTimelinePosts(
+	userID uuid.UUID,
+	postsFetchRequestQuery *parameters.PostsFetchRequestQuery,
+	sensitiveContent models.SensitiveContentPreference,
+) ([]models.DBPost, error) {
+	posts := []models.DBPost{}
+
+	query := `SELECT *
+		FROM (` + timelinePostsQuery + `) AS timeline_posts
+		WHERE (created_at, id) < ($2, $3)
+		` + notBlockedCondition("timeline_posts.user_id", "$1") + `
+		` + notMutedCondition("timeline_posts", "$1") + `
+		` + notSensitiveCondition("timeline_posts", "$1", "$5") + `
+		ORDER BY created_at DESC, id DESC
+		FETCH FIRST $4 ROWS ONLY`
+
+	err := q.Select(
+		&posts,
+		query,
+		userID,
+		postsFetchRequestQuery.LastSeenPostCreatedAt,
+		postsFetchRequestQuery.LastSeenPostID,
+		postsFetchRequestQuery.Count,
+		sensitiveContent,
+	)
+	if err != nil {
+		return posts, err
+	}
+
+	return posts, nil
+}
+
+// FanOutPost adds the new post to timelines of the author followers.
+// Nothing is done if the author has too many followers,
+// then the post is read by followers from the posts table.
+func (q *TimelineQueries) FanOutPost(post *models.DBPost) error {
+	query := `WITH followers AS (
+			SELECT user_id
+			FROM user_relations_view
+			WHERE relation_user_id = $2 AND type = 'following'
+		),
+		fanned_out AS (
+			INSERT INTO timelines (user_id, post_id, author_id, created_at)
+			SELECT user_id, $1, $2, $3
+			FROM followers
+			WHERE (SELECT Count(*) FROM followers) <= $4
+				ON CONFLICT DO NOTHING
+		)
+		UPDATE posts
+		SET
+			fanned_out = true
+		WHERE id = $1
+		AND (SELECT Count(*) FROM followers) <= $4`
+
+	_, err := q.Exec(query, post.ID, post.UserID, post.CreatedAt, configs.TimelineFanOutLimit)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// BackfillTimeline adds the latest fanned out posts of the author to the user timeline.
+func (q *TimelineQueries) BackfillTimeline(userID uuid.UUID, authorID uuid.UUID) error {
+	query := `INSERT INTO timelines (user_id, post_id, author_id, created_at)
+		SELECT $1, id, user_id, created_at
+		FROM posts
+		WHERE user_id = $2
+		AND fanned_out
+		AND deleted_at IS NULL
+		ORDER BY created_at DESC
+		FETCH FIRST $3 ROWS ONLY
+			ON CONFLICT DO NOTHING`
+
+	_, err := q.Exec(query, userID, authorID, configs.TimelineBackfillCount)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RemoveFromTimeline removes all posts of the author from the user timeline.
+func (q *TimelineQueries) RemoveFromTimeline(userID uuid.UUID, authorID uuid.UUID) error {
+	query := `DELETE FROM timelines
+		WHERE user_id = $1
+		AND author_id = $2`
+
+	_, err := q.Exec(query, userID, authorID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// notSensitiveCondition returns a condition which skips sensitive posts of the given table
+// if the sensitive content preference in the given query parameter hides them. Own posts
+// of the user in the given query parameter are never hidden.
+func notSensitiveCondition(postTable string, requesterParameter string, preferenceParameter string) string {
+	return `AND (` + preferenceParameter + ` <> '` + string(models.HideSensitive) + `'
+			OR NOT ` + postTable + `.sensitive
+			OR ` + postTable + `.user_id = ` + requesterParameter + `)`
+}
@@ -2,6 +2,7 @@ package queries
 
 import (
 	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/MangriMen/Diverse-Back/internal/search"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
@@ -9,6 +10,9 @@ import (
 // UserQueries is struct for interacting with a database for user-related queries.
 type UserQueries struct {
 	*sqlx.DB
+
+	// Index is updated after users are written to the database.
+	Index search.Index
 }
 
 // GetUsers is used to fetch users.
@@ -74,6 +78,39 @@ func (q *UserQueries) GetUserByUsername(username string) (models.DBUser, error)
 	return user, nil
 }
 
+// GetUsersByUsernames retrieves the users whose usernames case-insensitively match the given ones.
+func (q *UserQueries) GetUsersByUsernames(usernames []string) ([]models.DBUser, error) {
+	users := []models.DBUser{}
+
+	query := `SELECT *
+		FROM users_view
+		WHERE lower(username) = ANY($1::text[])`
+
+	err := q.Select(&users, query, usernames)
+	if err != nil {
+		return users, err
+	}
+
+	return users, nil
+}
+
+// GetUsersByIDs retrieves the users with the given ids in the order of the ids, unknown ids are skipped.
+func (q *UserQueries) GetUsersByIDs(ids []uuid.UUID) ([]models.DBUser, error) {
+	users := []models.DBUser{}
+
+	query := `SELECT *
+		FROM users_view
+		WHERE id = ANY($1::uuid[])
+		ORDER BY array_position($1::uuid[], id)`
+
+	err := q.Select(&users, query, uuidStrings(ids))
+	if err != nil {
+		return users, err
+	}
+
+	return users, nil
+}
+
 // CreateUser creates a new user at the database based on the given user object.
 func (q *UserQueries) CreateUser(b *models.DBUser) error {
 	query := `INSERT INTO users
@@ -87,6 +124,10 @@ func (q *UserQueries) CreateUser(b *models.DBUser) error {
 		return err
 	}
 
+	updateIndex(q.Index, func(index search.Index) error {
+		return index.IndexUser(b)
+	})
+
 	return nil
 }
 
@@ -118,14 +159,19 @@ func (q *UserQueries) UpdateUser(b *models.DBUser) error {
 
 	queryUserInfo := `UPDATE user_info
 		SET
-			about = $2
+			about = $2,
+			sensitive_content = $3
 		WHERE id = $1`
 
-	_, err = q.Exec(queryUserInfo, b.ID, b.About)
+	_, err = q.Exec(queryUserInfo, b.ID, b.About, b.SensitiveContent)
 	if err != nil {
 		return err
 	}
 
+	updateIndex(q.Index, func(index search.Index) error {
+		return index.IndexUser(b)
+	})
+
 	return nil
 }
 
@@ -141,5 +187,9 @@ func (q *UserQueries) DeleteUser(id uuid.UUID) error {
 		return err
 	}
 
+	updateIndex(q.Index, func(index search.Index) error {
+		return index.DeleteUser(id)
+	})
+
 	return nil
 }
@@ -0,0 +1,151 @@
+package queries
+
+import (
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/samber/lo"
+)
+
+// ViewQueries is struct for interacting with a database for post impressions queries.
+type ViewQueries struct {
+	*sqlx.DB
+}
+
+// TrackPostViews records views of the posts by the user. Repeated views in the same
+// window and views of own posts are skipped.
+func (q *ViewQueries) TrackPostViews(userID uuid.UUID, postIDs []uuid.UUID, viewedAt time.Time) error {
+	query := `INSERT INTO post_view_events (post_id, user_id, window_start, viewed_at)
+		SELECT id, $2, date_bin(make_interval(secs => $3), $4::timestamptz, timestamptz 'epoch'), $4
+		FROM posts_view
+		WHERE id = ANY($1::uuid[])
+		AND user_id <> $2
+			ON CONFLICT DO NOTHING`
+
+	ids := lo.Map(postIDs, func(item uuid.UUID, index int) string {
+		return item.String()
+	})
+
+	_, err := q.Exec(query, ids, userID, configs.PostViewWindow.Seconds(), viewedAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RollupPostViews moves view events of finished windows into hourly counts
+// and increases view counts of the posts. Viewers are counted once per hour.
+func (q *ViewQueries) RollupPostViews() error {
+	query := `WITH events AS (
+			DELETE FROM post_view_events
+			WHERE window_start < date_bin(make_interval(secs => $1), now(), timestamptz 'epoch')
+			RETURNING post_id, user_id, viewed_at
+		),
+		new_viewers AS (
+			INSERT INTO post_viewers (post_id, user_id, first_viewed_at)
+			SELECT post_id, user_id, min(viewed_at)
+			FROM events
+			GROUP BY post_id, user_id
+				ON CONFLICT DO NOTHING
+		),
+		new_hourly_viewers AS (
+			INSERT INTO post_viewers_hourly (post_id, hour, user_id)
+			SELECT DISTINCT post_id, date_trunc('hour', viewed_at), user_id
+			FROM events
+				ON CONFLICT DO NOTHING
+			RETURNING post_id, hour
+		),
+		hourly_views AS (
+			SELECT post_id, date_trunc('hour', viewed_at) AS hour, Count(*) AS views
+			FROM events
+			GROUP BY post_id, hour
+		),
+		hourly_viewers AS (
+			SELECT post_id, hour, Count(*) AS viewers
+			FROM new_hourly_viewers
+			GROUP BY post_id, hour
+		),
+		hourly AS (
+			INSERT INTO post_views_hourly (post_id, hour, views, unique_viewers)
+			SELECT post_id, hour, coalesce(views, 0), coalesce(viewers, 0)
+			FROM hourly_views
+			FULL JOIN hourly_viewers USING (post_id, hour)
+				ON CONFLICT (post_id, hour) DO
+			UPDATE
+				SET views = post_views_hourly.views + EXCLUDED.views,
+				unique_viewers = post_views_hourly.unique_viewers + EXCLUDED.unique_viewers
+		)
+		UPDATE posts
+		SET
+			view_count = view_count + counts.views
+		FROM (
+			SELECT post_id, Count(*) AS views
+			FROM events
+			GROUP BY post_id
+		) AS counts
+		WHERE posts.id = counts.post_id`
+
+	_, err := q.Exec(query, configs.PostViewWindow.Seconds())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetPostInsights is used to fetch total statistics of the post and its activity
+// by hours in the given period. Archived posts have insights too.
+func (q *ViewQueries) GetPostInsights(postID uuid.UUID, from time.Time, to time.Time) (models.PostInsights, error) {
+	insights := models.PostInsights{Hours: []models.PostInsightsHour{}}
+
+	query := `SELECT posts.view_count AS views,
+			(SELECT Count(*) FROM post_viewers WHERE post_id = $1) AS unique_viewers,
+			(
+				SELECT Count(DISTINCT user_id)
+				FROM post_viewers_hourly
+				WHERE post_id = $1 AND hour >= date_trunc('hour', $2::timestamptz) AND hour < $3
+			) AS period_unique_viewers,
+			posts.likes,
+			(SELECT Count(*) FROM comments_view WHERE post_id = $1) AS comments
+		FROM posts
+		WHERE id = $1
+		AND deleted_at IS NULL`
+
+	err := q.Get(&insights, query, postID, from, to)
+	if err != nil {
+		return insights, err
+	}
+
+	query = `SELECT hour,
+			sum(views) AS views,
+			sum(unique_viewers) AS unique_viewers,
+			sum(likes) AS likes,
+			sum(comments) AS comments
+		FROM (
+			SELECT hour, views, unique_viewers, 0 AS likes, 0 AS comments
+			FROM post_views_hourly
+			WHERE post_id = $1
+			UNION ALL
+			SELECT date_trunc('hour', created_at), 0, 0, 1, 0
+			FROM post_likes_view
+			WHERE post_id = $1
+			UNION ALL
+			SELECT date_trunc('hour', created_at), 0, 0, 0, 1
+			FROM comments_view
+			WHERE post_id = $1
+		) AS activity
+		WHERE hour >= date_trunc('hour', $2::timestamptz) AND hour < $3
+		GROUP BY hour
+		ORDER BY hour`
+
+	err = q.Select(&insights.Hours, query, postID, from, to)
+	if err != nil {
+		return insights, err
+	}
+
+	return insights, nil
+}
@@ -0,0 +1,372 @@
+package realtime
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/helpers/posthelpers"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/google/uuid"
+)
+
+// Enum for message type.
+const (
+	SubscribeMessage   = "subscribe"
+	UnsubscribeMessage = "unsubscribe"
+	PingMessage        = "ping"
+	SubscribedMessage  = "subscribed"
+	ResetMessage       = "reset"
+	EventMessage       = "event"
+	PongMessage        = "pong"
+	ErrorMessage       = "error"
+	CloseMessage       = "close"
+)
+
+// Constants for errors sent to the client.
+const (
+	invalidMessageError = "invalid message"
+	forbiddenTopicError = "topic is not available"
+	topicsLimitError    = "too many topics"
+)
+
+// closeReason is the reason the connection of the client is closed for.
+type closeReason int
+
+// Enum for close reason.
+const (
+	// The connection failed or was closed by the client
+	disconnectedClose closeReason = iota
+	// The client doesn't receive events fast enough
+	slowConsumerClose
+	// The server is shutting down
+	shutdownClose
+)
+
+// String returns the description of the reason sent to the client.
+func (r closeReason) String() string {
+	switch r {
+	case disconnectedClose:
+		return ""
+	case slowConsumerClose:
+		return "too many events are not received"
+	case shutdownClose:
+		return "server is shutting down"
+	default:
+		return ""
+	}
+}
+
+// ClientMessage is the message sent by the client.
+type ClientMessage struct {
+	// Kind of the message: subscribe, unsubscribe or ping
+	Type string `json:"type"`
+
+	// Topic to subscribe to or unsubscribe from
+	Topic string `json:"topic,omitempty"`
+
+	// Id of the last received event of the topic to resume the subscription from
+	LastEventID int64 `json:"last_event_id,omitempty"`
+}
+
+// ServerMessage is the message sent to the client.
+type ServerMessage struct {
+	// Kind of the message: subscribed, reset, event, pong, error or close
+	Type string `json:"type"`
+
+	// Topic of the message
+	Topic string `json:"topic,omitempty"`
+
+	// Event pushed to the topic, only for event messages
+	Event *models.RealtimeEvent `json:"event,omitempty"`
+
+	// Description of the error or the close reason
+	Message string `json:"message,omitempty"`
+}
+
+// subscription is the state of the topic subscribed by the client.
+type subscription struct {
+	// Id of the last event queued to the client
+	lastEventID int64
+
+	// Whether missed events are being sent, live events are kept in pending meanwhile
+	replaying bool
+	pending   []models.RealtimeEvent
+}
+
+// Client is the connection of the authenticated user, independent of the transport.
+// Messages queued to send are written by the transport until done is closed.
+type Client struct {
+	mutex sync.Mutex
+
+	userID uuid.UUID
+	db     *database.Queries
+
+	subscriptions map[string]*subscription
+
+	send      chan ServerMessage
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// Reason of closing, set before done is closed
+	reason closeReason
+	// Closes the connection of the transport, if it isn't closed by returning from the write loop
+	closeConn func(reason closeReason)
+}
+
+// newClient creates the client of the user and registers it in the hub,
+// the client is closed at once if the server is shutting down.
+func newClient(userID uuid.UUID, db *database.Queries, closeConn func(reason closeReason)) *Client {
+	client := &Client{
+		userID:        userID,
+		db:            db,
+		subscriptions: map[string]*subscription{},
+		send:          make(chan ServerMessage, configs.RealtimeSendBufferSize),
+		done:          make(chan struct{}),
+		closeConn:     closeConn,
+	}
+
+	if !shared.Register(client) {
+		client.close(shutdownClose)
+	}
+
+	return client
+}
+
+// detach closes the client, unsubscribes it from all topics and removes it from the hub.
+func (c *Client) detach() {
+	c.close(disconnectedClose)
+
+	c.mutex.Lock()
+	for topic := range c.subscriptions {
+		shared.Unsubscribe(topic, c)
+	}
+	c.subscriptions = map[string]*subscription{}
+	c.mutex.Unlock()
+
+	shared.Unregister(c)
+}
+
+// CanSubscribe reports whether the user is allowed to receive events of the topic.
+// Topics of the user are available only to the user, comments and likes to any user
+// the post is visible to.
+func CanSubscribe(userID uuid.UUID, topic string, db *database.Queries) bool {
+	kind, id, err := ParseTopic(topic)
+	if err != nil {
+		return false
+	}
+
+	switch kind {
+	case NotificationsTopic, FollowersTopic, FeedTopic, MessagesTopic:
+		return id == userID
+	case CommentsTopic, LikesTopic:
+		post, postErr := db.GetPost(id)
+		if postErr != nil {
+			return false
+		}
+
+		sensitiveFilter, filterErr := posthelpers.NewSensitiveContentFilter(userID, db)
+		if filterErr != nil {
+			return false
+		}
+
+		return posthelpers.CheckVisibility(post, sensitiveFilter, db) == nil
+	default:
+		return false
+	}
+}
+
+// subscribe subscribes the client to the topics and sends events published after the given one.
+func (c *Client) subscribe(topics []string, lastEventID int64) {
+	subscribed := make([]string, 0, len(topics))
+
+	for _, topic := range topics {
+		if !CanSubscribe(c.userID, topic, c.db) {
+			c.queue(ServerMessage{Type: ErrorMessage, Topic: topic, Message: forbiddenTopicError})
+			continue
+		}
+
+		c.mutex.Lock()
+		if c.closed() {
+			c.mutex.Unlock()
+			return
+		}
+
+		if _, found := c.subscriptions[topic]; !found && len(c.subscriptions) >= configs.RealtimeMaxTopics {
+			c.mutex.Unlock()
+			c.queue(ServerMessage{Type: ErrorMessage, Topic: topic, Message: topicsLimitError})
+			continue
+		}
+
+		// The hub is updated under the mutex, so the client detached meanwhile isn't left subscribed
+		c.subscriptions[topic] = &subscription{lastEventID: lastEventID, replaying: lastEventID > 0}
+		shared.Subscribe(topic, c)
+		c.mutex.Unlock()
+
+		c.queue(ServerMessage{Type: SubscribedMessage, Topic: topic})
+
+		subscribed = append(subscribed, topic)
+	}
+
+	if lastEventID > 0 && len(subscribed) != 0 {
+		c.replay(subscribed, lastEventID)
+	}
+}
+
+// replay sends missed events of the topics followed by live events received meanwhile.
+// Subscriptions are reset if too many events are missed or they are not available.
+// Missed events are sent waiting for the transport, so they can exceed the queue size.
+func (c *Client) replay(topics []string, lastEventID int64) {
+	events, err := c.db.GetRealtimeEvents(topics, lastEventID, configs.RealtimeReplayLimit)
+	if err != nil || len(events) == configs.RealtimeReplayLimit {
+		events = []models.RealtimeEvent{}
+
+		for _, topic := range topics {
+			c.queue(ServerMessage{Type: ResetMessage, Topic: topic})
+		}
+	}
+
+	for i := range events {
+		if !c.sendMissedEvent(events[i]) {
+			return
+		}
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	pending := []models.RealtimeEvent{}
+	for _, topic := range topics {
+		current, found := c.subscriptions[topic]
+		if !found {
+			continue
+		}
+
+		pending = append(pending, current.pending...)
+		current.replaying = false
+		current.pending = nil
+	}
+
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].ID < pending[j].ID
+	})
+
+	for i := range pending {
+		if current, found := c.subscriptions[pending[i].Topic]; found {
+			c.queueEventLocked(current, pending[i])
+		}
+	}
+}
+
+// sendMissedEvent sends the missed event waiting for the queue to have space.
+// Returns false if the client is closed.
+func (c *Client) sendMissedEvent(event models.RealtimeEvent) bool {
+	c.mutex.Lock()
+	current, found := c.subscriptions[event.Topic]
+	if !found || event.ID <= current.lastEventID {
+		c.mutex.Unlock()
+		return true
+	}
+	current.lastEventID = event.ID
+	c.mutex.Unlock()
+
+	select {
+	case c.send <- ServerMessage{Type: EventMessage, Topic: event.Topic, Event: &event}:
+		return true
+	case <-c.done:
+		return false
+	}
+}
+
+// unsubscribe stops sending events of the topic to the client.
+func (c *Client) unsubscribe(topic string) {
+	c.mutex.Lock()
+	delete(c.subscriptions, topic)
+	c.mutex.Unlock()
+
+	shared.Unsubscribe(topic, c)
+}
+
+// deliver queues the live event of the subscribed topic. Ephemeral events
+// are dropped while missed events are replayed, they're outdated by the end of the replay.
+func (c *Client) deliver(event models.RealtimeEvent) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	current, found := c.subscriptions[event.Topic]
+	if !found {
+		return
+	}
+
+	if event.Ephemeral() {
+		if !current.replaying {
+			c.queueLocked(ServerMessage{Type: EventMessage, Topic: event.Topic, Event: &event})
+		}
+
+		return
+	}
+
+	if current.replaying {
+		current.pending = append(current.pending, event)
+		return
+	}
+
+	c.queueEventLocked(current, event)
+}
+
+// queueEventLocked queues the event unless it is already queued. Ids of saved events
+// follow the order of commit, so lower ids are already queued. The mutex must be held.
+func (c *Client) queueEventLocked(current *subscription, event models.RealtimeEvent) {
+	if event.ID <= current.lastEventID {
+		return
+	}
+
+	current.lastEventID = event.ID
+	c.queueLocked(ServerMessage{Type: EventMessage, Topic: event.Topic, Event: &event})
+}
+
+// queue queues the message to the client.
+func (c *Client) queue(message ServerMessage) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.queueLocked(message)
+}
+
+// queueLocked queues the message without blocking. The client which doesn't receive
+// fast enough to keep the queue from overflowing is closed, it can resume from
+// the last received events after reconnecting. The mutex must be held.
+func (c *Client) queueLocked(message ServerMessage) {
+	if c.closed() {
+		return
+	}
+
+	select {
+	case c.send <- message:
+	default:
+		go c.close(slowConsumerClose)
+	}
+}
+
+// closed reports whether the client is closed.
+func (c *Client) closed() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// close closes the client once with the reason.
+func (c *Client) close(reason closeReason) {
+	c.closeOnce.Do(func() {
+		c.reason = reason
+		close(c.done)
+
+		if c.closeConn != nil {
+			c.closeConn(reason)
+		}
+	})
+}
@@ -0,0 +1,107 @@
+// Package realtime provides the WebSocket and Server-Sent Events gateways which push events to subscribed clients.
+// Events are published through the database, so every backend instance receives all of them.
+package realtime
+
+import (
+	"sync"
+
+	"github.com/MangriMen/Diverse-Back/internal/models"
+)
+
+//nolint:gochecknoglobals // subscriptions live in the process and are shared by all connections
+var shared = NewHub()
+
+// Hub keeps connected clients of this instance and topics they are subscribed to.
+type Hub struct {
+	mutex sync.RWMutex
+
+	clients map[*Client]struct{}
+	topics  map[string]map[*Client]struct{}
+
+	// Whether clients are closed, because the server is shutting down
+	closing bool
+}
+
+// NewHub creates a hub without clients.
+func NewHub() *Hub {
+	return &Hub{
+		clients: map[*Client]struct{}{},
+		topics:  map[string]map[*Client]struct{}{},
+	}
+}
+
+// Register adds the connected client. Returns false if the hub is closing.
+func (h *Hub) Register(client *Client) bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.closing {
+		return false
+	}
+
+	h.clients[client] = struct{}{}
+
+	return true
+}
+
+// Unregister removes the disconnected client, it must be unsubscribed from all topics.
+func (h *Hub) Unregister(client *Client) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	delete(h.clients, client)
+}
+
+// CloseAll closes connections of all clients, including connecting later,
+// so they reconnect to other instances.
+func (h *Hub) CloseAll() {
+	h.mutex.Lock()
+	h.closing = true
+	clients := make([]*Client, 0, len(h.clients))
+	for client := range h.clients {
+		clients = append(clients, client)
+	}
+	h.mutex.Unlock()
+
+	for _, client := range clients {
+		client.close(shutdownClose)
+	}
+}
+
+// Subscribe adds the client to subscribers of the topic.
+func (h *Hub) Subscribe(topic string, client *Client) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.topics[topic] == nil {
+		h.topics[topic] = map[*Client]struct{}{}
+	}
+
+	h.topics[topic][client] = struct{}{}
+}
+
+// Unsubscribe removes the client from subscribers of the topic.
+func (h *Hub) Unsubscribe(topic string, client *Client) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	delete(h.topics[topic], client)
+
+	if len(h.topics[topic]) == 0 {
+		delete(h.topics, topic)
+	}
+}
+
+// Broadcast delivers the event to subscribers of its topic.
+func (h *Hub) Broadcast(event models.RealtimeEvent) {
+	h.mutex.RLock()
+	clients := make([]*Client, 0, len(h.topics[event.Topic]))
+	for client := range h.topics[event.Topic] {
+		clients = append(clients, client)
+	}
+	h.mutex.RUnlock()
+
+	for _, client := range clients {
+		client.deliver(event)
+	}
+}
@@ -0,0 +1,80 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+)
+
+// eventsChannel is the channel of database notifications about published events.
+const eventsChannel = "realtime_events"
+
+// Start listens for events published by all instances and delivers them to subscribers
+// of this instance. Returns function which closes connected clients, stops listening
+// and waits for the listener to finish.
+func Start() func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		listen(ctx)
+	}()
+
+	return func() {
+		shared.CloseAll()
+		cancel()
+		<-stopped
+	}
+}
+
+// listen receives notifications until the context is canceled,
+// the connection is reopened after the interval if it fails.
+func listen(ctx context.Context) {
+	for {
+		if err := receive(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Realtime events are not received. Reason: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(configs.RealtimeReconnectInterval):
+		}
+	}
+}
+
+// receive opens the listening connection and broadcasts received events until it fails.
+func receive(ctx context.Context) error {
+	conn, err := database.PostgreSQLListenConnection(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = conn.Close(context.Background())
+	}()
+
+	if _, err = conn.Exec(ctx, "LISTEN "+eventsChannel); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		event := models.RealtimeEvent{}
+		if err = json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			log.Printf("Realtime event is skipped. Reason: %v", err)
+			continue
+		}
+
+		shared.Broadcast(event)
+	}
+}
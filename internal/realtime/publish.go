@@ -0,0 +1,198 @@
+package realtime
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/google/uuid"
+)
+
+// Publish publishes the event with the data to subscribers of the topic on all instances.
+// The error is only logged, because the change is already saved and clients can refetch it.
+func Publish(topic string, eventType string, data interface{}, db *database.Queries) {
+	payload, err := json.Marshal(data)
+	if err == nil {
+		err = db.PublishRealtimeEvent(topic, eventType, payload)
+	}
+
+	if err != nil {
+		log.Printf("Realtime event is not published. Reason: %v", err)
+	}
+}
+
+// PublishEphemeral publishes the event with the data to subscribers of the topic on all instances
+// without keeping it, for short-lived state like typing indicators.
+func PublishEphemeral(topic string, eventType string, data interface{}, db *database.Queries) {
+	payload, err := json.Marshal(data)
+	if err == nil {
+		err = db.PublishEphemeralRealtimeEvent(topic, eventType, payload)
+	}
+
+	if err != nil {
+		log.Printf("Realtime event is not published. Reason: %v", err)
+	}
+}
+
+// PublishNotification publishes the new or updated notification with the unread count of the user.
+func PublishNotification(userID uuid.UUID, notificationID uuid.UUID, db *database.Queries) {
+	unreadCount, err := db.GetUnreadNotificationsCount(userID)
+	if err != nil {
+		log.Printf("Realtime event is not published. Reason: %v", err)
+		return
+	}
+
+	Publish(UserNotificationsTopic(userID), NotificationEvent, map[string]interface{}{
+		"notification_id": notificationID,
+		"unread_count":    unreadCount,
+	}, db)
+}
+
+// PublishNotificationsRead publishes the unread count of the user after notifications are read.
+func PublishNotificationsRead(userID uuid.UUID, db *database.Queries) {
+	unreadCount, err := db.GetUnreadNotificationsCount(userID)
+	if err != nil {
+		log.Printf("Realtime event is not published. Reason: %v", err)
+		return
+	}
+
+	Publish(UserNotificationsTopic(userID), NotificationsReadEvent, map[string]interface{}{
+		"unread_count": unreadCount,
+	}, db)
+}
+
+// PublishFollower publishes the new follower to the followers topic of the followed user.
+func PublishFollower(relation models.DBRelation, db *database.Queries) {
+	Publish(UserFollowersTopic(relation.RelationUserID), FollowerAddedEvent, map[string]interface{}{
+		"user_id": relation.UserID,
+	}, db)
+}
+
+// PublishFeedPost publishes the new post to feed topics of followers of its author.
+func PublishFeedPost(post models.DBPost, db *database.Queries) {
+	payload, err := json.Marshal(map[string]interface{}{
+		"post_id":   post.ID,
+		"author_id": post.UserID,
+	})
+	if err == nil {
+		err = db.PublishFeedRealtimeEvent(post.UserID, feedTopicFormat(), FeedPostEvent, payload)
+	}
+
+	if err != nil {
+		log.Printf("Realtime event is not published. Reason: %v", err)
+	}
+}
+
+// PublishComment publishes the change of the comment to the comment stream of its post.
+func PublishComment(eventType string, comment models.DBComment, db *database.Queries) {
+	Publish(PostCommentsTopic(comment.PostID), eventType, map[string]interface{}{
+		"comment_id":        comment.ID,
+		"parent_comment_id": comment.ParentCommentID,
+	}, db)
+}
+
+// PublishPostLikes publishes reaction counts of the post.
+func PublishPostLikes(post models.DBPost, db *database.Queries) {
+	Publish(PostLikesTopic(post.ID), PostLikesEvent, map[string]interface{}{
+		"post_id":   post.ID,
+		"likes":     post.Likes,
+		"reactions": post.Reactions,
+	}, db)
+}
+
+// PublishCommentLikes publishes reaction counts of the comment to the likes topic of its post.
+func PublishCommentLikes(comment models.DBComment, db *database.Queries) {
+	Publish(PostLikesTopic(comment.PostID), CommentLikesEvent, map[string]interface{}{
+		"comment_id": comment.ID,
+		"likes":      comment.Likes,
+		"reactions":  comment.Reactions,
+	}, db)
+}
+
+// PublishMessage publishes the change of the message to messages topics of members of its conversation,
+// except members who declined the conversation.
+func PublishMessage(eventType string, message models.DBMessage, db *database.Queries) {
+	members, err := db.GetConversationMembers(message.ConversationID)
+	if err != nil {
+		log.Printf("Realtime event is not published. Reason: %v", err)
+		return
+	}
+
+	for _, member := range members {
+		if member.Status == models.DeclinedConversation {
+			continue
+		}
+
+		Publish(UserMessagesTopic(member.UserID), eventType, map[string]interface{}{
+			"conversation_id": message.ConversationID,
+			"message_id":      message.ID,
+			"sender_id":       message.SenderID,
+		}, db)
+	}
+}
+
+// PublishConversationRead publishes the unread count of the conversation to the messages topic of the member
+// after the member read it, to update other devices of the member.
+func PublishConversationRead(conversation models.DBMemberConversation, userID uuid.UUID, db *database.Queries) {
+	Publish(UserMessagesTopic(userID), ConversationReadEvent, map[string]interface{}{
+		"conversation_id": conversation.ID,
+		"unread_count":    conversation.UnreadCount,
+	}, db)
+}
+
+// PublishConversationLeft publishes to the messages topic of the user that the user is no longer
+// a member of the conversation, after the user left or was removed from it.
+func PublishConversationLeft(conversationID uuid.UUID, userID uuid.UUID, db *database.Queries) {
+	Publish(UserMessagesTopic(userID), ConversationLeftEvent, map[string]interface{}{
+		"conversation_id": conversationID,
+	}, db)
+}
+
+// PublishTyping publishes the ephemeral event that the member is typing to messages topics
+// of other members of the conversation, except members who declined it.
+// Clients show the indicator until the timeout after the last event or the message of the member.
+func PublishTyping(conversationID uuid.UUID, userID uuid.UUID, db *database.Queries) {
+	publishToOtherMembers(conversationID, userID, TypingEvent, map[string]interface{}{
+		"conversation_id": conversationID,
+		"user_id":         userID,
+		"timeout":         configs.TypingIndicatorTimeout.Milliseconds(),
+	}, db)
+}
+
+// PublishReadReceipt publishes the ephemeral event that the member read messages of the conversation
+// up to the given time to messages topics of other members, except members who declined it.
+// The read time is kept with the member, so clients can refetch it.
+func PublishReadReceipt(conversationID uuid.UUID, userID uuid.UUID, readAt time.Time, db *database.Queries) {
+	publishToOtherMembers(conversationID, userID, ReadReceiptEvent, map[string]interface{}{
+		"conversation_id": conversationID,
+		"user_id":         userID,
+		"read_at":         readAt,
+	}, db)
+}
+
+// publishToOtherMembers publishes the ephemeral event to messages topics of members of the conversation
+// except the user and members who declined the conversation.
+func publishToOtherMembers(
+	conversationID uuid.UUID,
+	userID uuid.UUID,
+	eventType string,
+	data interface{},
+	db *database.Queries,
+) {
+	members, err := db.GetConversationMembers(conversationID)
+	if err != nil {
+		log.Printf("Realtime event is not published. Reason: %v", err)
+		return
+	}
+
+	for _, member := range members {
+		if member.UserID == userID || member.Status == models.DeclinedConversation {
+			continue
+		}
+
+		PublishEphemeral(UserMessagesTopic(member.UserID), eventType, data, db)
+	}
+}
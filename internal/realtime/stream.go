@@ -0,0 +1,89 @@
+package realtime
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/google/uuid"
+)
+
+// ServeStream writes events of the topics to the Server-Sent Events stream of the user
+// until the connection fails or the client is closed. Events published after the given one
+// are sent first, so the stream resumes from the Last-Event-ID of the reconnected client.
+func ServeStream(w *bufio.Writer, userID uuid.UUID, topics []string, lastEventID int64, db *database.Queries) {
+	client := newClient(userID, db, nil)
+	defer client.detach()
+
+	_, err := fmt.Fprintf(w, "retry: %d\n\n", configs.RealtimeStreamRetry.Milliseconds())
+	if err == nil {
+		err = w.Flush()
+	}
+
+	if err != nil {
+		return
+	}
+
+	// Missed events wait for space in the queue, so they are sent while the stream is written
+	go client.subscribe(topics, lastEventID)
+
+	ticker := time.NewTicker(configs.RealtimePingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-client.done:
+			if client.reason != disconnectedClose {
+				_ = writeStreamMessage(w, ServerMessage{Type: CloseMessage, Message: client.reason.String()})
+				_ = w.Flush()
+			}
+
+			return
+		case message := <-client.send:
+			err = writeStreamMessage(w, message)
+		case <-ticker.C:
+			// Comments are ignored by clients, but keep proxies from closing the idle connection
+			_, err = w.WriteString(": ping\n\n")
+		}
+
+		if err == nil {
+			err = w.Flush()
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// writeStreamMessage writes the message as the stream event. Pushed events are written
+// with their id and type, other messages are named by their type. Ephemeral events are written
+// without the id, so the Last-Event-ID of the client still resumes from the last kept event.
+func writeStreamMessage(w *bufio.Writer, message ServerMessage) error {
+	if message.Type == EventMessage && message.Event != nil {
+		data, err := json.Marshal(message.Event)
+		if err != nil {
+			return err
+		}
+
+		if message.Event.Ephemeral() {
+			_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", message.Event.Type, data)
+		} else {
+			_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", message.Event.ID, message.Event.Type, data)
+		}
+
+		return err
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", message.Type, data)
+
+	return err
+}
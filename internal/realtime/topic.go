@@ -0,0 +1,114 @@
+package realtime
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// TopicKind is type for kinds of topics clients can subscribe to.
+type TopicKind string
+
+// Enum for topic kind.
+const (
+	NotificationsTopic TopicKind = "notifications"
+	FollowersTopic     TopicKind = "followers"
+	FeedTopic          TopicKind = "feed"
+	MessagesTopic      TopicKind = "messages"
+	CommentsTopic      TopicKind = "comments"
+	LikesTopic         TopicKind = "likes"
+)
+
+// Enum for event type.
+const (
+	NotificationEvent      = "notification"
+	NotificationsReadEvent = "notifications_read"
+	CommentAddedEvent      = "comment_added"
+	CommentUpdatedEvent    = "comment_updated"
+	CommentDeletedEvent    = "comment_deleted"
+	PostLikesEvent         = "post_likes"
+	CommentLikesEvent      = "comment_likes"
+	FollowerAddedEvent     = "follower_added"
+	FeedPostEvent          = "post_added"
+	MessageAddedEvent      = "message_added"
+	MessageUpdatedEvent    = "message_updated"
+	MessageDeletedEvent    = "message_deleted"
+	ConversationReadEvent  = "conversation_read"
+	ConversationLeftEvent  = "conversation_left"
+	TypingEvent            = "typing"
+	ReadReceiptEvent       = "read_receipt"
+)
+
+// errInvalidTopic is returned for topics not matching any kind.
+var errInvalidTopic = errors.New("invalid topic")
+
+// UserNotificationsTopic returns the topic of notifications of the user.
+func UserNotificationsTopic(userID uuid.UUID) string {
+	return fmt.Sprintf("users/%s/%s", userID, NotificationsTopic)
+}
+
+// UserFollowersTopic returns the topic of new followers of the user.
+func UserFollowersTopic(userID uuid.UUID) string {
+	return fmt.Sprintf("users/%s/%s", userID, FollowersTopic)
+}
+
+// UserFeedTopic returns the topic of new posts of followings of the user.
+func UserFeedTopic(userID uuid.UUID) string {
+	return fmt.Sprintf(feedTopicFormat(), userID)
+}
+
+// feedTopicFormat returns the format of feed topics, which gets the id of the user.
+func feedTopicFormat() string {
+	return fmt.Sprintf("users/%%s/%s", FeedTopic)
+}
+
+// UserMessagesTopic returns the topic of messages of conversations of the user.
+func UserMessagesTopic(userID uuid.UUID) string {
+	return fmt.Sprintf("users/%s/%s", userID, MessagesTopic)
+}
+
+// UserTopics returns all topics of the user.
+func UserTopics(userID uuid.UUID) []string {
+	return []string{
+		UserNotificationsTopic(userID),
+		UserFollowersTopic(userID),
+		UserFeedTopic(userID),
+		UserMessagesTopic(userID),
+	}
+}
+
+// PostCommentsTopic returns the topic of added, updated and deleted comments of the post.
+func PostCommentsTopic(postID uuid.UUID) string {
+	return fmt.Sprintf("posts/%s/%s", postID, CommentsTopic)
+}
+
+// PostLikesTopic returns the topic of reaction counts of the post and its comments.
+func PostLikesTopic(postID uuid.UUID) string {
+	return fmt.Sprintf("posts/%s/%s", postID, LikesTopic)
+}
+
+// ParseTopic returns the kind of the topic and the id of the user or the post it belongs to.
+func ParseTopic(topic string) (TopicKind, uuid.UUID, error) {
+	parts := strings.Split(topic, "/")
+	if len(parts) != 3 {
+		return "", uuid.Nil, errInvalidTopic
+	}
+
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return "", uuid.Nil, errInvalidTopic
+	}
+
+	kind := TopicKind(parts[2])
+
+	switch {
+	case parts[0] == "users" &&
+		(kind == NotificationsTopic || kind == FollowersTopic || kind == FeedTopic || kind == MessagesTopic),
+		parts[0] == "posts" && (kind == CommentsTopic || kind == LikesTopic):
+		return kind, id, nil
+	default:
+		return "", uuid.Nil, errInvalidTopic
+	}
+}
@@ -0,0 +1,115 @@
+package realtime
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/api/database"
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/gofiber/websocket/v2"
+	"github.com/google/uuid"
+)
+
+// maxMessageSize is the maximum size of the message of the client in bytes.
+const maxMessageSize = 1024
+
+// Serve runs the WebSocket connection of the user until it is closed, messages of the client
+// are read in the calling goroutine and events are written in a separate one.
+func Serve(conn *websocket.Conn, userID uuid.UUID, db *database.Queries) {
+	client := newClient(userID, db, func(reason closeReason) {
+		closeWebSocket(conn, reason)
+	})
+	defer client.detach()
+
+	go writeWebSocket(conn, client)
+
+	readWebSocket(conn, client)
+}
+
+// readWebSocket handles messages of the client until the connection fails or stays silent too long.
+func readWebSocket(conn *websocket.Conn, client *Client) {
+	conn.SetReadLimit(maxMessageSize)
+	_ = conn.SetReadDeadline(time.Now().Add(configs.RealtimePongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(configs.RealtimePongWait))
+	})
+
+	for {
+		message := ClientMessage{}
+		if err := conn.ReadJSON(&message); err != nil {
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+				client.queue(ServerMessage{Type: ErrorMessage, Message: invalidMessageError})
+				continue
+			}
+
+			return
+		}
+
+		_ = conn.SetReadDeadline(time.Now().Add(configs.RealtimePongWait))
+
+		switch message.Type {
+		case SubscribeMessage:
+			client.subscribe([]string{message.Topic}, message.LastEventID)
+		case UnsubscribeMessage:
+			client.unsubscribe(message.Topic)
+		case PingMessage:
+			client.queue(ServerMessage{Type: PongMessage})
+		default:
+			client.queue(ServerMessage{Type: ErrorMessage, Message: invalidMessageError})
+		}
+
+		select {
+		case <-client.done:
+			return
+		default:
+		}
+	}
+}
+
+// writeWebSocket writes queued messages and pings the connection until the client is closed.
+func writeWebSocket(conn *websocket.Conn, client *Client) {
+	ticker := time.NewTicker(configs.RealtimePingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-client.done:
+			return
+		case message := <-client.send:
+			_ = conn.SetWriteDeadline(time.Now().Add(configs.RealtimeWriteWait))
+			if err := conn.WriteJSON(message); err != nil {
+				client.close(disconnectedClose)
+				return
+			}
+		case <-ticker.C:
+			deadline := time.Now().Add(configs.RealtimeWriteWait)
+			if err := conn.WriteControl(websocket.PingMessage, nil, deadline); err != nil {
+				client.close(disconnectedClose)
+				return
+			}
+		}
+	}
+}
+
+// closeWebSocket closes the connection, sending the close message if the server closes it.
+func closeWebSocket(conn *websocket.Conn, reason closeReason) {
+	code := websocket.CloseNormalClosure
+
+	switch reason {
+	case disconnectedClose:
+		_ = conn.Close()
+		return
+	case slowConsumerClose:
+		code = websocket.ClosePolicyViolation
+	case shutdownClose:
+		code = websocket.CloseGoingAway
+	}
+
+	deadline := time.Now().Add(configs.RealtimeWriteWait)
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason.String()), deadline)
+
+	_ = conn.Close()
+}
@@ -28,6 +28,9 @@ type GetCommentsResponseBody struct {
 
 	// required: true
 	Data []models.Comment `json:"data"`
+
+	// Cursor of the next page, empty if there are no more comments
+	NextCursor string `json:"next_cursor"`
 }
 
 // GetCommentsResponse represent the response retrived on get comments request.
@@ -37,6 +40,28 @@ type GetCommentsResponse struct {
 	Body GetCommentsResponseBody
 }
 
+// GetRepliesResponseBody includes the page of replies to the comment
+// and the cursor of the next page.
+type GetRepliesResponseBody struct {
+	BaseResponseBody
+
+	// required: true
+	Count int `json:"count"`
+
+	// required: true
+	Data []models.Comment `json:"data"`
+
+	// Cursor of the next page, empty if there are no more replies
+	NextCursor string `json:"next_cursor"`
+}
+
+// GetRepliesResponse represent the response retrived on get comment replies request.
+// swagger:response
+type GetRepliesResponse struct {
+	// in: body
+	Body GetRepliesResponseBody
+}
+
 // GetCommentResponseBody includes the signle comment for a post
 // based on given post and comment ID.
 type GetCommentResponseBody struct {
@@ -0,0 +1,134 @@
+package responses
+
+import "github.com/MangriMen/Diverse-Back/internal/models"
+
+// GetConversationsResponseBody includes the slice of conversations.
+type GetConversationsResponseBody struct {
+	BaseResponseBody
+
+	// required: true
+	Count int `json:"count"`
+
+	// required: true
+	Data []models.Conversation `json:"data"`
+
+	// Cursor to fetch the next page, empty if there are no more conversations
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// GetConversationsResponse represent the response retrived on get conversations request.
+// swagger:response
+type GetConversationsResponse struct {
+	// in: body
+	Body GetConversationsResponseBody
+}
+
+// GetConversationsCountResponseBody includes counts of conversations with unread messages and message requests.
+type GetConversationsCountResponseBody struct {
+	BaseResponseBody
+
+	// Number of conversations with unread messages
+	// required: true
+	UnreadCount int `json:"unread_count"`
+
+	// Number of message requests
+	// required: true
+	RequestsCount int `json:"requests_count"`
+}
+
+// GetConversationsCountResponse represent the response retrived on get conversations count request.
+// swagger:response
+type GetConversationsCountResponse struct {
+	// in: body
+	Body GetConversationsCountResponseBody
+}
+
+// GetConversationResponseBody includes the single conversation.
+type GetConversationResponseBody struct {
+	BaseResponseBody
+
+	// required: true
+	Data models.Conversation `json:"data"`
+}
+
+// GetConversationResponse represent the response retrived on get, start, accept or decline conversation request.
+// swagger:response
+type GetConversationResponse struct {
+	// in: body
+	Body GetConversationResponseBody
+}
+
+// ReadConversationResponse represents response for successfully read conversation request.
+// swagger:response
+type ReadConversationResponse struct {
+}
+
+// TypingConversationResponse represents response for successfully sent typing indicator.
+// swagger:response
+type TypingConversationResponse struct {
+}
+
+// GetConversationMembersResponseBody includes the slice of members of the conversation.
+type GetConversationMembersResponseBody struct {
+	BaseResponseBody
+
+	// required: true
+	Count int `json:"count"`
+
+	// required: true
+	Data []models.ConversationMember `json:"data"`
+}
+
+// GetConversationMembersResponse represent the response retrived on get or add conversation members request.
+// swagger:response
+type GetConversationMembersResponse struct {
+	// in: body
+	Body GetConversationMembersResponseBody
+}
+
+// LeaveConversationResponse represents response for successfully leave conversation
+// or remove conversation member request.
+// swagger:response
+type LeaveConversationResponse struct {
+}
+
+// GetMessagesResponseBody includes the slice of messages.
+type GetMessagesResponseBody struct {
+	BaseResponseBody
+
+	// required: true
+	Count int `json:"count"`
+
+	// required: true
+	Data []models.Message `json:"data"`
+
+	// Cursor to fetch older messages, empty if there are no more messages
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// GetMessagesResponse represent the response retrived on get messages request.
+// swagger:response
+type GetMessagesResponse struct {
+	// in: body
+	Body GetMessagesResponseBody
+}
+
+// GetMessageResponseBody includes the single message.
+type GetMessageResponseBody struct {
+	BaseResponseBody
+
+	// required: true
+	Data models.Message `json:"data"`
+}
+
+// GetMessageResponse represent the response retrived on send or update message request.
+// swagger:response
+type GetMessageResponse struct {
+	// in: body
+	Body GetMessageResponseBody
+}
+
+// DeleteMessageResponse represents response for successfully delete message request.
+// swagger:response
+type DeleteMessageResponse struct {
+}
@@ -1,6 +1,10 @@
 package responses
 
-import "bytes"
+import (
+	"bytes"
+
+	"github.com/google/uuid"
+)
 
 // UploadDataResponseBody includes the relative path to uploaded data.
 type UploadDataResponseBody struct {
@@ -8,6 +12,9 @@ type UploadDataResponseBody struct {
 
 	// required: true
 	Path string `json:"path" validate:"required"`
+
+	// The id of the file uploaded for messages to attach it
+	ID *uuid.UUID `json:"id,omitempty"`
 }
 
 // UploadDataResponse contains the uploaded data info.
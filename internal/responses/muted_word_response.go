@@ -0,0 +1,41 @@
+package responses
+
+import "github.com/MangriMen/Diverse-Back/internal/models"
+
+// GetMutedWordsResponseBody includes the slice of muted words.
+type GetMutedWordsResponseBody struct {
+	BaseResponseBody
+
+	// required: true
+	Count int `json:"count"`
+
+	// required: true
+	Data []models.MutedWord `json:"data"`
+}
+
+// GetMutedWordsResponse represent the response retrived on get muted words request.
+// swagger:response
+type GetMutedWordsResponse struct {
+	// in: body
+	Body GetMutedWordsResponseBody
+}
+
+// GetMutedWordResponseBody includes the single muted word.
+type GetMutedWordResponseBody struct {
+	BaseResponseBody
+
+	// required: true
+	Data models.MutedWord `json:"data"`
+}
+
+// GetMutedWordResponse represent the response retrived on mute word request.
+// swagger:response
+type GetMutedWordResponse struct {
+	// in: body
+	Body GetMutedWordResponseBody
+}
+
+// DeleteMutedWordResponse represents response for successfully unmute word request.
+// swagger:response
+type DeleteMutedWordResponse struct {
+}
@@ -0,0 +1,67 @@
+package responses
+
+import "github.com/MangriMen/Diverse-Back/internal/models"
+
+// GetNotificationsResponseBody includes the slice of notifications.
+type GetNotificationsResponseBody struct {
+	BaseResponseBody
+
+	// required: true
+	Count int `json:"count"`
+
+	// required: true
+	Data []models.Notification `json:"data"`
+
+	// Number of unread notifications of the requester
+	// required: true
+	UnreadCount int `json:"unread_count"`
+
+	// Cursor to fetch the next page, empty if there are no more notifications
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// GetNotificationsResponse represent the response retrived on get notifications request.
+// swagger:response
+type GetNotificationsResponse struct {
+	// in: body
+	Body GetNotificationsResponseBody
+}
+
+// GetNotificationsCountResponseBody includes the count of unread notifications.
+type GetNotificationsCountResponseBody struct {
+	BaseResponseBody
+
+	// required: true
+	Count int `json:"count"`
+}
+
+// GetNotificationsCountResponse represent the response retrived on get notifications count request.
+// swagger:response
+type GetNotificationsCountResponse struct {
+	// in: body
+	Body GetNotificationsCountResponseBody
+}
+
+// ReadNotificationResponse represents response for successfully read notification request.
+// swagger:response
+type ReadNotificationResponse struct {
+}
+
+// GetNotificationPreferencesResponseBody includes preferences for each type and channel of notifications
+// and settings of their delivery.
+type GetNotificationPreferencesResponseBody struct {
+	BaseResponseBody
+
+	// required: true
+	Preferences []models.NotificationPreference `json:"preferences"`
+
+	// required: true
+	Settings models.NotificationSettings `json:"settings"`
+}
+
+// GetNotificationPreferencesResponse represent the response retrived on get or update notification preferences request.
+// swagger:response
+type GetNotificationPreferencesResponse struct {
+	// in: body
+	Body GetNotificationPreferencesResponseBody
+}
@@ -26,6 +26,9 @@ type GetPostsResponseBody struct {
 
 	// required: true
 	Data []models.Post `json:"data"`
+
+	// Cursor to fetch the next page of the ranked feed, empty if there are no more posts
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // GetPostsResponse represent the response retrived on get posts request.
@@ -50,6 +53,47 @@ type GetPostResponse struct {
 	Body GetPostResponseBody
 }
 
+// GetLikesResponseBody includes the slice of users who liked the post or comment.
+type GetLikesResponseBody struct {
+	BaseResponseBody
+
+	// required: true
+	Count int `json:"count"`
+
+	// required: true
+	Data []models.Liker `json:"data"`
+
+	// Cursor to fetch the next page, empty if there are no more likes
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// GetLikesResponse represent the response retrived on get likes request.
+// swagger:response
+type GetLikesResponse struct {
+	// in: body
+	Body GetLikesResponseBody
+}
+
+// GetPostInsightsResponseBody includes the post insights.
+type GetPostInsightsResponseBody struct {
+	BaseResponseBody
+
+	// required: true
+	Data models.PostInsights `json:"data"`
+}
+
+// GetPostInsightsResponse represent the response retrived on get post insights request.
+// swagger:response
+type GetPostInsightsResponse struct {
+	// in: body
+	Body GetPostInsightsResponseBody
+}
+
+// ViewPostsResponse represents response for successfully recorded views.
+// swagger:response
+type ViewPostsResponse struct {
+}
+
 // CreateUpdatePostResponse represents response for successfully create or update post request.
 // swagger:response
 type CreateUpdatePostResponse string
@@ -0,0 +1,57 @@
+package responses
+
+import "github.com/MangriMen/Diverse-Back/internal/models"
+
+// GetVAPIDKeyResponseBody includes the public VAPID key of the server.
+type GetVAPIDKeyResponseBody struct {
+	BaseResponseBody
+
+	// Base64url-encoded key, passed as applicationServerKey on subscribing in the browser
+	// required: true
+	PublicKey string `json:"public_key"`
+}
+
+// GetVAPIDKeyResponse represent the response retrived on get VAPID key request.
+// swagger:response
+type GetVAPIDKeyResponse struct {
+	// in: body
+	Body GetVAPIDKeyResponseBody
+}
+
+// GetPushSubscriptionsResponseBody includes the slice of push subscriptions.
+type GetPushSubscriptionsResponseBody struct {
+	BaseResponseBody
+
+	// required: true
+	Count int `json:"count"`
+
+	// required: true
+	Data []models.PushSubscription `json:"data"`
+}
+
+// GetPushSubscriptionsResponse represent the response retrived on get push subscriptions request.
+// swagger:response
+type GetPushSubscriptionsResponse struct {
+	// in: body
+	Body GetPushSubscriptionsResponseBody
+}
+
+// GetPushSubscriptionResponseBody includes the single push subscription.
+type GetPushSubscriptionResponseBody struct {
+	BaseResponseBody
+
+	// required: true
+	Data models.PushSubscription `json:"data"`
+}
+
+// GetPushSubscriptionResponse represent the response retrived on register push subscription request.
+// swagger:response
+type GetPushSubscriptionResponse struct {
+	// in: body
+	Body GetPushSubscriptionResponseBody
+}
+
+// DeletePushSubscriptionResponse represents response for successfully unregister push subscription request.
+// swagger:response
+type DeletePushSubscriptionResponse struct {
+}
@@ -0,0 +1,13 @@
+package responses
+
+// RealtimeResponse represents response for successfully upgrade to the WebSocket connection.
+// swagger:response
+type RealtimeResponse struct {
+}
+
+// RealtimeStreamResponse represents the stream of events in the text/event-stream format.
+// swagger:response
+type RealtimeStreamResponse struct {
+	// in: body
+	Body string
+}
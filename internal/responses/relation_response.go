@@ -57,3 +57,8 @@ type GetRelationStatusResponse struct {
 // swagger:response
 type DeleteRelationResponse struct {
 }
+
+// CommentRestrictionResponse represents response for successfully restrict or unrestrict user request.
+// swagger:response
+type CommentRestrictionResponse struct {
+}
@@ -0,0 +1,71 @@
+package responses
+
+import "github.com/MangriMen/Diverse-Back/internal/models"
+
+// SearchPostsResponseBody includes the slice of found posts
+// and the cursor to fetch the next page.
+type SearchPostsResponseBody struct {
+	BaseResponseBody
+
+	// required: true
+	Count int `json:"count"`
+
+	// required: true
+	Data []models.PostSearchResult `json:"data"`
+
+	// Empty if there are no more results
+	// required: true
+	NextCursor string `json:"next_cursor"`
+}
+
+// SearchPostsResponse represent the response retrived on search posts request.
+// swagger:response
+type SearchPostsResponse struct {
+	// in: body
+	Body SearchPostsResponseBody
+}
+
+// SearchCommentsResponseBody includes the slice of found comments
+// and the cursor to fetch the next page.
+type SearchCommentsResponseBody struct {
+	BaseResponseBody
+
+	// required: true
+	Count int `json:"count"`
+
+	// required: true
+	Data []models.CommentSearchResult `json:"data"`
+
+	// Empty if there are no more results
+	// required: true
+	NextCursor string `json:"next_cursor"`
+}
+
+// SearchCommentsResponse represent the response retrived on search comments request.
+// swagger:response
+type SearchCommentsResponse struct {
+	// in: body
+	Body SearchCommentsResponseBody
+}
+
+// GetSearchSuggestionsResponseBody includes the slices of users, posts and hashtags
+// starting with the search text.
+type GetSearchSuggestionsResponseBody struct {
+	BaseResponseBody
+
+	// required: true
+	Users []models.User `json:"users"`
+
+	// required: true
+	Posts []models.Post `json:"posts"`
+
+	// required: true
+	Hashtags []models.Hashtag `json:"hashtags"`
+}
+
+// GetSearchSuggestionsResponse represent the response retrived on get search suggestions request.
+// swagger:response
+type GetSearchSuggestionsResponse struct {
+	// in: body
+	Body GetSearchSuggestionsResponseBody
+}
@@ -0,0 +1,82 @@
+package routes
+
+import (
+	"github.com/MangriMen/Diverse-Back/internal/controllers"
+	"github.com/MangriMen/Diverse-Back/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ConversationPrivateRoutes sets up private routes for authenticated users.
+// These routes require a valid JWT for authentication and authorization to access the endpoints.
+// It includes endpoints for starting conversations and groups, handling message requests,
+// managing members of groups, typing indicators, getting attachments
+// and sending, editing and deleting messages.
+func ConversationPrivateRoutes(route fiber.Router) {
+	route.Get("/conversations", middleware.JWTProtected(), controllers.GetConversations)
+
+	route.Get("/conversations/count", middleware.JWTProtected(), controllers.GetConversationsCount)
+
+	route.Get(
+		"/conversations/attachments/:attachment",
+		middleware.JWTProtected(),
+		controllers.GetMessageAttachment,
+	)
+
+	route.Get("/conversations/:conversation", middleware.JWTProtected(), controllers.GetConversation)
+
+	route.Get("/conversations/:conversation/members", middleware.JWTProtected(), controllers.GetConversationMembers)
+
+	route.Get("/conversations/:conversation/messages", middleware.JWTProtected(), controllers.GetMessages)
+
+	route.Post("/conversations", middleware.JWTProtected(), controllers.CreateConversation)
+
+	route.Post("/conversations/groups", middleware.JWTProtected(), controllers.CreateGroup)
+
+	route.Patch("/conversations/:conversation", middleware.JWTProtected(), controllers.UpdateConversation)
+
+	route.Post("/conversations/:conversation/read", middleware.JWTProtected(), controllers.ReadConversation)
+
+	route.Post("/conversations/:conversation/typing", middleware.JWTProtected(), controllers.TypingConversation)
+
+	route.Post("/conversations/:conversation/accept", middleware.JWTProtected(), controllers.AcceptConversation)
+
+	route.Post("/conversations/:conversation/decline", middleware.JWTProtected(), controllers.DeclineConversation)
+
+	route.Post("/conversations/:conversation/leave", middleware.JWTProtected(), controllers.LeaveConversation)
+
+	route.Post("/conversations/:conversation/mute", middleware.JWTProtected(), controllers.MuteConversation)
+
+	route.Delete("/conversations/:conversation/mute", middleware.JWTProtected(), controllers.UnmuteConversation)
+
+	route.Post(
+		"/conversations/:conversation/members",
+		middleware.JWTProtected(),
+		controllers.AddConversationMembers,
+	)
+
+	route.Delete(
+		"/conversations/:conversation/members/:user",
+		middleware.JWTProtected(),
+		controllers.RemoveConversationMember,
+	)
+
+	route.Put(
+		"/conversations/:conversation/members/:user/role",
+		middleware.JWTProtected(),
+		controllers.UpdateConversationMemberRole,
+	)
+
+	route.Post("/conversations/:conversation/messages", middleware.JWTProtected(), controllers.SendMessage)
+
+	route.Patch(
+		"/conversations/:conversation/messages/:message",
+		middleware.JWTProtected(),
+		controllers.UpdateMessage,
+	)
+
+	route.Delete(
+		"/conversations/:conversation/messages/:message",
+		middleware.JWTProtected(),
+		controllers.DeleteMessage,
+	)
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/MangriMen/Diverse-Back/internal/controllers"
+	"github.com/MangriMen/Diverse-Back/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+// NotificationPrivateRoutes sets up private routes for authenticated users.
+// These routes require a valid JWT for authentication and authorization to access the endpoints.
+// It includes endpoints for fetching notifications, marking them as read
+// and choosing how they are delivered.
+func NotificationPrivateRoutes(route fiber.Router) {
+	route.Get("/notifications", middleware.JWTProtected(), controllers.GetNotifications)
+
+	route.Get("/notifications/count", middleware.JWTProtected(), controllers.GetNotificationsCount)
+
+	route.Get("/notifications/preferences", middleware.JWTProtected(), controllers.GetNotificationPreferences)
+
+	route.Put("/notifications/preferences", middleware.JWTProtected(), controllers.UpdateNotificationPreferences)
+
+	route.Post("/notifications/read", middleware.JWTProtected(), controllers.ReadAllNotifications)
+
+	route.Post("/notifications/:notification/read", middleware.JWTProtected(), controllers.ReadNotification)
+}
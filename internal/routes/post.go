@@ -15,18 +15,50 @@ func PostPrivateRoutes(route fiber.Router) {
 
 	route.Get("/posts", middleware.JWTProtected(), controllers.GetPosts)
 
+	route.Get("/posts/archive", middleware.JWTProtected(), controllers.GetArchivedPosts)
+
+	route.Get("/posts/trash", middleware.JWTProtected(), controllers.GetDeletedPosts)
+
 	route.Get("/posts/:post", middleware.JWTProtected(), controllers.GetPost)
 
+	route.Get("/posts/:post/likes", middleware.JWTProtected(), controllers.GetPostLikes)
+
+	route.Get("/posts/:post/insights", middleware.JWTProtected(), controllers.GetPostInsights)
+
 	route.Post("/posts", middleware.JWTProtected(), controllers.CreatePost)
 
+	route.Post("/posts/views", middleware.JWTProtected(), controllers.ViewPosts)
+
+	route.Post("/posts/:post/view", middleware.JWTProtected(), controllers.ViewPost)
+
+	route.Post("/posts/:post/archive", middleware.JWTProtected(), controllers.ArchivePost)
+
+	route.Post("/posts/:post/restore", middleware.JWTProtected(), controllers.RestorePost)
+
 	route.Post("/posts/:post/like", middleware.JWTProtected(), controllers.LikePost)
 
+	route.Put("/posts/:post/reaction", middleware.JWTProtected(), controllers.ReactToPost)
+
+	route.Post("/posts/:post/pin", middleware.JWTProtected(), controllers.PinPost)
+
+	route.Put("/posts/pins", middleware.JWTProtected(), controllers.UpdatePinnedPosts)
+
+	route.Put("/posts/:post/sensitivity", middleware.JWTProtected(), controllers.UpdatePostSensitivity)
+
+	route.Post("/posts/:post/poll/votes", middleware.JWTProtected(), controllers.VotePoll)
+
 	route.Patch("/posts/:post", middleware.JWTProtected(), controllers.UpdatePost)
 
 	route.Delete("/posts/:post", middleware.JWTProtected(), controllers.DeletePost)
 
 	route.Delete("/posts/:post/like", middleware.JWTProtected(), controllers.UnlikePost)
 
+	route.Delete("/posts/:post/reaction", middleware.JWTProtected(), controllers.UnlikePost)
+
+	route.Delete("/posts/:post/pin", middleware.JWTProtected(), controllers.UnpinPost)
+
+	route.Delete("/posts/:post/archive", middleware.JWTProtected(), controllers.UnarchivePost)
+
 	PostCommentPrivateRoutes(route)
 }
 
@@ -40,13 +72,31 @@ func PostCommentPrivateRoutes(route fiber.Router) {
 
 	posts.Get("comments", middleware.JWTProtected(), controllers.GetComments)
 
+	posts.Get("comments/:comment/likes", middleware.JWTProtected(), controllers.GetCommentLikes)
+
+	posts.Get("comments/:comment/replies", middleware.JWTProtected(), controllers.GetCommentReplies)
+
 	posts.Post("/comments", middleware.JWTProtected(), controllers.AddComment)
 
+	posts.Post("/comments/:comment/replies", middleware.JWTProtected(), controllers.ReplyToComment)
+
+	posts.Post("/comments/:comment/hide", middleware.JWTProtected(), controllers.HideComment)
+
+	posts.Post("/comments/:comment/pin", middleware.JWTProtected(), controllers.PinComment)
+
 	posts.Post("/comments/:comment/like", middleware.JWTProtected(), controllers.LikeComment)
 
+	posts.Put("/comments/:comment/reaction", middleware.JWTProtected(), controllers.ReactToComment)
+
 	posts.Patch("/comments/:comment", middleware.JWTProtected(), controllers.UpdateComment)
 
 	posts.Delete("/comments/:comment", middleware.JWTProtected(), controllers.DeleteComment)
 
 	posts.Delete("/comments/:comment/like", middleware.JWTProtected(), controllers.UnlikeComment)
+
+	posts.Delete("/comments/:comment/reaction", middleware.JWTProtected(), controllers.UnlikeComment)
+
+	posts.Delete("/comments/:comment/hide", middleware.JWTProtected(), controllers.UnhideComment)
+
+	posts.Delete("/comments/:comment/pin", middleware.JWTProtected(), controllers.UnpinComment)
 }
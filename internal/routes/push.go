@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/MangriMen/Diverse-Back/internal/controllers"
+	"github.com/MangriMen/Diverse-Back/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PushPrivateRoutes sets up private routes for authenticated users.
+// These routes require a valid JWT for authentication and authorization to access the endpoints.
+// It includes endpoints for registering push subscriptions.
+func PushPrivateRoutes(route fiber.Router) {
+	route.Get("/push/vapid-key", middleware.JWTProtected(), controllers.GetVAPIDKey)
+
+	route.Get("/push/subscriptions", middleware.JWTProtected(), controllers.GetPushSubscriptions)
+
+	route.Post("/push/subscriptions", middleware.JWTProtected(), controllers.CreatePushSubscription)
+
+	route.Delete("/push/subscriptions/:subscription", middleware.JWTProtected(), controllers.DeletePushSubscription)
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/MangriMen/Diverse-Back/internal/controllers"
+	"github.com/MangriMen/Diverse-Back/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RealtimePrivateRoutes sets up private routes of the WebSocket gateway and the event stream,
+// which require JWT authentication to connect.
+func RealtimePrivateRoutes(route fiber.Router) {
+	route.Get("/ws", middleware.JWTProtectedStream(), controllers.UpgradeRealtime, controllers.ServeRealtime())
+	route.Get("/events", middleware.JWTProtectedStream(), controllers.StreamRealtime)
+}
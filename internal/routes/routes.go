@@ -22,4 +22,9 @@ func PrivateRoutes(a *fiber.App) {
 	UserPrivateRoutes(route)
 	PostPrivateRoutes(route)
 	DataPrivateRoutes(route)
+	SearchPrivateRoutes(route)
+	NotificationPrivateRoutes(route)
+	RealtimePrivateRoutes(route)
+	PushPrivateRoutes(route)
+	ConversationPrivateRoutes(route)
 }
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/MangriMen/Diverse-Back/internal/controllers"
+	"github.com/MangriMen/Diverse-Back/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+// SearchPrivateRoutes sets up the private routes for the search endpoints,
+// which require JWT authentication to access. It includes routes
+// for full-text search of posts and comments and for prefix search suggestions.
+func SearchPrivateRoutes(route fiber.Router) {
+	route.Get("/search/suggestions", middleware.JWTProtected(), controllers.GetSearchSuggestions)
+
+	route.Get("/search/posts", middleware.JWTProtected(), controllers.SearchPosts)
+
+	route.Get("/search/comments", middleware.JWTProtected(), controllers.SearchComments)
+}
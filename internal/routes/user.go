@@ -33,6 +33,8 @@ func UserPrivateRoutes(route fiber.Router) {
 	route.Delete("/users/:user", middleware.JWTProtected(), controllers.DeleteUser)
 
 	UserRelationPrivateRoutes(route)
+
+	UserMutedWordPrivateRoutes(route)
 }
 
 // UserRelationPrivateRoutes sets up private routes for authenticated users.
@@ -50,4 +52,21 @@ func UserRelationPrivateRoutes(route fiber.Router) {
 	users.Post("/relations/:relationUser", middleware.JWTProtected(), controllers.AddRelation)
 
 	users.Delete("/relations/:relationUser", middleware.JWTProtected(), controllers.DeleteRelation)
+
+	users.Post("/restrictions/:relationUser", middleware.JWTProtected(), controllers.RestrictUser)
+
+	users.Delete("/restrictions/:relationUser", middleware.JWTProtected(), controllers.UnrestrictUser)
+}
+
+// UserMutedWordPrivateRoutes sets up private routes for authenticated users.
+// These routes require a valid JWT for authentication and authorization to access the endpoints.
+// It includes endpoints for fetching, adding and removing muted words.
+func UserMutedWordPrivateRoutes(route fiber.Router) {
+	users := route.Group("/users/:user")
+
+	users.Get("/muted-words", middleware.JWTProtected(), controllers.GetMutedWords)
+
+	users.Post("/muted-words", middleware.JWTProtected(), controllers.MuteWord)
+
+	users.Delete("/muted-words/:mutedWord", middleware.JWTProtected(), controllers.UnmuteWord)
 }
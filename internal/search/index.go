@@ -0,0 +1,138 @@
+// Package search provides indexes for fast prefix search of users, posts and hashtags.
+package search
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// Backend is type for search index implementations.
+type Backend string
+
+// Enum for search backend.
+const (
+	Postgres Backend = "postgres"
+	Memory   Backend = "memory"
+)
+
+// Index is a search index of users, posts and hashtags.
+// Write methods are called after changes are saved to the database.
+type Index interface {
+	// IndexUser adds or replaces the user in the index.
+	IndexUser(user *models.DBUser) error
+
+	// IndexPost adds or replaces the post and its hashtags in the index.
+	IndexPost(post *models.DBPost) error
+
+	// DeleteUser removes the user from the index.
+	DeleteUser(id uuid.UUID) error
+
+	// DeletePost removes the post and its hashtags from the index.
+	DeletePost(id uuid.UUID) error
+
+	// Clear removes all documents from the index.
+	Clear() error
+
+	// SearchUsers returns ids of users which username or name words starts with the query words.
+	SearchUsers(query string, count int) ([]uuid.UUID, error)
+
+	// SearchPosts returns ids of the newest posts which description words starts with the query words.
+	SearchPosts(query string, count int) ([]uuid.UUID, error)
+
+	// SearchHashtags returns the most used hashtags which starts with the query.
+	SearchHashtags(query string, count int) ([]models.Hashtag, error)
+
+	// Flush saves the index state if it is stored outside of the database.
+	Flush() error
+}
+
+// Open returns the index of the given backend, Postgres is used if the backend is empty.
+func Open(backend Backend, db *sqlx.DB) (Index, error) {
+	switch backend {
+	case Postgres, "":
+		return &PostgresIndex{DB: db}, nil
+	case Memory:
+		return openMemoryIndex()
+	default:
+		return nil, fmt.Errorf("unknown search backend %q", backend)
+	}
+}
+
+// Source is the storage of users and posts to build the index from.
+type Source interface {
+	GetUsers() ([]models.DBUser, error)
+	GetPostsAfter(lastSeenPostID uuid.UUID, count int) ([]models.DBPost, error)
+}
+
+// reindexBatchSize is the count of posts fetched from the source at once.
+const reindexBatchSize = 500
+
+// Reindex clears the index and fills it with all users and posts of the source.
+func Reindex(index Index, source Source) error {
+	if err := index.Clear(); err != nil {
+		return err
+	}
+
+	users, err := source.GetUsers()
+	if err != nil {
+		return err
+	}
+
+	for i := range users {
+		if err = index.IndexUser(&users[i]); err != nil {
+			return err
+		}
+	}
+
+	lastSeenPostID := uuid.Nil
+	for {
+		posts, postsErr := source.GetPostsAfter(lastSeenPostID, reindexBatchSize)
+		if postsErr != nil {
+			return postsErr
+		}
+
+		for i := range posts {
+			if err = index.IndexPost(&posts[i]); err != nil {
+				return err
+			}
+		}
+
+		if len(posts) < reindexBatchSize {
+			break
+		}
+		lastSeenPostID = posts[len(posts)-1].ID
+	}
+
+	return index.Flush()
+}
+
+var hashtagRegexp = regexp.MustCompile(`#([\p{L}\p{N}_]+)`)
+
+// ExtractHashtags returns unique lowercase hashtags of the text without leading #.
+func ExtractHashtags(text string) []string {
+	var hashtags []string
+	seen := map[string]bool{}
+
+	for _, match := range hashtagRegexp.FindAllStringSubmatch(text, -1) {
+		hashtag := strings.ToLower(match[1])
+		if !seen[hashtag] {
+			seen[hashtag] = true
+			hashtags = append(hashtags, hashtag)
+		}
+	}
+
+	return hashtags
+}
+
+// Tokenize splits the text into lowercase words of letters and digits.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+}
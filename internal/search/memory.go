@@ -0,0 +1,505 @@
+package search
+
+import (
+	"encoding/gob"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/helpers"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/google/uuid"
+)
+
+//nolint:gochecknoglobals // the index lives in the process and is shared by all database connections
+var shared struct {
+	mutex  sync.Mutex
+	loaded bool
+	index  *MemoryIndex
+	err    error
+}
+
+// openMemoryIndex returns the process-wide memory index, loading it from disk on first call.
+func openMemoryIndex() (*MemoryIndex, error) {
+	shared.mutex.Lock()
+	defer shared.mutex.Unlock()
+
+	if !shared.loaded {
+		shared.index, shared.err = LoadMemoryIndex(configs.SearchIndexPath)
+		shared.loaded = true
+	}
+
+	return shared.index, shared.err
+}
+
+// FlushMemoryIndex saves the process-wide memory index to disk if it was opened.
+func FlushMemoryIndex() error {
+	shared.mutex.Lock()
+	index := shared.index
+	shared.mutex.Unlock()
+
+	if index == nil {
+		return nil
+	}
+
+	return index.Flush()
+}
+
+type memoryUser struct {
+	Username string
+	Name     string
+}
+
+type memoryPost struct {
+	Description string
+	CreatedAt   time.Time
+}
+
+// memorySnapshot is the state of the memory index stored on disk.
+type memorySnapshot struct {
+	Users map[uuid.UUID]memoryUser
+	Posts map[uuid.UUID]memoryPost
+
+	// The time the rebuild of the index started, zero if the index was never rebuilt
+	RebuiltAt time.Time
+}
+
+// MemoryIndex is the pure Go in-process index. Documents are kept in memory
+// and saved to the file on Flush, term indexes are rebuilt on load.
+//
+// The index can be rebuilt by another process while it is used. Flush notices the replaced file
+// and loads it, documents changed since the rebuild started are applied over it.
+type MemoryIndex struct {
+	mutex sync.RWMutex
+
+	path string
+	// The file the index was loaded from or saved to last
+	file os.FileInfo
+
+	users map[uuid.UUID]memoryUser
+	posts map[uuid.UUID]memoryPost
+
+	userTerms    *termIndex
+	postTerms    *termIndex
+	hashtagTerms *termIndex
+
+	// The time each document was changed at, kept for the retention
+	userChanges map[uuid.UUID]time.Time
+	postChanges map[uuid.UUID]time.Time
+
+	// Whether there are changes not saved yet
+	dirty bool
+	// Whether the index is being rebuilt, so it replaces the file without loading it
+	rebuilding bool
+	rebuiltAt  time.Time
+}
+
+// NewMemoryIndex creates an empty memory index stored at the given path.
+func NewMemoryIndex(path string) *MemoryIndex {
+	return &MemoryIndex{
+		path:         path,
+		users:        map[uuid.UUID]memoryUser{},
+		posts:        map[uuid.UUID]memoryPost{},
+		userTerms:    newTermIndex(),
+		postTerms:    newTermIndex(),
+		hashtagTerms: newTermIndex(),
+		userChanges:  map[uuid.UUID]time.Time{},
+		postChanges:  map[uuid.UUID]time.Time{},
+	}
+}
+
+// LoadMemoryIndex loads the memory index from the given path.
+// Returns an empty index if the file does not exist yet.
+func LoadMemoryIndex(path string) (*MemoryIndex, error) {
+	index := NewMemoryIndex(path)
+
+	file, err := os.Open(filepath.Clean(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer helpers.CloseQuietly(file)
+
+	if index.file, err = file.Stat(); err != nil {
+		return nil, err
+	}
+
+	snapshot := memorySnapshot{}
+	if err = gob.NewDecoder(file).Decode(&snapshot); err != nil {
+		return nil, err
+	}
+
+	index.rebuiltAt = snapshot.RebuiltAt
+
+	for id, user := range snapshot.Users {
+		index.putUser(id, user)
+	}
+	for id, post := range snapshot.Posts {
+		index.putPost(id, post)
+	}
+
+	return index, nil
+}
+
+// IndexUser adds or replaces the user in the index.
+func (i *MemoryIndex) IndexUser(user *models.DBUser) error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.putUser(user.ID, memoryUser{Username: user.Username, Name: user.Name})
+	i.userChanges[user.ID] = time.Now()
+	i.dirty = true
+
+	return nil
+}
+
+// IndexPost adds or replaces the post and its hashtags in the index.
+func (i *MemoryIndex) IndexPost(post *models.DBPost) error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.putPost(post.ID, memoryPost{Description: post.Description, CreatedAt: post.CreatedAt})
+	i.postChanges[post.ID] = time.Now()
+	i.dirty = true
+
+	return nil
+}
+
+// DeleteUser removes the user from the index.
+func (i *MemoryIndex) DeleteUser(id uuid.UUID) error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.deleteUser(id)
+	i.userChanges[id] = time.Now()
+	i.dirty = true
+
+	return nil
+}
+
+// DeletePost removes the post and its hashtags from the index.
+func (i *MemoryIndex) DeletePost(id uuid.UUID) error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.deletePost(id)
+	i.postChanges[id] = time.Now()
+	i.dirty = true
+
+	return nil
+}
+
+// Clear removes all documents from the index to rebuild it.
+// The next Flush replaces the file even if another process saved it meanwhile.
+func (i *MemoryIndex) Clear() error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.users = map[uuid.UUID]memoryUser{}
+	i.posts = map[uuid.UUID]memoryPost{}
+	i.userTerms = newTermIndex()
+	i.postTerms = newTermIndex()
+	i.hashtagTerms = newTermIndex()
+
+	i.dirty = true
+	i.rebuilding = true
+	i.rebuiltAt = time.Now()
+
+	return nil
+}
+
+// SearchUsers returns ids of users which username or name words starts with the query words.
+func (i *MemoryIndex) SearchUsers(query string, count int) ([]uuid.UUID, error) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	ids := i.userTerms.match(Tokenize(query))
+
+	sort.Slice(ids, func(a, b int) bool {
+		return i.users[ids[a]].Username < i.users[ids[b]].Username
+	})
+
+	return limit(ids, count), nil
+}
+
+// SearchPosts returns ids of the newest posts which description words starts with the query words.
+func (i *MemoryIndex) SearchPosts(query string, count int) ([]uuid.UUID, error) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	ids := i.postTerms.match(Tokenize(query))
+
+	sort.Slice(ids, func(a, b int) bool {
+		return i.posts[ids[a]].CreatedAt.After(i.posts[ids[b]].CreatedAt)
+	})
+
+	return limit(ids, count), nil
+}
+
+// SearchHashtags returns the most used hashtags which starts with the query.
+func (i *MemoryIndex) SearchHashtags(query string, count int) ([]models.Hashtag, error) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+
+	hashtags := []models.Hashtag{}
+	for _, term := range i.hashtagTerms.prefixTerms(strings.ToLower(strings.TrimPrefix(query, "#"))) {
+		hashtags = append(hashtags, models.Hashtag{
+			Tag:   term,
+			Posts: len(i.hashtagTerms.postings[term]),
+		})
+	}
+
+	sort.Slice(hashtags, func(a, b int) bool {
+		if hashtags[a].Posts != hashtags[b].Posts {
+			return hashtags[a].Posts > hashtags[b].Posts
+		}
+		return hashtags[a].Tag < hashtags[b].Tag
+	})
+
+	return limit(hashtags, count), nil
+}
+
+// Flush saves changes of the index to the file. If the file was replaced
+// by the rebuilt index meanwhile, it is loaded with own changes applied over it first.
+func (i *MemoryIndex) Flush() error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if !i.rebuilding {
+		if err := i.loadRebuilt(); err != nil {
+			return err
+		}
+	}
+
+	if !i.dirty {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(i.path), os.ModePerm); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(i.path), filepath.Base(i.path))
+	if err != nil {
+		return err
+	}
+	defer helpers.CloseQuietly(file)
+
+	err = gob.NewEncoder(file).Encode(memorySnapshot{Users: i.users, Posts: i.posts, RebuiltAt: i.rebuiltAt})
+	if err != nil {
+		return err
+	}
+
+	if err = file.Close(); err != nil {
+		return err
+	}
+
+	if err = os.Rename(file.Name(), i.path); err != nil {
+		return err
+	}
+
+	if i.file, err = os.Stat(i.path); err != nil {
+		return err
+	}
+
+	i.dirty = false
+	i.rebuilding = false
+	i.pruneChanges(time.Now().Add(-configs.SearchIndexChangesRetention))
+
+	return nil
+}
+
+// loadRebuilt replaces documents of the index with the file saved by another process,
+// documents changed since the rebuild started are kept. The mutex must be held.
+func (i *MemoryIndex) loadRebuilt() error {
+	current, err := os.Stat(i.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if i.file != nil && os.SameFile(i.file, current) {
+		return nil
+	}
+
+	rebuilt, err := LoadMemoryIndex(i.path)
+	if err != nil {
+		return err
+	}
+
+	for id, changedAt := range i.userChanges {
+		if changedAt.Before(rebuilt.rebuiltAt) {
+			continue
+		}
+
+		if user, found := i.users[id]; found {
+			rebuilt.putUser(id, user)
+		} else {
+			rebuilt.deleteUser(id)
+		}
+	}
+
+	for id, changedAt := range i.postChanges {
+		if changedAt.Before(rebuilt.rebuiltAt) {
+			continue
+		}
+
+		if post, found := i.posts[id]; found {
+			rebuilt.putPost(id, post)
+		} else {
+			rebuilt.deletePost(id)
+		}
+	}
+
+	i.file = rebuilt.file
+	i.users, i.posts = rebuilt.users, rebuilt.posts
+	i.userTerms, i.postTerms, i.hashtagTerms = rebuilt.userTerms, rebuilt.postTerms, rebuilt.hashtagTerms
+	i.rebuiltAt = rebuilt.rebuiltAt
+	i.dirty = true
+	i.pruneChanges(i.rebuiltAt)
+
+	return nil
+}
+
+// pruneChanges forgets the times of changes made before the given time.
+func (i *MemoryIndex) pruneChanges(before time.Time) {
+	for id, changedAt := range i.userChanges {
+		if changedAt.Before(before) {
+			delete(i.userChanges, id)
+		}
+	}
+
+	for id, changedAt := range i.postChanges {
+		if changedAt.Before(before) {
+			delete(i.postChanges, id)
+		}
+	}
+}
+
+func (i *MemoryIndex) putUser(id uuid.UUID, user memoryUser) {
+	i.users[id] = user
+	i.userTerms.put(id, append(Tokenize(user.Username), Tokenize(user.Name)...))
+}
+
+func (i *MemoryIndex) putPost(id uuid.UUID, post memoryPost) {
+	i.posts[id] = post
+	i.postTerms.put(id, Tokenize(post.Description))
+	i.hashtagTerms.put(id, ExtractHashtags(post.Description))
+}
+
+func (i *MemoryIndex) deleteUser(id uuid.UUID) {
+	delete(i.users, id)
+	i.userTerms.remove(id)
+}
+
+func (i *MemoryIndex) deletePost(id uuid.UUID) {
+	delete(i.posts, id)
+	i.postTerms.remove(id)
+	i.hashtagTerms.remove(id)
+}
+
+func limit[T any](items []T, count int) []T {
+	if len(items) > count {
+		return items[:count]
+	}
+	return items
+}
+
+// termIndex is the inverted index with sorted terms for prefix lookups.
+type termIndex struct {
+	terms    []string
+	postings map[string]map[uuid.UUID]struct{}
+	docTerms map[uuid.UUID][]string
+}
+
+func newTermIndex() *termIndex {
+	return &termIndex{
+		postings: map[string]map[uuid.UUID]struct{}{},
+		docTerms: map[uuid.UUID][]string{},
+	}
+}
+
+// put replaces terms of the document.
+func (t *termIndex) put(id uuid.UUID, terms []string) {
+	t.remove(id)
+
+	for _, term := range terms {
+		docs, ok := t.postings[term]
+		if !ok {
+			docs = map[uuid.UUID]struct{}{}
+			t.postings[term] = docs
+
+			position := sort.SearchStrings(t.terms, term)
+			t.terms = append(t.terms, "")
+			copy(t.terms[position+1:], t.terms[position:])
+			t.terms[position] = term
+		}
+		docs[id] = struct{}{}
+	}
+
+	t.docTerms[id] = terms
+}
+
+// remove removes the document and terms which are not used anymore.
+func (t *termIndex) remove(id uuid.UUID) {
+	for _, term := range t.docTerms[id] {
+		docs := t.postings[term]
+		delete(docs, id)
+
+		if len(docs) == 0 {
+			delete(t.postings, term)
+
+			position := sort.SearchStrings(t.terms, term)
+			if position < len(t.terms) && t.terms[position] == term {
+				t.terms = append(t.terms[:position], t.terms[position+1:]...)
+			}
+		}
+	}
+
+	delete(t.docTerms, id)
+}
+
+// prefixTerms returns all terms which starts with the prefix.
+func (t *termIndex) prefixTerms(prefix string) []string {
+	start := sort.SearchStrings(t.terms, prefix)
+
+	end := start
+	for end < len(t.terms) && strings.HasPrefix(t.terms[end], prefix) {
+		end++
+	}
+
+	return t.terms[start:end]
+}
+
+// match returns documents which has a term starting with each of the prefixes.
+func (t *termIndex) match(prefixes []string) []uuid.UUID {
+	var found map[uuid.UUID]struct{}
+
+	for _, prefix := range prefixes {
+		matched := map[uuid.UUID]struct{}{}
+		for _, term := range t.prefixTerms(prefix) {
+			for id := range t.postings[term] {
+				if _, ok := found[id]; found == nil || ok {
+					matched[id] = struct{}{}
+				}
+			}
+		}
+		found = matched
+	}
+
+	ids := make([]uuid.UUID, 0, len(found))
+	for id := range found {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
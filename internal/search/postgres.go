@@ -0,0 +1,122 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/MangriMen/Diverse-Back/internal/models"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// PostgresIndex is the index which uses tables and indexes of the database directly,
+// so it is always in sync with the database and write methods do nothing.
+type PostgresIndex struct {
+	*sqlx.DB
+}
+
+// IndexUser does nothing, users are indexed by the database.
+func (i *PostgresIndex) IndexUser(_ *models.DBUser) error { return nil }
+
+// IndexPost does nothing, posts are indexed by the database.
+func (i *PostgresIndex) IndexPost(_ *models.DBPost) error { return nil }
+
+// DeleteUser does nothing, users are indexed by the database.
+func (i *PostgresIndex) DeleteUser(_ uuid.UUID) error { return nil }
+
+// DeletePost does nothing, posts are indexed by the database.
+func (i *PostgresIndex) DeletePost(_ uuid.UUID) error { return nil }
+
+// Clear does nothing, the database keeps its indexes in sync itself.
+func (i *PostgresIndex) Clear() error { return nil }
+
+// Flush does nothing, the database stores the index itself.
+func (i *PostgresIndex) Flush() error { return nil }
+
+// SearchUsers returns ids of users which username or name starts with the query.
+func (i *PostgresIndex) SearchUsers(query string, count int) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+
+	sqlQuery := `SELECT id
+		FROM users_view
+		WHERE lower(username) LIKE $1 ESCAPE '\'
+		OR lower(name) LIKE $1 ESCAPE '\'
+		ORDER BY username
+		FETCH FIRST $2 ROWS ONLY`
+
+	err := i.Select(&ids, sqlQuery, likePrefix(query), count)
+	if err != nil {
+		return ids, err
+	}
+
+	return ids, nil
+}
+
+// SearchPosts returns ids of the newest posts which description words starts with the query words.
+func (i *PostgresIndex) SearchPosts(query string, count int) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+
+	words := Tokenize(query)
+	if len(words) == 0 {
+		return ids, nil
+	}
+
+	prefixes := make([]string, 0, len(words))
+	for _, word := range words {
+		prefixes = append(prefixes, "'"+word+"':*")
+	}
+
+	sqlQuery := `SELECT posts_view.id
+		FROM posts_view
+		JOIN posts USING (id)
+		WHERE posts.search_vector @@ (` + prefixIndexQuery() + `)
+		AND posts.search_vector @@ to_tsquery(posts.language, $1)
+		ORDER BY posts_view.created_at DESC
+		FETCH FIRST $2 ROWS ONLY`
+
+	err := i.Select(&ids, sqlQuery, strings.Join(prefixes, " & "), count)
+	if err != nil {
+		return ids, err
+	}
+
+	return ids, nil
+}
+
+// SearchHashtags returns the most used hashtags which starts with the query.
+func (i *PostgresIndex) SearchHashtags(query string, count int) ([]models.Hashtag, error) {
+	hashtags := []models.Hashtag{}
+
+	sqlQuery := `SELECT post_hashtags.tag, Count(*) AS posts
+		FROM post_hashtags
+		JOIN posts_view ON posts_view.id = post_hashtags.post_id
+		WHERE post_hashtags.tag LIKE $1 ESCAPE '\'
+		GROUP BY post_hashtags.tag
+		ORDER BY posts DESC, tag
+		FETCH FIRST $2 ROWS ONLY`
+
+	err := i.Select(&hashtags, sqlQuery, likePrefix(strings.TrimPrefix(query, "#")), count)
+	if err != nil {
+		return hashtags, err
+	}
+
+	return hashtags, nil
+}
+
+// prefixIndexQuery returns the prefix query $1 in all search languages combined.
+// Unlike the query in the language of each row, it's the same for all rows, so the search vector index is used,
+// and matched rows are checked with the query in their language.
+func prefixIndexQuery() string {
+	queries := make([]string, 0, len(configs.GetSearchLanguages()))
+	for _, language := range configs.GetSearchLanguages() {
+		queries = append(queries, fmt.Sprintf("to_tsquery('%s', $1)", language))
+	}
+
+	return strings.Join(queries, " || ")
+}
+
+// likePrefix returns lowercase LIKE pattern matching strings starting with the prefix.
+func likePrefix(prefix string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(strings.ToLower(prefix))
+	return escaped + "%"
+}
@@ -0,0 +1,140 @@
+package webpush
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// Sizes of the aes128gcm content coding (RFC 8188) used by Web Push.
+const (
+	recordSize     = 4096
+	saltSize       = 16
+	authSecretSize = 16
+	publicKeySize  = 65
+	keySize        = 16
+	nonceSize      = 12
+	ikmSize        = 32
+	tagSize        = 16
+	headerSize     = saltSize + 4 + 1 + publicKeySize
+
+	// The last record is padded with the delimiter only
+	lastRecordDelimiter = 0x02
+)
+
+// MaxPayloadSize is the maximum size of the payload, push services accept
+// at least 4096 bytes of the encrypted message in one record.
+const MaxPayloadSize = recordSize - headerSize - tagSize - 1
+
+// Errors returned for payloads and subscriptions which can't be encrypted.
+var (
+	ErrPayloadTooLarge   = errors.New("push payload is too large")
+	ErrInvalidAuthSecret = errors.New("invalid push subscription auth secret")
+)
+
+// encrypt encrypts the payload for the browser with the given public key and authentication secret,
+// as specified by RFC 8291. The result is the body of the push message with the aes128gcm header.
+func encrypt(payload []byte, userAgentPublicKey []byte, authSecret []byte) ([]byte, error) {
+	if len(payload) > MaxPayloadSize {
+		return nil, ErrPayloadTooLarge
+	}
+
+	if len(authSecret) != authSecretSize {
+		return nil, ErrInvalidAuthSecret
+	}
+
+	// The key of the application server and the salt are new for each message
+	serverKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err = rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	return encryptWithKey(payload, userAgentPublicKey, authSecret, serverKey, salt)
+}
+
+// encryptWithKey encrypts the payload with the given key of the application server and salt.
+func encryptWithKey(
+	payload []byte,
+	userAgentPublicKey []byte,
+	authSecret []byte,
+	serverKey *ecdh.PrivateKey,
+	salt []byte,
+) ([]byte, error) {
+	userAgentKey, err := ecdh.P256().NewPublicKey(userAgentPublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	sharedSecret, err := serverKey.ECDH(userAgentKey)
+	if err != nil {
+		return nil, err
+	}
+
+	serverPublicKey := serverKey.PublicKey().Bytes()
+
+	keyInfo := make([]byte, 0, len("WebPush: info\x00")+2*publicKeySize)
+	keyInfo = append(keyInfo, "WebPush: info\x00"...)
+	keyInfo = append(keyInfo, userAgentKey.Bytes()...)
+	keyInfo = append(keyInfo, serverPublicKey...)
+
+	ikm, err := expand(hkdf.Extract(sha256.New, sharedSecret, authSecret), keyInfo, ikmSize)
+	if err != nil {
+		return nil, err
+	}
+
+	prk := hkdf.Extract(sha256.New, ikm, salt)
+
+	contentKey, err := expand(prk, []byte("Content-Encoding: aes128gcm\x00"), keySize)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := expand(prk, []byte("Content-Encoding: nonce\x00"), nonceSize)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(contentKey)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext := make([]byte, 0, len(payload)+1)
+	plaintext = append(plaintext, payload...)
+	plaintext = append(plaintext, lastRecordDelimiter)
+
+	header := make([]byte, 0, headerSize+len(plaintext)+tagSize)
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, recordSize)
+	header = append(header, byte(len(serverPublicKey)))
+	header = append(header, serverPublicKey...)
+
+	return gcm.Seal(header, nonce, plaintext, nil), nil
+}
+
+// expand derives the key of the given size from the pseudorandom key with HKDF.
+func expand(prk []byte, info []byte, size int) ([]byte, error) {
+	key := make([]byte, size)
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, info), key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
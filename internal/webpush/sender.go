@@ -0,0 +1,252 @@
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/configs"
+)
+
+// maxDiscardedResponseSize is the maximum size of the response body read to reuse the connection.
+const maxDiscardedResponseSize = 4096
+
+// Urgency is type for the importance of the push message, used by browsers to save the battery.
+type Urgency string
+
+// Enum for urgency.
+const (
+	VeryLowUrgency Urgency = "very-low"
+	LowUrgency     Urgency = "low"
+	NormalUrgency  Urgency = "normal"
+	HighUrgency    Urgency = "high"
+)
+
+// Subscription is the push subscription of the browser.
+type Subscription struct {
+	// URL of the push service the messages are sent to
+	Endpoint string
+
+	// Public ECDH key of the browser, base64url-encoded
+	P256dh string
+
+	// Authentication secret of the browser, base64url-encoded
+	Auth string
+}
+
+// Message is the push message sent to the subscription.
+type Message struct {
+	// Data received by the service worker of the browser
+	Payload []byte
+
+	// How long the push service keeps the message while the browser is offline
+	TTL time.Duration
+
+	// Undelivered message with the same topic is replaced by this one, up to 32 base64url characters
+	Topic string
+
+	Urgency Urgency
+}
+
+// StatusError is returned when the push service rejects the message.
+type StatusError struct {
+	StatusCode int
+
+	// How long to wait before sending again, if the push service asks to
+	RetryAfter time.Duration
+}
+
+// Error returns the description of the rejection.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("push service responded with status %d", e.StatusCode)
+}
+
+// Expired reports whether the subscription is expired or unsubscribed and should be deleted.
+func (e *StatusError) Expired() bool {
+	return e.StatusCode == http.StatusNotFound || e.StatusCode == http.StatusGone
+}
+
+// Temporary reports whether the message can be sent again later.
+func (e *StatusError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+// Sender sends push messages signed with VAPID keys.
+type Sender struct {
+	keys    *Keys
+	subject string
+
+	// URL all messages are sent to instead of endpoints of subscriptions
+	serviceURL *url.URL
+
+	client *http.Client
+}
+
+// NewSender creates the sender configured by the environment: VAPID_PRIVATE_KEY, VAPID_SUBJECT
+// with the contact of the server operator and PUSH_SERVICE_URL which, if set, replaces push services
+// of all subscriptions, so messages can be received by a local stub.
+func NewSender() (*Sender, error) {
+	keys, err := LoadKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	sender := &Sender{
+		keys:    keys,
+		subject: os.Getenv("VAPID_SUBJECT"),
+		client:  &http.Client{Timeout: configs.PushSendTimeout},
+	}
+
+	if serviceURL := os.Getenv("PUSH_SERVICE_URL"); serviceURL != "" {
+		if sender.serviceURL, err = url.Parse(serviceURL); err != nil {
+			return nil, err
+		}
+	}
+
+	return sender, nil
+}
+
+// Send encrypts the message for the subscription and sends it to the push service.
+// Returns *StatusError if the push service rejects the message.
+func (s *Sender) Send(subscription Subscription, message Message) error {
+	target, err := s.target(subscription.Endpoint)
+	if err != nil {
+		return err
+	}
+
+	userAgentPublicKey, err := decode(subscription.P256dh)
+	if err != nil {
+		return err
+	}
+
+	authSecret, err := decode(subscription.Auth)
+	if err != nil {
+		return err
+	}
+
+	body, err := encrypt(message.Payload, userAgentPublicKey, authSecret)
+	if err != nil {
+		return err
+	}
+
+	authorization, err := s.keys.authorization(target.Scheme+"://"+target.Host, s.subject, time.Now())
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodPost, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Authorization", authorization)
+	request.Header.Set("Content-Encoding", "aes128gcm")
+	request.Header.Set("Content-Type", "application/octet-stream")
+	request.Header.Set("TTL", strconv.Itoa(int(message.TTL.Seconds())))
+
+	if message.Topic != "" {
+		request.Header.Set("Topic", message.Topic)
+	}
+
+	if message.Urgency != "" {
+		request.Header.Set("Urgency", string(message.Urgency))
+	}
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, maxDiscardedResponseSize))
+
+	if response.StatusCode >= http.StatusOK && response.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+
+	return &StatusError{
+		StatusCode: response.StatusCode,
+		RetryAfter: retryAfter(response.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// target returns the URL the message to the endpoint is sent to. If the push service URL is set,
+// the path and the query of the endpoint are sent to it.
+func (s *Sender) target(endpoint string) (*url.URL, error) {
+	target, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.serviceURL == nil {
+		return target, nil
+	}
+
+	serviceTarget := s.serviceURL.JoinPath(target.Path)
+	serviceTarget.RawQuery = target.RawQuery
+
+	return serviceTarget, nil
+}
+
+// retryAfter parses the Retry-After header given in seconds or as the date.
+func retryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+
+	return 0
+}
+
+// AllowedEndpoint reports whether messages can be sent to the endpoint: it must be the HTTPS URL
+// of the known push service. Any endpoint is allowed if the push service URL replaces them.
+func AllowedEndpoint(endpoint string) bool {
+	if os.Getenv("PUSH_SERVICE_URL") != "" {
+		return true
+	}
+
+	target, err := url.Parse(endpoint)
+	if err != nil || target.Scheme != "https" || target.User != nil {
+		return false
+	}
+
+	host := strings.ToLower(target.Hostname())
+	for _, serviceHost := range strings.Split(configs.PushServiceHosts, ",") {
+		if host == serviceHost || strings.HasSuffix(host, "."+serviceHost) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateSubscription reports whether keys of the subscription can be used to encrypt messages.
+func ValidateSubscription(subscription Subscription) bool {
+	userAgentPublicKey, err := decode(subscription.P256dh)
+	if err != nil || len(userAgentPublicKey) != publicKeySize {
+		return false
+	}
+
+	authSecret, err := decode(subscription.Auth)
+	if err != nil || len(authSecret) != authSecretSize {
+		return false
+	}
+
+	_, err = encrypt([]byte{}, userAgentPublicKey, authSecret)
+
+	return err == nil
+}
@@ -0,0 +1,125 @@
+// Package webpush sends push messages to browsers with the Web Push protocol: payloads are
+// encrypted for the subscription (RFC 8291) and requests are signed with VAPID keys (RFC 8292).
+package webpush
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/MangriMen/Diverse-Back/configs"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// ErrNotConfigured is returned when VAPID keys are not set in the environment.
+var ErrNotConfigured = errors.New("VAPID keys are not configured")
+
+// coordinateSize is the size of coordinates of P-256 points in bytes.
+const coordinateSize = 32
+
+// Keys is the VAPID key pair identifying the server to push services.
+type Keys struct {
+	private *ecdsa.PrivateKey
+
+	// Public key as the uncompressed P-256 point
+	public []byte
+}
+
+// GenerateKeys generates a new VAPID key pair.
+func GenerateKeys() (*Keys, error) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return keysFromECDH(key), nil
+}
+
+// ParseKeys parses the VAPID key pair from the base64url-encoded private key.
+func ParseKeys(privateKey string) (*Keys, error) {
+	data, err := decode(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ecdh.P256().NewPrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return keysFromECDH(key), nil
+}
+
+// LoadKeys parses the VAPID key pair from the VAPID_PRIVATE_KEY environment variable.
+func LoadKeys() (*Keys, error) {
+	privateKey := os.Getenv("VAPID_PRIVATE_KEY")
+	if privateKey == "" {
+		return nil, ErrNotConfigured
+	}
+
+	return ParseKeys(privateKey)
+}
+
+// Enabled reports whether VAPID keys are set, so push messages can be sent.
+func Enabled() bool {
+	return os.Getenv("VAPID_PRIVATE_KEY") != ""
+}
+
+// keysFromECDH converts the ECDH key to the ECDSA one used to sign tokens.
+func keysFromECDH(key *ecdh.PrivateKey) *Keys {
+	public := key.PublicKey().Bytes()
+
+	return &Keys{
+		private: &ecdsa.PrivateKey{
+			PublicKey: ecdsa.PublicKey{
+				Curve: elliptic.P256(),
+				X:     new(big.Int).SetBytes(public[1 : 1+coordinateSize]),
+				Y:     new(big.Int).SetBytes(public[1+coordinateSize:]),
+			},
+			D: new(big.Int).SetBytes(key.Bytes()),
+		},
+		public: public,
+	}
+}
+
+// PublicKey returns the base64url-encoded public key, used by browsers as the application server key.
+func (k *Keys) PublicKey() string {
+	return base64.RawURLEncoding.EncodeToString(k.public)
+}
+
+// PrivateKey returns the base64url-encoded private key.
+func (k *Keys) PrivateKey() string {
+	return base64.RawURLEncoding.EncodeToString(k.private.D.FillBytes(make([]byte, coordinateSize)))
+}
+
+// authorization returns the value of the Authorization header for the push service with the given origin.
+// The subject is the contact of the server operator, a mailto: or https: URL.
+func (k *Keys) authorization(audience string, subject string, now time.Time) (string, error) {
+	claims := jwt.MapClaims{
+		"aud": audience,
+		"exp": now.Add(configs.VAPIDTokenLifetime).Unix(),
+	}
+	if subject != "" {
+		claims["sub"] = subject
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, claims).SignedString(k.private)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("vapid t=%s, k=%s", token, k.PublicKey()), nil
+}
+
+// decode decodes the base64url-encoded value, padded or not.
+func decode(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+}
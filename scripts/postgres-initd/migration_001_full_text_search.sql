@@ -0,0 +1,51 @@
+--
+-- Full-text search over post descriptions and comment contents.
+--
+-- Every post and comment stores the text search configuration it was written in,
+-- so stemming is applied with the rules of that language both on indexing and on querying.
+--
+
+ALTER TABLE public.posts
+    ADD COLUMN IF NOT EXISTS language regconfig NOT NULL DEFAULT 'english';
+
+ALTER TABLE public.posts
+    ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector(language, coalesce(description, ''))) STORED;
+
+CREATE INDEX IF NOT EXISTS posts_search_vector_idx
+    ON public.posts USING gin (search_vector);
+
+
+ALTER TABLE public.comments
+    ADD COLUMN IF NOT EXISTS language regconfig NOT NULL DEFAULT 'english';
+
+ALTER TABLE public.comments
+    ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector(language, coalesce(content, ''))) STORED;
+
+CREATE INDEX IF NOT EXISTS comments_search_vector_idx
+    ON public.comments USING gin (search_vector);
+
+
+CREATE OR REPLACE VIEW public.posts_view AS
+ SELECT posts.id,
+    posts.user_id,
+    posts.content,
+    posts.description,
+    posts.likes,
+    posts.created_at,
+    posts.language::text AS language
+   FROM public.posts
+  WHERE (posts.deleted_at IS NULL);
+
+CREATE OR REPLACE VIEW public.comments_view AS
+ SELECT comments.id,
+    comments.post_id,
+    comments.user_id,
+    comments.content,
+    comments.created_at,
+    comments.updated_at,
+    comments.likes,
+    comments.language::text AS language
+   FROM public.comments
+  WHERE (comments.deleted_at IS NULL);
@@ -0,0 +1,9 @@
+--
+-- Prefix search of users by username and name.
+--
+
+CREATE INDEX IF NOT EXISTS users_username_prefix_idx
+    ON public.users (lower(username) text_pattern_ops);
+
+CREATE INDEX IF NOT EXISTS users_name_prefix_idx
+    ON public.users (lower(name) text_pattern_ops);
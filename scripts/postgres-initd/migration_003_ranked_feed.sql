@@ -0,0 +1,22 @@
+--
+-- Ranked "for you" feed.
+--
+-- The ranking is computed once per feed session and stored, so pages of the session
+-- are cut from the same order even if scores change while the user scrolls.
+--
+
+CREATE TABLE IF NOT EXISTS public.feed_snapshots (
+    id uuid NOT NULL PRIMARY KEY,
+    user_id uuid NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    post_ids uuid[] NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS feed_snapshots_created_at_idx
+    ON public.feed_snapshots (created_at);
+
+CREATE INDEX IF NOT EXISTS posts_created_at_idx
+    ON public.posts (created_at);
+
+CREATE INDEX IF NOT EXISTS comments_post_id_created_at_idx
+    ON public.comments (post_id, created_at);
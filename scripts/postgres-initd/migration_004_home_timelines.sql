@@ -0,0 +1,45 @@
+--
+-- Materialized home timelines.
+--
+-- New posts are fanned out to timelines of the author followers. Posts of authors with
+-- too many followers are not fanned out and are read from the posts table instead.
+--
+
+CREATE TABLE IF NOT EXISTS public.timelines (
+    user_id uuid NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    post_id uuid NOT NULL REFERENCES public.posts(id) ON DELETE CASCADE,
+    author_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (user_id, post_id)
+);
+
+CREATE INDEX IF NOT EXISTS timelines_user_id_created_at_idx
+    ON public.timelines (user_id, created_at DESC, post_id DESC);
+
+CREATE INDEX IF NOT EXISTS timelines_user_id_author_id_idx
+    ON public.timelines (user_id, author_id);
+
+ALTER TABLE public.posts
+    ADD COLUMN IF NOT EXISTS fanned_out boolean NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS posts_not_fanned_out_idx
+    ON public.posts (user_id, created_at)
+    WHERE NOT fanned_out;
+
+CREATE INDEX IF NOT EXISTS user_relations_relation_user_id_type_idx
+    ON public.user_relations (relation_user_id, type);
+
+-- Fan out existing posts.
+INSERT INTO public.timelines (user_id, post_id, author_id, created_at)
+SELECT followings.user_id, posts.id, posts.user_id, posts.created_at
+  FROM public.posts
+  JOIN public.user_relations AS followings
+    ON followings.relation_user_id = posts.user_id
+   AND followings.type = 'following'
+   AND followings.deleted_at IS NULL
+ WHERE posts.deleted_at IS NULL
+    ON CONFLICT DO NOTHING;
+
+UPDATE public.posts
+   SET fanned_out = true
+ WHERE deleted_at IS NULL;
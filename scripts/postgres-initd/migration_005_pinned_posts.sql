@@ -0,0 +1,15 @@
+--
+-- Pinned posts.
+--
+-- Users can pin a few of their own posts to the top of the profile.
+-- Pins are removed by the application when the post is deleted.
+--
+
+CREATE TABLE IF NOT EXISTS public.pinned_posts (
+    post_id uuid NOT NULL PRIMARY KEY REFERENCES public.posts(id) ON DELETE CASCADE,
+    user_id uuid NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    position integer NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT pinned_posts_user_id_position_key
+        UNIQUE (user_id, position) DEFERRABLE INITIALLY DEFERRED
+);
@@ -0,0 +1,170 @@
+--
+-- Emoji reactions on posts and comments.
+--
+-- Likes become reactions of the default kind: every like row stores the reaction,
+-- "likes" keeps the total number of reactions and "reactions" keeps the number of each kind.
+--
+
+ALTER TABLE public.post_likes
+    ADD COLUMN IF NOT EXISTS reaction text NOT NULL DEFAULT 'like';
+
+ALTER TABLE public.comment_likes
+    ADD COLUMN IF NOT EXISTS reaction text NOT NULL DEFAULT 'like';
+
+ALTER TABLE public.posts
+    ADD COLUMN IF NOT EXISTS reactions jsonb NOT NULL DEFAULT '{}';
+
+ALTER TABLE public.comments
+    ADD COLUMN IF NOT EXISTS reactions jsonb NOT NULL DEFAULT '{}';
+
+
+-- Adds the delta to the total and to the reaction counts, the reaction is removed when its count is zero.
+CREATE OR REPLACE FUNCTION public.add_reaction_count(reactions jsonb, reaction text, delta integer)
+    RETURNS jsonb
+    LANGUAGE sql IMMUTABLE
+    AS $$
+    SELECT CASE
+        WHEN coalesce((reactions ->> reaction)::integer, 0) + delta > 0
+            THEN reactions || jsonb_build_object(reaction, coalesce((reactions ->> reaction)::integer, 0) + delta)
+        ELSE reactions - reaction
+    END
+$$;
+
+CREATE OR REPLACE FUNCTION public.update_reactions_count_on_post() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$BEGIN
+	IF (TG_OP IN ('UPDATE', 'DELETE') AND OLD.deleted_at IS NULL) THEN
+		UPDATE posts
+			SET likes = likes - 1,
+				reactions = add_reaction_count(reactions, OLD.reaction, -1)
+			WHERE id = OLD.post_id;
+	END IF;
+	IF (TG_OP IN ('INSERT', 'UPDATE') AND NEW.deleted_at IS NULL) THEN
+		UPDATE posts
+			SET likes = likes + 1,
+				reactions = add_reaction_count(reactions, NEW.reaction, 1)
+			WHERE id = NEW.post_id;
+	END IF;
+	IF (TG_OP = 'DELETE') THEN
+		RETURN OLD;
+	END IF;
+	RETURN NEW;
+END;$$;
+
+CREATE OR REPLACE FUNCTION public.update_reactions_count_on_comment() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$BEGIN
+	IF (TG_OP IN ('UPDATE', 'DELETE') AND OLD.deleted_at IS NULL) THEN
+		UPDATE comments
+			SET likes = likes - 1,
+				reactions = add_reaction_count(reactions, OLD.reaction, -1)
+			WHERE id = OLD.comment_id;
+	END IF;
+	IF (TG_OP IN ('INSERT', 'UPDATE') AND NEW.deleted_at IS NULL) THEN
+		UPDATE comments
+			SET likes = likes + 1,
+				reactions = add_reaction_count(reactions, NEW.reaction, 1)
+			WHERE id = NEW.comment_id;
+	END IF;
+	IF (TG_OP = 'DELETE') THEN
+		RETURN OLD;
+	END IF;
+	RETURN NEW;
+END;$$;
+
+DROP TRIGGER IF EXISTS update_likes_count_on_post_trigger ON public.post_likes;
+DROP TRIGGER IF EXISTS update_reactions_count_on_post_trigger ON public.post_likes;
+CREATE TRIGGER update_reactions_count_on_post_trigger
+    AFTER INSERT OR DELETE OR UPDATE OF deleted_at, reaction ON public.post_likes
+    FOR EACH ROW EXECUTE FUNCTION public.update_reactions_count_on_post();
+
+DROP TRIGGER IF EXISTS update_likes_count_on_comment_trigger ON public.comment_likes;
+DROP TRIGGER IF EXISTS update_reactions_count_on_comment_trigger ON public.comment_likes;
+CREATE TRIGGER update_reactions_count_on_comment_trigger
+    AFTER INSERT OR DELETE OR UPDATE OF deleted_at, reaction ON public.comment_likes
+    FOR EACH ROW EXECUTE FUNCTION public.update_reactions_count_on_comment();
+
+DROP FUNCTION IF EXISTS public.update_likes_count_on_post();
+DROP FUNCTION IF EXISTS public.update_likes_count_on_comment();
+
+
+-- Convert existing likes into the default reaction and recount totals.
+UPDATE public.posts
+   SET likes = 0,
+       reactions = '{}';
+
+UPDATE public.posts
+   SET likes = counts.likes,
+       reactions = counts.reactions
+  FROM (
+      SELECT post_id, sum(count) AS likes, jsonb_object_agg(reaction, count) AS reactions
+        FROM (
+            SELECT post_id, reaction, Count(*) AS count
+              FROM public.post_likes
+             WHERE deleted_at IS NULL
+             GROUP BY post_id, reaction
+        ) AS reaction_counts
+       GROUP BY post_id
+  ) AS counts
+ WHERE posts.id = counts.post_id;
+
+UPDATE public.comments
+   SET likes = 0,
+       reactions = '{}';
+
+UPDATE public.comments
+   SET likes = counts.likes,
+       reactions = counts.reactions
+  FROM (
+      SELECT comment_id, sum(count) AS likes, jsonb_object_agg(reaction, count) AS reactions
+        FROM (
+            SELECT comment_id, reaction, Count(*) AS count
+              FROM public.comment_likes
+             WHERE deleted_at IS NULL
+             GROUP BY comment_id, reaction
+        ) AS reaction_counts
+       GROUP BY comment_id
+  ) AS counts
+ WHERE comments.id = counts.comment_id;
+
+
+CREATE OR REPLACE VIEW public.post_likes_view AS
+ SELECT post_likes.id,
+    post_likes.post_id,
+    post_likes.user_id,
+    post_likes.reaction
+   FROM public.post_likes
+  WHERE (post_likes.deleted_at IS NULL);
+
+CREATE OR REPLACE VIEW public.comment_likes_view AS
+ SELECT comment_likes.id,
+    comment_likes.comment_id,
+    comment_likes.user_id,
+    comment_likes.reaction
+   FROM public.comment_likes
+  WHERE (comment_likes.deleted_at IS NULL);
+
+CREATE OR REPLACE VIEW public.posts_view AS
+ SELECT posts.id,
+    posts.user_id,
+    posts.content,
+    posts.description,
+    posts.likes,
+    posts.created_at,
+    posts.language::text AS language,
+    posts.reactions
+   FROM public.posts
+  WHERE (posts.deleted_at IS NULL);
+
+CREATE OR REPLACE VIEW public.comments_view AS
+ SELECT comments.id,
+    comments.post_id,
+    comments.user_id,
+    comments.content,
+    comments.created_at,
+    comments.updated_at,
+    comments.likes,
+    comments.language::text AS language,
+    comments.reactions
+   FROM public.comments
+  WHERE (comments.deleted_at IS NULL);
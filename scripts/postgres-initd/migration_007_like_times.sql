@@ -0,0 +1,35 @@
+--
+-- Like times.
+--
+-- Likes made before this migration get the migration time.
+--
+
+ALTER TABLE public.post_likes
+    ADD COLUMN IF NOT EXISTS created_at timestamp with time zone NOT NULL DEFAULT now();
+
+ALTER TABLE public.comment_likes
+    ADD COLUMN IF NOT EXISTS created_at timestamp with time zone NOT NULL DEFAULT now();
+
+CREATE INDEX IF NOT EXISTS post_likes_post_id_created_at_idx
+    ON public.post_likes (post_id, created_at);
+
+CREATE INDEX IF NOT EXISTS comment_likes_comment_id_created_at_idx
+    ON public.comment_likes (comment_id, created_at);
+
+CREATE OR REPLACE VIEW public.post_likes_view AS
+ SELECT post_likes.id,
+    post_likes.post_id,
+    post_likes.user_id,
+    post_likes.reaction,
+    post_likes.created_at
+   FROM public.post_likes
+  WHERE (post_likes.deleted_at IS NULL);
+
+CREATE OR REPLACE VIEW public.comment_likes_view AS
+ SELECT comment_likes.id,
+    comment_likes.comment_id,
+    comment_likes.user_id,
+    comment_likes.reaction,
+    comment_likes.created_at
+   FROM public.comment_likes
+  WHERE (comment_likes.deleted_at IS NULL);
@@ -0,0 +1,51 @@
+--
+-- Post impressions.
+--
+-- Views are recorded as events deduplicated per user per time window,
+-- events of finished windows are rolled up into hourly counts by a background job.
+--
+
+CREATE TABLE IF NOT EXISTS public.post_view_events (
+    post_id uuid NOT NULL REFERENCES public.posts(id) ON DELETE CASCADE,
+    user_id uuid NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    window_start timestamp with time zone NOT NULL,
+    viewed_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (post_id, user_id, window_start)
+);
+
+CREATE INDEX IF NOT EXISTS post_view_events_window_start_idx
+    ON public.post_view_events (window_start);
+
+CREATE TABLE IF NOT EXISTS public.post_viewers (
+    post_id uuid NOT NULL REFERENCES public.posts(id) ON DELETE CASCADE,
+    user_id uuid NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    first_viewed_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (post_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS public.post_views_hourly (
+    post_id uuid NOT NULL REFERENCES public.posts(id) ON DELETE CASCADE,
+    hour timestamp with time zone NOT NULL,
+    views integer NOT NULL,
+    unique_viewers integer NOT NULL,
+    PRIMARY KEY (post_id, hour)
+);
+
+ALTER TABLE public.posts
+    ADD COLUMN IF NOT EXISTS view_count integer NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS comments_post_id_idx
+    ON public.comments (post_id);
+
+CREATE OR REPLACE VIEW public.posts_view AS
+ SELECT posts.id,
+    posts.user_id,
+    posts.content,
+    posts.description,
+    posts.likes,
+    posts.created_at,
+    posts.language::text AS language,
+    posts.reactions,
+    posts.view_count
+   FROM public.posts
+  WHERE (posts.deleted_at IS NULL);
@@ -0,0 +1,64 @@
+--
+-- Archived posts and trash.
+--
+-- Archived posts are hidden from everyone but the owner. Deleted posts stay in the trash
+-- of the owner until they are purged permanently after the retention period.
+--
+
+ALTER TABLE public.posts
+    ADD COLUMN IF NOT EXISTS archived_at timestamp with time zone;
+
+CREATE INDEX IF NOT EXISTS posts_user_id_archived_at_idx
+    ON public.posts (user_id, archived_at)
+    WHERE archived_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS posts_user_id_deleted_at_idx
+    ON public.posts (user_id, deleted_at)
+    WHERE deleted_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS comment_likes_comment_id_idx
+    ON public.comment_likes (comment_id);
+
+CREATE INDEX IF NOT EXISTS post_likes_post_id_idx
+    ON public.post_likes (post_id);
+
+CREATE OR REPLACE VIEW public.posts_view AS
+ SELECT posts.id,
+    posts.user_id,
+    posts.content,
+    posts.description,
+    posts.likes,
+    posts.created_at,
+    posts.language::text AS language,
+    posts.reactions,
+    posts.view_count
+   FROM public.posts
+  WHERE (posts.deleted_at IS NULL AND posts.archived_at IS NULL);
+
+CREATE OR REPLACE VIEW public.archived_posts_view AS
+ SELECT posts.id,
+    posts.user_id,
+    posts.content,
+    posts.description,
+    posts.likes,
+    posts.created_at,
+    posts.language::text AS language,
+    posts.reactions,
+    posts.view_count,
+    posts.archived_at
+   FROM public.posts
+  WHERE (posts.deleted_at IS NULL AND posts.archived_at IS NOT NULL);
+
+CREATE OR REPLACE VIEW public.deleted_posts_view AS
+ SELECT posts.id,
+    posts.user_id,
+    posts.content,
+    posts.description,
+    posts.likes,
+    posts.created_at,
+    posts.language::text AS language,
+    posts.reactions,
+    posts.view_count,
+    posts.deleted_at
+   FROM public.posts
+  WHERE (posts.deleted_at IS NOT NULL);
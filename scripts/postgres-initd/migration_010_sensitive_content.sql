@@ -0,0 +1,81 @@
+--
+-- Content warnings and sensitive posts.
+--
+-- Every user chooses how sensitive posts of other users are shown: as is, blurred or hidden.
+-- Moderators can flag posts of other users as sensitive.
+--
+
+ALTER TABLE public.posts
+    ADD COLUMN IF NOT EXISTS content_warning character varying(256) NOT NULL DEFAULT '';
+
+ALTER TABLE public.posts
+    ADD COLUMN IF NOT EXISTS sensitive boolean NOT NULL DEFAULT false;
+
+ALTER TABLE public.users
+    ADD COLUMN IF NOT EXISTS role text NOT NULL DEFAULT 'user';
+
+ALTER TABLE public.user_info
+    ADD COLUMN IF NOT EXISTS sensitive_content text NOT NULL DEFAULT 'blur';
+
+CREATE OR REPLACE VIEW public.users_view AS
+ SELECT users.id,
+    users.email,
+    users.password,
+    users.username,
+    users.name,
+    users.created_at,
+    users.updated_at,
+    users.avatar_url,
+    user_info.about,
+    users.role,
+    user_info.sensitive_content
+   FROM (public.users
+     LEFT JOIN public.user_info USING (id))
+  WHERE (users.deleted_at IS NULL);
+
+CREATE OR REPLACE VIEW public.posts_view AS
+ SELECT posts.id,
+    posts.user_id,
+    posts.content,
+    posts.description,
+    posts.likes,
+    posts.created_at,
+    posts.language::text AS language,
+    posts.reactions,
+    posts.view_count,
+    posts.content_warning,
+    posts.sensitive
+   FROM public.posts
+  WHERE (posts.deleted_at IS NULL AND posts.archived_at IS NULL);
+
+CREATE OR REPLACE VIEW public.archived_posts_view AS
+ SELECT posts.id,
+    posts.user_id,
+    posts.content,
+    posts.description,
+    posts.likes,
+    posts.created_at,
+    posts.language::text AS language,
+    posts.reactions,
+    posts.view_count,
+    posts.archived_at,
+    posts.content_warning,
+    posts.sensitive
+   FROM public.posts
+  WHERE (posts.deleted_at IS NULL AND posts.archived_at IS NOT NULL);
+
+CREATE OR REPLACE VIEW public.deleted_posts_view AS
+ SELECT posts.id,
+    posts.user_id,
+    posts.content,
+    posts.description,
+    posts.likes,
+    posts.created_at,
+    posts.language::text AS language,
+    posts.reactions,
+    posts.view_count,
+    posts.deleted_at,
+    posts.content_warning,
+    posts.sensitive
+   FROM public.posts
+  WHERE (posts.deleted_at IS NOT NULL);
@@ -0,0 +1,50 @@
+--
+-- Polls attached to posts.
+--
+-- A post can carry one poll with 2 to 10 options. Each user votes once, choosing one
+-- or, for multiple choice polls, several options. Votes of anonymous polls are not linked
+-- to the options chosen, only the fact of voting is kept to prevent repeated votes.
+-- Expired polls are closed by the application.
+--
+
+CREATE TABLE IF NOT EXISTS public.polls (
+    id uuid NOT NULL PRIMARY KEY,
+    post_id uuid NOT NULL UNIQUE REFERENCES public.posts(id) ON DELETE CASCADE,
+    multiple boolean NOT NULL DEFAULT false,
+    anonymous boolean NOT NULL DEFAULT false,
+    show_results boolean NOT NULL DEFAULT false,
+    voters_count integer NOT NULL DEFAULT 0,
+    expires_at timestamp with time zone,
+    closed_at timestamp with time zone,
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS polls_expires_at_idx
+    ON public.polls (expires_at)
+    WHERE closed_at IS NULL AND expires_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS public.poll_options (
+    id uuid NOT NULL PRIMARY KEY,
+    poll_id uuid NOT NULL REFERENCES public.polls(id) ON DELETE CASCADE,
+    position integer NOT NULL,
+    text character varying(100) NOT NULL,
+    votes integer NOT NULL DEFAULT 0,
+    UNIQUE (poll_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS public.poll_voters (
+    poll_id uuid NOT NULL REFERENCES public.polls(id) ON DELETE CASCADE,
+    user_id uuid NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    voted_at timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (poll_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS public.poll_votes (
+    option_id uuid NOT NULL REFERENCES public.poll_options(id) ON DELETE CASCADE,
+    poll_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    PRIMARY KEY (option_id, user_id),
+    FOREIGN KEY (poll_id, user_id) REFERENCES public.poll_voters(poll_id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS poll_votes_poll_id_user_id_idx ON public.poll_votes (poll_id, user_id);
@@ -0,0 +1,59 @@
+--
+-- Comment policy of posts.
+--
+-- Authors choose who can comment their posts: everyone, users followed by the author,
+-- users mentioned in the post description or nobody.
+--
+
+ALTER TABLE public.posts
+    ADD COLUMN IF NOT EXISTS comment_policy text NOT NULL DEFAULT 'everyone';
+
+CREATE OR REPLACE VIEW public.posts_view AS
+ SELECT posts.id,
+    posts.user_id,
+    posts.content,
+    posts.description,
+    posts.likes,
+    posts.created_at,
+    posts.language::text AS language,
+    posts.reactions,
+    posts.view_count,
+    posts.content_warning,
+    posts.sensitive,
+    posts.comment_policy
+   FROM public.posts
+  WHERE (posts.deleted_at IS NULL AND posts.archived_at IS NULL);
+
+CREATE OR REPLACE VIEW public.archived_posts_view AS
+ SELECT posts.id,
+    posts.user_id,
+    posts.content,
+    posts.description,
+    posts.likes,
+    posts.created_at,
+    posts.language::text AS language,
+    posts.reactions,
+    posts.view_count,
+    posts.archived_at,
+    posts.content_warning,
+    posts.sensitive,
+    posts.comment_policy
+   FROM public.posts
+  WHERE (posts.deleted_at IS NULL AND posts.archived_at IS NOT NULL);
+
+CREATE OR REPLACE VIEW public.deleted_posts_view AS
+ SELECT posts.id,
+    posts.user_id,
+    posts.content,
+    posts.description,
+    posts.likes,
+    posts.created_at,
+    posts.language::text AS language,
+    posts.reactions,
+    posts.view_count,
+    posts.deleted_at,
+    posts.content_warning,
+    posts.sensitive,
+    posts.comment_policy
+   FROM public.posts
+  WHERE (posts.deleted_at IS NOT NULL);
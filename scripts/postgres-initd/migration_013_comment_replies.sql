@@ -0,0 +1,63 @@
+--
+-- Threaded replies on comments.
+--
+-- A comment can reply to another comment of the same post, "reply_count" keeps the number
+-- of direct replies. A deleted comment with replies becomes a tombstone: it stays in the thread
+-- without the content, so replies are not orphaned.
+--
+
+ALTER TABLE public.comments
+    ADD COLUMN IF NOT EXISTS parent_comment_id uuid REFERENCES public.comments(id);
+
+ALTER TABLE public.comments
+    ADD COLUMN IF NOT EXISTS reply_count integer NOT NULL DEFAULT 0;
+
+ALTER TABLE public.comments
+    ADD COLUMN IF NOT EXISTS tombstoned_at timestamp with time zone;
+
+CREATE INDEX IF NOT EXISTS comments_parent_comment_id_created_at_idx
+    ON public.comments (parent_comment_id, created_at, id)
+    WHERE deleted_at IS NULL;
+
+CREATE OR REPLACE FUNCTION public.update_reply_count_on_comment() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$BEGIN
+	IF (TG_OP IN ('UPDATE', 'DELETE') AND OLD.deleted_at IS NULL AND OLD.parent_comment_id IS NOT NULL) THEN
+		UPDATE comments
+			SET reply_count = reply_count - 1
+			WHERE id = OLD.parent_comment_id;
+	END IF;
+	IF (TG_OP IN ('INSERT', 'UPDATE') AND NEW.deleted_at IS NULL AND NEW.parent_comment_id IS NOT NULL) THEN
+		UPDATE comments
+			SET reply_count = reply_count + 1
+			WHERE id = NEW.parent_comment_id;
+	END IF;
+	IF (TG_OP = 'DELETE') THEN
+		RETURN OLD;
+	END IF;
+	RETURN NEW;
+END;$$;
+
+DROP TRIGGER IF EXISTS update_reply_count_on_comment_trigger ON public.comments;
+CREATE TRIGGER update_reply_count_on_comment_trigger
+    AFTER INSERT OR DELETE OR UPDATE OF deleted_at ON public.comments
+    FOR EACH ROW EXECUTE FUNCTION public.update_reply_count_on_comment();
+
+CREATE OR REPLACE VIEW public.comments_view AS
+ SELECT comments.id,
+    comments.post_id,
+    comments.user_id,
+    CASE
+        WHEN comments.tombstoned_at IS NULL THEN comments.content
+        ELSE ''::character varying(512)
+    END AS content,
+    comments.created_at,
+    comments.updated_at,
+    comments.likes,
+    comments.language::text AS language,
+    comments.reactions,
+    comments.parent_comment_id,
+    comments.reply_count,
+    (comments.tombstoned_at IS NOT NULL) AS tombstoned
+   FROM public.comments
+  WHERE (comments.deleted_at IS NULL);
@@ -0,0 +1,9 @@
+--
+-- Sorting of comments.
+--
+-- Top-level comments are paged by keyset cursors on the creation time with the id as tie-breaker.
+--
+
+CREATE INDEX IF NOT EXISTS comments_post_id_created_at_id_idx
+    ON public.comments (post_id, created_at, id)
+    WHERE deleted_at IS NULL AND parent_comment_id IS NULL;
@@ -0,0 +1,43 @@
+--
+-- Moderation of comments by post authors.
+--
+-- Authors can hide comments under their posts, the hidden comment stays visible to its author
+-- and to the post author only. Authors can pin one top-level comment per post and restrict users
+-- from commenting any of their posts, existing comments of restricted users are treated as hidden.
+--
+
+ALTER TABLE public.comments
+    ADD COLUMN IF NOT EXISTS hidden_at timestamp with time zone;
+
+CREATE TABLE IF NOT EXISTS public.pinned_comments (
+    post_id uuid NOT NULL PRIMARY KEY REFERENCES public.posts(id) ON DELETE CASCADE,
+    comment_id uuid NOT NULL UNIQUE REFERENCES public.comments(id) ON DELETE CASCADE,
+    pinned_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS public.comment_restrictions (
+    user_id uuid NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    restricted_user_id uuid NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, restricted_user_id)
+);
+
+CREATE OR REPLACE VIEW public.comments_view AS
+ SELECT comments.id,
+    comments.post_id,
+    comments.user_id,
+    CASE
+        WHEN comments.tombstoned_at IS NULL THEN comments.content
+        ELSE ''::character varying(512)
+    END AS content,
+    comments.created_at,
+    comments.updated_at,
+    comments.likes,
+    comments.language::text AS language,
+    comments.reactions,
+    comments.parent_comment_id,
+    comments.reply_count,
+    (comments.tombstoned_at IS NOT NULL) AS tombstoned,
+    (comments.hidden_at IS NOT NULL) AS hidden
+   FROM public.comments
+  WHERE (comments.deleted_at IS NULL);
@@ -0,0 +1,86 @@
+--
+-- Rich text of posts and comments.
+--
+-- Texts are stored normalized, entities (links, mentions and hashtags) are parsed on read.
+-- The markdown flag enables the safe subset of markdown when the text is rendered to HTML.
+--
+
+ALTER TABLE public.posts
+    ADD COLUMN IF NOT EXISTS markdown boolean NOT NULL DEFAULT false;
+
+ALTER TABLE public.comments
+    ADD COLUMN IF NOT EXISTS markdown boolean NOT NULL DEFAULT false;
+
+CREATE OR REPLACE VIEW public.posts_view AS
+ SELECT posts.id,
+    posts.user_id,
+    posts.content,
+    posts.description,
+    posts.likes,
+    posts.created_at,
+    posts.language::text AS language,
+    posts.reactions,
+    posts.view_count,
+    posts.content_warning,
+    posts.sensitive,
+    posts.comment_policy,
+    posts.markdown
+   FROM public.posts
+  WHERE (posts.deleted_at IS NULL AND posts.archived_at IS NULL);
+
+CREATE OR REPLACE VIEW public.archived_posts_view AS
+ SELECT posts.id,
+    posts.user_id,
+    posts.content,
+    posts.description,
+    posts.likes,
+    posts.created_at,
+    posts.language::text AS language,
+    posts.reactions,
+    posts.view_count,
+    posts.archived_at,
+    posts.content_warning,
+    posts.sensitive,
+    posts.comment_policy,
+    posts.markdown
+   FROM public.posts
+  WHERE (posts.deleted_at IS NULL AND posts.archived_at IS NOT NULL);
+
+CREATE OR REPLACE VIEW public.deleted_posts_view AS
+ SELECT posts.id,
+    posts.user_id,
+    posts.content,
+    posts.description,
+    posts.likes,
+    posts.created_at,
+    posts.language::text AS language,
+    posts.reactions,
+    posts.view_count,
+    posts.deleted_at,
+    posts.content_warning,
+    posts.sensitive,
+    posts.comment_policy,
+    posts.markdown
+   FROM public.posts
+  WHERE (posts.deleted_at IS NOT NULL);
+
+CREATE OR REPLACE VIEW public.comments_view AS
+ SELECT comments.id,
+    comments.post_id,
+    comments.user_id,
+    CASE
+        WHEN comments.tombstoned_at IS NULL THEN comments.content
+        ELSE ''::character varying(512)
+    END AS content,
+    comments.created_at,
+    comments.updated_at,
+    comments.likes,
+    comments.language::text AS language,
+    comments.reactions,
+    comments.parent_comment_id,
+    comments.reply_count,
+    (comments.tombstoned_at IS NOT NULL) AS tombstoned,
+    (comments.hidden_at IS NOT NULL) AS hidden,
+    comments.markdown
+   FROM public.comments
+  WHERE (comments.deleted_at IS NULL);
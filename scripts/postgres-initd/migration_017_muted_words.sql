@@ -0,0 +1,20 @@
+--
+-- Muted words of users.
+--
+-- Posts and comments containing a muted word or hashtag are hidden from the user or shown collapsed
+-- in the scopes chosen for the word. Words with the expiry time stop muting after it passes.
+--
+
+CREATE TABLE IF NOT EXISTS public.muted_words (
+    id uuid NOT NULL PRIMARY KEY,
+    user_id uuid NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    phrase character varying(100) NOT NULL,
+    whole_word boolean NOT NULL DEFAULT true,
+    scopes jsonb NOT NULL DEFAULT '["feed", "comments", "notifications"]'::jsonb,
+    action text NOT NULL DEFAULT 'hide',
+    expires_at timestamp with time zone,
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS muted_words_user_id_phrase_idx
+    ON public.muted_words USING btree (user_id, lower(phrase));
@@ -0,0 +1,58 @@
+--
+-- In-app notifications.
+--
+-- Notifications of the same kind about the same target are grouped while unread: a new actor
+-- joins the unread notification and moves it to the top instead of creating a new one.
+-- "actors_count" keeps the number of actors of the notification.
+--
+
+CREATE TABLE IF NOT EXISTS public.notifications (
+    id uuid NOT NULL PRIMARY KEY,
+    user_id uuid NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    type text NOT NULL,
+    post_id uuid REFERENCES public.posts(id) ON DELETE CASCADE,
+    comment_id uuid REFERENCES public.comments(id) ON DELETE CASCADE,
+    group_key text NOT NULL,
+    actors_count integer NOT NULL DEFAULT 0,
+    last_actor_id uuid REFERENCES public.users(id) ON DELETE SET NULL,
+    read_at timestamp with time zone,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS notifications_user_id_group_key_unread_idx
+    ON public.notifications (user_id, group_key)
+    WHERE read_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS notifications_user_id_updated_at_idx
+    ON public.notifications (user_id, updated_at DESC, id DESC);
+
+CREATE TABLE IF NOT EXISTS public.notification_actors (
+    notification_id uuid NOT NULL REFERENCES public.notifications(id) ON DELETE CASCADE,
+    actor_id uuid NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    created_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (notification_id, actor_id)
+);
+
+CREATE INDEX IF NOT EXISTS notification_actors_notification_id_created_at_idx
+    ON public.notification_actors (notification_id, created_at DESC);
+
+CREATE OR REPLACE FUNCTION public.update_actors_count_on_notification_actor() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$BEGIN
+	IF (TG_OP = 'DELETE') THEN
+		UPDATE notifications
+			SET actors_count = actors_count - 1
+			WHERE id = OLD.notification_id;
+		RETURN OLD;
+	END IF;
+	UPDATE notifications
+		SET actors_count = actors_count + 1
+		WHERE id = NEW.notification_id;
+	RETURN NEW;
+END;$$;
+
+DROP TRIGGER IF EXISTS update_actors_count_on_notification_actor_trigger ON public.notification_actors;
+CREATE TRIGGER update_actors_count_on_notification_actor_trigger
+    AFTER INSERT OR DELETE ON public.notification_actors
+    FOR EACH ROW EXECUTE FUNCTION public.update_actors_count_on_notification_actor();
@@ -0,0 +1,38 @@
+--
+-- Events of the real-time gateway.
+--
+-- Each published event is kept for a short time so clients can resume from the last received
+-- event after reconnecting, and is sent to all backend instances with NOTIFY on "realtime_events".
+--
+
+CREATE TABLE IF NOT EXISTS public.realtime_events (
+    id bigserial NOT NULL PRIMARY KEY,
+    topic text NOT NULL,
+    type text NOT NULL,
+    data jsonb NOT NULL DEFAULT 'null'::jsonb,
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS realtime_events_topic_id_idx
+    ON public.realtime_events (topic, id);
+
+CREATE INDEX IF NOT EXISTS realtime_events_created_at_idx
+    ON public.realtime_events (created_at);
+
+CREATE OR REPLACE FUNCTION public.notify_realtime_event() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$BEGIN
+	PERFORM pg_notify('realtime_events', json_build_object(
+		'id', NEW.id,
+		'topic', NEW.topic,
+		'type', NEW.type,
+		'data', NEW.data,
+		'created_at', NEW.created_at
+	)::text);
+	RETURN NEW;
+END;$$;
+
+DROP TRIGGER IF EXISTS notify_realtime_event_trigger ON public.realtime_events;
+CREATE TRIGGER notify_realtime_event_trigger
+    AFTER INSERT ON public.realtime_events
+    FOR EACH ROW EXECUTE FUNCTION public.notify_realtime_event();
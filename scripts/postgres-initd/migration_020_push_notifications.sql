@@ -0,0 +1,43 @@
+--
+-- Web Push notifications.
+--
+-- Browsers of the user register push subscriptions, notifications are queued for delivery
+-- to each subscription of the user who hasn't opted out of their type. Deliveries are retried
+-- until they succeed or run out of attempts, expired subscriptions are deleted with their deliveries.
+--
+
+CREATE TABLE IF NOT EXISTS public.push_subscriptions (
+    id uuid NOT NULL PRIMARY KEY,
+    user_id uuid NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    endpoint text NOT NULL,
+    p256dh text NOT NULL,
+    auth text NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS push_subscriptions_endpoint_idx
+    ON public.push_subscriptions USING btree (endpoint);
+
+CREATE INDEX IF NOT EXISTS push_subscriptions_user_id_idx
+    ON public.push_subscriptions USING btree (user_id);
+
+CREATE TABLE IF NOT EXISTS public.push_opt_outs (
+    user_id uuid NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    type text NOT NULL,
+    PRIMARY KEY (user_id, type)
+);
+
+CREATE TABLE IF NOT EXISTS public.push_deliveries (
+    id uuid NOT NULL PRIMARY KEY,
+    subscription_id uuid NOT NULL REFERENCES public.push_subscriptions(id) ON DELETE CASCADE,
+    notification_id uuid NOT NULL REFERENCES public.notifications(id) ON DELETE CASCADE,
+    attempts integer NOT NULL DEFAULT 0,
+    next_attempt_at timestamp with time zone NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS push_deliveries_subscription_id_notification_id_idx
+    ON public.push_deliveries USING btree (subscription_id, notification_id);
+
+CREATE INDEX IF NOT EXISTS push_deliveries_next_attempt_at_idx
+    ON public.push_deliveries USING btree (next_attempt_at);
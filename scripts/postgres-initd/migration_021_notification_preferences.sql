@@ -0,0 +1,37 @@
+--
+-- Notification preferences and email digests.
+--
+-- Users opt out of notifications of each type per channel: in the app, push and email.
+-- Push notifications are delayed during quiet hours in the timezone of the user, the email digest
+-- of unread notifications is sent daily or weekly. Quiet hours are minutes since the local midnight.
+--
+
+CREATE TABLE IF NOT EXISTS public.notification_opt_outs (
+    user_id uuid NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    type text NOT NULL,
+    channel text NOT NULL,
+    PRIMARY KEY (user_id, type, channel)
+);
+
+DO $$BEGIN
+	IF EXISTS (SELECT 1 FROM information_schema.tables WHERE table_schema = 'public' AND table_name = 'push_opt_outs') THEN
+		INSERT INTO public.notification_opt_outs (user_id, type, channel)
+			SELECT user_id, type, 'push'
+			FROM public.push_opt_outs
+				ON CONFLICT DO NOTHING;
+		DROP TABLE public.push_opt_outs;
+	END IF;
+END;$$;
+
+CREATE TABLE IF NOT EXISTS public.notification_settings (
+    user_id uuid NOT NULL PRIMARY KEY REFERENCES public.users(id) ON DELETE CASCADE,
+    timezone text NOT NULL DEFAULT 'UTC',
+    quiet_hours_start smallint CHECK (quiet_hours_start BETWEEN 0 AND 1439),
+    quiet_hours_end smallint CHECK (quiet_hours_end BETWEEN 0 AND 1439),
+    digest text NOT NULL DEFAULT 'off',
+    last_digest_at timestamp with time zone
+);
+
+CREATE INDEX IF NOT EXISTS notification_settings_digest_idx
+    ON public.notification_settings USING btree (last_digest_at)
+    WHERE digest <> 'off';
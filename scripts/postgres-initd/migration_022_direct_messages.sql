@@ -0,0 +1,55 @@
+--
+-- One-to-one direct messages.
+--
+-- "direct_key" is both user ids joined in ascending order, so each pair of users has one conversation.
+-- "updated_at" of the conversation is the time of its last message, conversations are listed by it.
+-- Members who don't follow the user who started the conversation get it as a message request:
+-- their "status" is 'request' until they accept it, reply to it or decline it.
+-- "last_read_at" is the time of the last message the member has read.
+--
+
+CREATE TABLE IF NOT EXISTS public.conversations (
+    id uuid NOT NULL PRIMARY KEY,
+    type text NOT NULL DEFAULT 'direct',
+    direct_key text UNIQUE,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS public.conversation_members (
+    conversation_id uuid NOT NULL REFERENCES public.conversations(id) ON DELETE CASCADE,
+    user_id uuid NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    status text NOT NULL DEFAULT 'accepted',
+    last_read_at timestamp with time zone,
+    joined_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (conversation_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS conversation_members_user_id_status_idx
+    ON public.conversation_members (user_id, status);
+
+CREATE TABLE IF NOT EXISTS public.messages (
+    id uuid NOT NULL PRIMARY KEY,
+    conversation_id uuid NOT NULL REFERENCES public.conversations(id) ON DELETE CASCADE,
+    sender_id uuid REFERENCES public.users(id) ON DELETE SET NULL,
+    content text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS messages_conversation_id_created_at_idx
+    ON public.messages (conversation_id, created_at DESC, id DESC);
+
+CREATE OR REPLACE FUNCTION public.update_conversation_on_message() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$BEGIN
+	UPDATE conversations
+		SET updated_at = GREATEST(updated_at, NEW.created_at)
+		WHERE id = NEW.conversation_id;
+	RETURN NEW;
+END;$$;
+
+DROP TRIGGER IF EXISTS update_conversation_on_message_trigger ON public.messages;
+CREATE TRIGGER update_conversation_on_message_trigger
+    AFTER INSERT ON public.messages
+    FOR EACH ROW EXECUTE FUNCTION public.update_conversation_on_message();
@@ -0,0 +1,20 @@
+--
+-- Group conversations.
+--
+-- Groups have a name and members with roles: the owner, admins and members.
+-- Changes of membership are recorded in the conversation as system messages with "event"
+-- and "target_user_id", the sender is the member who made the change.
+-- Members see messages sent since they joined and can mute the conversation for themselves.
+--
+
+ALTER TABLE public.conversations
+    ADD COLUMN IF NOT EXISTS name text;
+
+ALTER TABLE public.conversation_members
+    ADD COLUMN IF NOT EXISTS role text NOT NULL DEFAULT 'member',
+    ADD COLUMN IF NOT EXISTS muted boolean NOT NULL DEFAULT false;
+
+ALTER TABLE public.messages
+    ADD COLUMN IF NOT EXISTS type text NOT NULL DEFAULT 'text',
+    ADD COLUMN IF NOT EXISTS event text,
+    ADD COLUMN IF NOT EXISTS target_user_id uuid REFERENCES public.users(id) ON DELETE SET NULL;
@@ -0,0 +1,23 @@
+--
+-- Images attached to messages.
+--
+-- Attachments are uploaded before the message is sent and are kept outside of the public data routes,
+-- so only members of the conversation can get them. "message_id" is null until the uploader
+-- attaches them to the message, unused attachments and attachments of deleted messages
+-- are deleted with their files after the retention.
+--
+
+CREATE TABLE IF NOT EXISTS public.message_attachments (
+    id uuid NOT NULL PRIMARY KEY,
+    user_id uuid NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    message_id uuid REFERENCES public.messages(id) ON DELETE SET NULL,
+    filename text NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS message_attachments_message_id_idx
+    ON public.message_attachments (message_id);
+
+CREATE INDEX IF NOT EXISTS message_attachments_unused_created_at_idx
+    ON public.message_attachments (created_at)
+    WHERE message_id IS NULL;
@@ -0,0 +1,40 @@
+--
+-- Ids of real-time events in the order of commit.
+--
+-- Ids given on insert follow the start of inserting transactions, so an event committed later
+-- could get a lower id than the events already received by clients and would be skipped by them.
+-- The id is given again right before the commit while holding the lock until the commit ends,
+-- so events are committed and sent on NOTIFY in the order of their ids.
+--
+
+CREATE OR REPLACE FUNCTION public.notify_realtime_event() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$DECLARE
+	committed_id bigint;
+BEGIN
+	PERFORM pg_advisory_xact_lock(hashtext('realtime_events'));
+
+	UPDATE public.realtime_events
+	SET id = nextval(pg_get_serial_sequence('public.realtime_events', 'id'))
+	WHERE id = NEW.id
+	RETURNING id INTO committed_id;
+
+	IF committed_id IS NULL THEN
+		RETURN NULL;
+	END IF;
+
+	PERFORM pg_notify('realtime_events', json_build_object(
+		'id', committed_id,
+		'topic', NEW.topic,
+		'type', NEW.type,
+		'data', NEW.data,
+		'created_at', NEW.created_at
+	)::text);
+	RETURN NULL;
+END;$$;
+
+DROP TRIGGER IF EXISTS notify_realtime_event_trigger ON public.realtime_events;
+CREATE CONSTRAINT TRIGGER notify_realtime_event_trigger
+    AFTER INSERT ON public.realtime_events
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION public.notify_realtime_event();
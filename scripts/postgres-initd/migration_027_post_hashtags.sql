@@ -0,0 +1,53 @@
+--
+-- Hashtags of posts for prefix search of hashtags.
+--
+-- Hashtags are taken from entities of the post description stored on write, so the search
+-- doesn't scan descriptions of all posts. Hashtags of posts written before entities were stored
+-- are taken from the description once and replaced when their entities are saved.
+--
+
+CREATE TABLE IF NOT EXISTS public.post_hashtags (
+    post_id uuid NOT NULL REFERENCES public.posts(id) ON DELETE CASCADE,
+    tag text NOT NULL,
+    PRIMARY KEY (post_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS post_hashtags_tag_idx
+    ON public.post_hashtags (tag text_pattern_ops);
+
+CREATE OR REPLACE FUNCTION public.update_post_hashtags() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$BEGIN
+	IF NEW.entities IS NULL THEN
+		RETURN NULL;
+	END IF;
+
+	DELETE FROM public.post_hashtags WHERE post_id = NEW.id;
+
+	INSERT INTO public.post_hashtags (post_id, tag)
+	SELECT DISTINCT NEW.id, entity->>'target'
+	FROM jsonb_array_elements(NEW.entities) AS entity
+	WHERE entity->>'type' = 'hashtag';
+
+	RETURN NULL;
+END;$$;
+
+DROP TRIGGER IF EXISTS update_post_hashtags_trigger ON public.posts;
+CREATE TRIGGER update_post_hashtags_trigger
+    AFTER INSERT OR UPDATE OF entities ON public.posts
+    FOR EACH ROW EXECUTE FUNCTION public.update_post_hashtags();
+
+INSERT INTO public.post_hashtags (post_id, tag)
+SELECT DISTINCT posts.id, entity->>'target'
+FROM public.posts
+CROSS JOIN LATERAL jsonb_array_elements(posts.entities) AS entity
+WHERE posts.entities IS NOT NULL
+AND entity->>'type' = 'hashtag'
+ON CONFLICT DO NOTHING;
+
+INSERT INTO public.post_hashtags (post_id, tag)
+SELECT DISTINCT posts.id, lower(hashtag[1])
+FROM public.posts
+CROSS JOIN LATERAL regexp_matches(posts.description, '#(\w+)', 'g') AS hashtag
+WHERE posts.entities IS NULL
+ON CONFLICT DO NOTHING;
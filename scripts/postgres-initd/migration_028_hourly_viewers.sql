@@ -0,0 +1,14 @@
+--
+-- Distinct viewers of posts by hours.
+--
+-- Viewers are kept for each hour they viewed the post in, so unique viewers of an hour
+-- and of any period count each user once. Unique viewers of hours rolled up before
+-- count only users who viewed the post for the first time.
+--
+
+CREATE TABLE IF NOT EXISTS public.post_viewers_hourly (
+    post_id uuid NOT NULL REFERENCES public.posts(id) ON DELETE CASCADE,
+    hour timestamp with time zone NOT NULL,
+    user_id uuid NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    PRIMARY KEY (post_id, hour, user_id)
+);
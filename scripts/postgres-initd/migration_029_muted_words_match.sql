@@ -0,0 +1,19 @@
+--
+-- Matching of muted words in queries.
+--
+-- Posts hidden by muted words are skipped by queries, so pages of posts are filled
+-- and counts of posts match the fetched posts. The phrase matches ignoring case,
+-- with whole_word it matches only when it is not a part of a longer word.
+--
+
+CREATE OR REPLACE FUNCTION public.matches_muted_word(content text, phrase text, whole_word boolean) RETURNS boolean
+    LANGUAGE sql IMMUTABLE
+    AS $$SELECT CASE
+		WHEN phrase = '' THEN false
+		WHEN whole_word THEN content ~* (
+			'(^|[^[:alnum:]_])'
+			|| regexp_replace(phrase, '([^[:alnum:][:space:]_])', '\\\1', 'g')
+			|| '($|[^[:alnum:]_])'
+		)
+		ELSE strpos(lower(content), lower(phrase)) > 0
+	END$$;